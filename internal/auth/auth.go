@@ -13,22 +13,25 @@ import (
 
 type AuthMiddleware interface {
 	Authentication() gin.HandlerFunc
+	Authorization(permission string) gin.HandlerFunc
 	validateToken(userAdmin *domain.UserAdmin, bearerToken string) error
 	bindTokenToUserEntity(userAdmin *domain.UserAdmin, claim jwt.MapClaims) domain.MessageErr
 	parseToken(tokenString string) (*jwt.Token, domain.MessageErr)
 }
 
 type authMiddleware struct {
-	db                  *sql.DB
-	jwtSecret           string
-	userAdminRepository repository.UserAdminRepository
+	db                     *sql.DB
+	jwtSecret              string
+	userAdminRepository    repository.UserAdminRepository
+	staffSessionRepository repository.StaffSessionRepository
 }
 
-func NewAuthMiddleware(db *sql.DB, jwtSecret string, userAdminRepository repository.UserAdminRepository) AuthMiddleware {
+func NewAuthMiddleware(db *sql.DB, jwtSecret string, userAdminRepository repository.UserAdminRepository, staffSessionRepository repository.StaffSessionRepository) AuthMiddleware {
 	return &authMiddleware{
-		db:                  db,
-		jwtSecret:           jwtSecret,
-		userAdminRepository: userAdminRepository,
+		db:                     db,
+		jwtSecret:              jwtSecret,
+		userAdminRepository:    userAdminRepository,
+		staffSessionRepository: staffSessionRepository,
 	}
 }
 
@@ -51,13 +54,36 @@ func (a *authMiddleware) Authentication() gin.HandlerFunc {
 			return
 		}
 
-		_, err = a.userAdminRepository.GetUserByPhoneNumberRepository(ctx, a.db, user.PhoneNumber)
-		if err != nil {
+		// logging out revokes the session before its access tokens expire
+		session, err := a.staffSessionRepository.GetStaffSessionByID(ctx, a.db, user.SessionID)
+		if err != nil || session == nil || session.RevokedAt != nil || session.UserAdminID != user.ID {
+			ctx.AbortWithStatusJSON(invalidTokenErr.Status(), invalidTokenErr)
+			return
+		}
+
+		userAdmin, err := a.userAdminRepository.GetUserByPhoneNumberRepository(ctx, a.db, user.PhoneNumber)
+		if err != nil || userAdmin.ID != user.ID {
 			ctx.AbortWithStatusJSON(invalidTokenErr.Status(), invalidTokenErr)
 			return
 		}
+		userAdmin.Password = ""
+		userAdmin.SessionID = session.ID
+
+		ctx.Set("userData", *userAdmin)
+		ctx.Next()
+	}
+}
+
+// runs after Authentication
+func (a *authMiddleware) Authorization(permission string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userAdmin := ctx.MustGet("userData").(domain.UserAdmin)
+		if !domain.HasPermission(userAdmin.Role, permission) {
+			forbiddenErr := domain.NewForbiddenError("staff is not allowed to do this")
+			ctx.AbortWithStatusJSON(forbiddenErr.Status(), forbiddenErr)
+			return
+		}
 
-		ctx.Set("userData", user)
 		ctx.Next()
 	}
 }
@@ -128,5 +154,11 @@ func (a *authMiddleware) bindTokenToUserEntity(userAdmin *domain.UserAdmin, clai
 	}
 	userAdmin.PhoneNumber = phoneNumber
 
+	sessionID, ok := claim["sessionId"].(string)
+	if !ok {
+		return domain.NewUnauthenticatedError("invalid token")
+	}
+	userAdmin.SessionID = sessionID
+
 	return nil
 }
@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"eniqilo-store/internal/domain"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAuthorization(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	a := NewAuthMiddleware(nil, "test-secret", nil, nil)
+
+	tests := []struct {
+		name       string
+		role       string
+		permission string
+		wantStatus int
+	}{
+		{name: "cashier checks out", role: domain.UserAdminRoleCashier, permission: domain.PermissionCheckoutCreate, wantStatus: http.StatusOK},
+		{name: "cashier changes prices", role: domain.UserAdminRoleCashier, permission: domain.PermissionPricingWrite, wantStatus: http.StatusForbidden},
+		{name: "manager voids any sale", role: domain.UserAdminRoleManager, permission: domain.PermissionCheckoutVoidAny, wantStatus: http.StatusOK},
+		{name: "manager manages staff", role: domain.UserAdminRoleManager, permission: domain.PermissionStaffManage, wantStatus: http.StatusForbidden},
+		{name: "owner manages staff", role: domain.UserAdminRoleOwner, permission: domain.PermissionStaffManage, wantStatus: http.StatusOK},
+		{name: "unknown role", role: "staff", permission: domain.PermissionProductRead, wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/",
+				func(ctx *gin.Context) { ctx.Set("userData", domain.UserAdmin{Role: tt.role}) },
+				a.Authorization(tt.permission),
+				func(ctx *gin.Context) { ctx.Status(http.StatusOK) },
+			)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+		})
+	}
+}
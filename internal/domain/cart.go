@@ -0,0 +1,199 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	CartStatusOpen       = "open"
+	CartStatusParked     = "parked"
+	CartStatusCheckedOut = "checked_out"
+)
+
+var CartIdempotencyKeyPrefix = "cart:"
+
+type Cart struct {
+	ID             string    `db:"id"`
+	Sid            int       `db:"sid"`
+	CreatedAt      time.Time `db:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at"`
+	Till           string    `db:"till"`
+	Label          *string   `db:"label"`
+	UserCustomerID *string   `db:"user_customer_id"`
+	UserAdminID    string    `db:"user_admin_id"`
+	Status         string    `db:"status"`
+	CheckoutID     *string   `db:"checkout_id"`
+}
+
+type CartItem struct {
+	ID          string    `db:"id"`
+	Sid         int       `db:"sid"`
+	CreatedAt   time.Time `db:"created_at"`
+	CartID      string    `db:"cart_id"`
+	ProductID   string    `db:"product_id"`
+	Quantity    int       `db:"quantity"`
+	ProductName string
+	ProductSku  string
+	Price       int
+}
+
+type CartRequest struct {
+	UserAdminID string `json:"-"`
+	Till        string `json:"till" binding:"required,gte=1,lte=50"`
+	CustomerID  string `json:"customerId" binding:"omitempty,uuid"`
+	Label       string `json:"label" binding:"omitempty,lte=100"`
+}
+
+type CartItemRequest struct {
+	ProductID string `json:"productId" binding:"required"`
+	Quantity  int    `json:"quantity" binding:"required,min=1,number"`
+}
+
+type CartCustomerRequest struct {
+	CustomerID string `json:"customerId" binding:"required,uuid"`
+}
+
+type CartParkRequest struct {
+	Label string `json:"label" binding:"omitempty,lte=100"`
+}
+
+type CartResumeRequest struct {
+	Till string `json:"till" binding:"omitempty,lte=50"`
+}
+
+type CartCheckoutRequest struct {
+	UserAdminID string           `json:"-"`
+	Payments    []PaymentRequest `json:"payments" binding:"omitempty,dive"`
+	Paid        int              `json:"paid" binding:"omitempty,min=1,number"`
+	CouponCode  string           `json:"couponCode" binding:"omitempty,lte=30"`
+	Change      *int             `json:"change" binding:"required,min=0,number"`
+}
+
+type CartQueryParams struct {
+	Till   string `form:"till"`
+	Status string `form:"status"`
+}
+
+type CartItemResponse struct {
+	ProductID string `json:"productId"`
+	Name      string `json:"name"`
+	Sku       string `json:"sku"`
+	Price     int    `json:"price"`
+	Quantity  int    `json:"quantity"`
+}
+
+type CartResponse struct {
+	ID            string             `json:"id"`
+	CreatedAt     time.Time          `json:"createdAt"`
+	UpdatedAt     time.Time          `json:"updatedAt"`
+	Till          string             `json:"till"`
+	Label         string             `json:"label,omitempty"`
+	CustomerID    string             `json:"customerId,omitempty"`
+	CashierID     string             `json:"cashierId"`
+	Status        string             `json:"status"`
+	TransactionID string             `json:"transactionId,omitempty"`
+	Items         []CartItemResponse `json:"items"`
+}
+
+func (cr *CartRequest) NewCart() Cart {
+	id := uuid.New()
+	rawCreatedAt := time.Now().Format(time.RFC3339)
+	createdAt, _ := time.Parse(time.RFC3339, rawCreatedAt)
+
+	cart := Cart{
+		ID:          id.String(),
+		CreatedAt:   createdAt,
+		UpdatedAt:   createdAt,
+		Till:        cr.Till,
+		UserAdminID: cr.UserAdminID,
+		Status:      CartStatusOpen,
+	}
+	if len(cr.Label) > 0 {
+		cart.Label = &cr.Label
+	}
+	if len(cr.CustomerID) > 0 {
+		cart.UserCustomerID = &cr.CustomerID
+	}
+
+	return cart
+}
+
+func (cr *CartItemRequest) NewCartItem(cartID string) CartItem {
+	id := uuid.New()
+	rawCreatedAt := time.Now().Format(time.RFC3339)
+	createdAt, _ := time.Parse(time.RFC3339, rawCreatedAt)
+
+	return CartItem{
+		ID:        id.String(),
+		CreatedAt: createdAt,
+		CartID:    cartID,
+		ProductID: cr.ProductID,
+		Quantity:  cr.Quantity,
+	}
+}
+
+// carts expire counting from their last use
+func (c *Cart) Touch() {
+	rawUpdatedAt := time.Now().Format(time.RFC3339)
+	c.UpdatedAt, _ = time.Parse(time.RFC3339, rawUpdatedAt)
+}
+
+func (c *Cart) IdempotencyKey() string {
+	return CartIdempotencyKeyPrefix + c.ID
+}
+
+func (c *Cart) NewCheckoutRequest(items []CartItem, body CartCheckoutRequest) CheckoutRequest {
+	checkout := CheckoutRequest{
+		UserAdminID: body.UserAdminID,
+		Payments:    body.Payments,
+		Paid:        body.Paid,
+		CouponCode:  body.CouponCode,
+		Change:      body.Change,
+	}
+	if c.UserCustomerID != nil {
+		checkout.CustomerID = *c.UserCustomerID
+	}
+	for _, i := range items {
+		checkout.ProductDetails = append(checkout.ProductDetails, ProductCheckoutRequest{
+			ProductID: i.ProductID,
+			Quantity:  i.Quantity,
+		})
+	}
+
+	return checkout
+}
+
+func (c *Cart) NewCartResponse(items []CartItem) CartResponse {
+	response := CartResponse{
+		ID:        c.ID,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+		Till:      c.Till,
+		CashierID: c.UserAdminID,
+		Status:    c.Status,
+		Items:     []CartItemResponse{},
+	}
+	if c.Label != nil {
+		response.Label = *c.Label
+	}
+	if c.UserCustomerID != nil {
+		response.CustomerID = *c.UserCustomerID
+	}
+	if c.CheckoutID != nil {
+		response.TransactionID = *c.CheckoutID
+	}
+
+	for _, i := range items {
+		response.Items = append(response.Items, CartItemResponse{
+			ProductID: i.ProductID,
+			Name:      i.ProductName,
+			Sku:       i.ProductSku,
+			Price:     i.Price,
+			Quantity:  i.Quantity,
+		})
+	}
+
+	return response
+}
@@ -0,0 +1,56 @@
+package domain
+
+import "testing"
+
+func TestCartNewCheckoutRequest(t *testing.T) {
+	customerID := "customer"
+	change := 0
+
+	tests := []struct {
+		name           string
+		cart           Cart
+		items          []CartItem
+		wantCustomerID string
+	}{
+		{
+			name:           "with customer",
+			cart:           Cart{ID: "cart", UserCustomerID: &customerID},
+			items:          []CartItem{{ProductID: "a", Quantity: 2}, {ProductID: "b", Quantity: 1}},
+			wantCustomerID: customerID,
+		},
+		{
+			name:  "without customer",
+			cart:  Cart{ID: "cart"},
+			items: []CartItem{{ProductID: "a", Quantity: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := CartCheckoutRequest{UserAdminID: "cashier", Paid: 5000, CouponCode: "SAVE10", Change: &change}
+			request := tt.cart.NewCheckoutRequest(tt.items, body)
+
+			if request.CustomerID != tt.wantCustomerID {
+				t.Errorf("customerId = %q, want %q", request.CustomerID, tt.wantCustomerID)
+			}
+			if request.UserAdminID != body.UserAdminID || request.Paid != body.Paid || request.CouponCode != body.CouponCode || request.Change != body.Change {
+				t.Errorf("request = %+v doesn't carry the checkout body", request)
+			}
+			if len(request.ProductDetails) != len(tt.items) {
+				t.Fatalf("got %d product details, want %d", len(request.ProductDetails), len(tt.items))
+			}
+			for i, pd := range request.ProductDetails {
+				if pd.ProductID != tt.items[i].ProductID || pd.Quantity != tt.items[i].Quantity {
+					t.Errorf("productDetails[%d] = %+v, want %+v", i, pd, tt.items[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCartIdempotencyKey(t *testing.T) {
+	cart := Cart{ID: "6f1d6c3e-8a57-4a4e-9f0b-3b2a9d1c7e21"}
+	if got, want := cart.IdempotencyKey(), CartIdempotencyKeyPrefix+cart.ID; got != want {
+		t.Errorf("IdempotencyKey() = %q, want %q", got, want)
+	}
+}
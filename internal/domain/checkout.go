@@ -1,26 +1,54 @@
 package domain
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+var (
+	CheckoutTypeSale   = "sale"
+	CheckoutTypeReturn = "return"
+)
+
 type Checkout struct {
-	ID             string    `db:"id"`
-	Sid            int       `db:"sid"`
-	CreatedAt      time.Time `db:"created_at"`
-	UserCustomerID string    `db:"user_customer_id"`
-	Paid           int       `db:"paid"`
-	Change         *int      `db:"change"`
+	ID             string     `db:"id"`
+	Sid            int        `db:"sid"`
+	CreatedAt      time.Time  `db:"created_at"`
+	UserCustomerID string     `db:"user_customer_id"`
+	UserAdminID    string     `db:"user_admin_id"`
+	ShiftID        *string    `db:"shift_id"`
+	Type           string     `db:"type"`
+	ReturnOf       *string    `db:"return_of"`
+	ReturnReason   *string    `db:"return_reason"`
+	Paid           int        `db:"paid"`
+	Change         *int       `db:"change"`
+	Subtotal       int        `db:"subtotal"`
+	Discount       int        `db:"discount"`
+	Tax            int        `db:"tax"`
+	Total          int        `db:"total"`
+	VoidedAt       *time.Time `db:"voided_at"`
+	VoidedBy       *string    `db:"voided_by"`
+	VoidReason     *string    `db:"void_reason"`
 }
 
 type ProductCheckout struct {
-	ID         string `db:"id"`
-	Sid        int    `db:"sid"`
-	ProductID  string `db:"product_id"`
-	Quantity   int    `db:"quantity"`
-	CheckoutID string `db:"checkout_id"`
+	ID           string  `db:"id"`
+	Sid          int     `db:"sid"`
+	ProductID    string  `db:"product_id"`
+	Quantity     int     `db:"quantity"`
+	CheckoutID   string  `db:"checkout_id"`
+	ProductName  string  `db:"product_name"`
+	ProductSku   string  `db:"product_sku"`
+	Price        int     `db:"price"`
+	Discount     int     `db:"discount"`
+	RestockState *string `db:"restock_state"`
+	Tax          int     `db:"tax"`
+	TaxRate      float64 `db:"tax_rate"`
+	TaxInclusive bool    `db:"tax_inclusive"`
+	Total        int     `db:"total"`
+	Promotions   []AppliedPromotion
 }
 
 type ProductCheckoutRequest struct {
@@ -29,49 +57,232 @@ type ProductCheckoutRequest struct {
 }
 
 type CheckoutRequest struct {
+	UserAdminID    string                   `json:"-"`
 	CustomerID     string                   `json:"customerId" binding:"required"`
 	ProductDetails []ProductCheckoutRequest `json:"productDetails" binding:"required,min=1,dive"`
-	Paid           int                      `json:"paid" binding:"required,min=1,number"`
+	Payments       []PaymentRequest         `json:"payments" binding:"omitempty,dive"`
+	Paid           int                      `json:"paid" binding:"omitempty,min=1,number"`
+	CouponCode     string                   `json:"couponCode" binding:"omitempty,lte=30"`
 	Change         *int                     `json:"change" binding:"required,min=0,number"`
 }
 
-type CheckoutResponse struct {
-	ID        string    `json:"id"`
-	CreatedAt time.Time `json:"createdAt"`
+type CheckoutQuoteRequest struct {
+	CustomerID     string                   `json:"customerId" binding:"omitempty"`
+	ProductDetails []ProductCheckoutRequest `json:"productDetails" binding:"required,min=1,dive"`
+	Payments       []PaymentRequest         `json:"payments" binding:"omitempty,dive"`
+	Paid           int                      `json:"paid" binding:"omitempty,min=1,number"`
+	CouponCode     string                   `json:"couponCode" binding:"omitempty,lte=30"`
 }
 
-type GetCheckoutHistory struct {
-	TransactionID string    `json:"transactionId"`
-	CreatedAt     time.Time `json:"createdAt"`
-	CustomerID    string    `json:"customerId"`
-	ProductID     string    `json:"productId"`
-	Quantity      int       `json:"quantity"`
-	Paid          int       `json:"paid"`
-	Change        int       `json:"change"`
+type CheckoutQuoteResponse struct {
+	ProductDetails []CheckoutProductDetailResponse `json:"productDetails"`
+	Subtotal       int                             `json:"subtotal"`
+	Discount       int                             `json:"discount"`
+	Tax            int                             `json:"tax"`
+	Total          int                             `json:"total"`
+	Paid           int                             `json:"paid"`
+	Change         int                             `json:"change"`
 }
 
-type ProductCheckoutResponse struct {
-	ProductID string `json:"productId"`
-	Quantity  int    `json:"quantity"`
+type CheckoutProductDetailResponse struct {
+	ID           string                     `json:"-"`
+	ProductID    string                     `json:"productId"`
+	Name         string                     `json:"name"`
+	Sku          string                     `json:"sku"`
+	Quantity     int                        `json:"quantity"`
+	Price        int                        `json:"price"`
+	Discount     int                        `json:"discount"`
+	Promotions   []AppliedPromotionResponse `json:"promotions"`
+	Tax          int                        `json:"tax"`
+	TaxRate      float64                    `json:"taxRate"`
+	TaxInclusive bool                       `json:"taxInclusive"`
+	Total        int                        `json:"total"`
+	RestockState string                     `json:"restockState,omitempty"`
 }
 
-type GetCheckoutHistoryResponse struct {
-	TransactionID  string                    `json:"transactionId"`
-	CreatedAt      time.Time                 `json:"createdAt"`
-	CustomerID     string                    `json:"customerId"`
-	ProductDetails []ProductCheckoutResponse `json:"productDetails" db:"product_details"`
-	Paid           int                       `json:"paid"`
-	Change         int                       `json:"change"`
+type CheckoutResponse struct {
+	TransactionID  string                          `json:"transactionId"`
+	CreatedAt      time.Time                       `json:"createdAt"`
+	CustomerID     string                          `json:"customerId"`
+	CashierID      string                          `json:"cashierId"`
+	ShiftID        string                          `json:"shiftId,omitempty"`
+	Type           string                          `json:"type"`
+	ReturnOf       string                          `json:"returnOf,omitempty"`
+	ReturnReason   string                          `json:"returnReason,omitempty"`
+	ProductDetails []CheckoutProductDetailResponse `json:"productDetails"`
+	Subtotal       int                             `json:"subtotal"`
+	Discount       int                             `json:"discount"`
+	Tax            int                             `json:"tax"`
+	Total          int                             `json:"total"`
+	Paid           int                             `json:"paid"`
+	Change         int                             `json:"change"`
+	Payments       []PaymentResponse               `json:"payments"`
+	VoidedAt       *time.Time                      `json:"voidedAt,omitempty"`
+	VoidedBy       string                          `json:"voidedBy,omitempty"`
+	VoidReason     string                          `json:"voidReason,omitempty"`
+}
+
+type GetCheckoutHistory struct {
+	TransactionID     string     `json:"transactionId"`
+	ProductCheckoutID string     `json:"productCheckoutId"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	CustomerID        string     `json:"customerId"`
+	CashierID         string     `json:"cashierId"`
+	ShiftID           string     `json:"shiftId"`
+	Type              string     `json:"type"`
+	ReturnOf          string     `json:"returnOf"`
+	ReturnReason      string     `json:"returnReason"`
+	ProductID         string     `json:"productId"`
+	ProductName       string     `json:"name"`
+	ProductSku        string     `json:"sku"`
+	Quantity          int        `json:"quantity"`
+	Price             int        `json:"price"`
+	RestockState      string     `json:"restockState"`
+	LineDiscount      int        `json:"lineDiscount"`
+	LineTax           int        `json:"lineTax"`
+	TaxRate           float64    `json:"taxRate"`
+	TaxInclusive      bool       `json:"taxInclusive"`
+	LineTotal         int        `json:"lineTotal"`
+	Subtotal          int        `json:"subtotal"`
+	Discount          int        `json:"discount"`
+	Tax               int        `json:"tax"`
+	Total             int        `json:"total"`
+	Paid              int        `json:"paid"`
+	Change            int        `json:"change"`
+	VoidedAt          *time.Time `json:"voidedAt"`
+	VoidedBy          string     `json:"voidedBy"`
+	VoidReason        string     `json:"voidReason"`
 }
 
 type CheckoutHistoryQueryParams struct {
 	CustomerId string `form:"customerId"`
+	CashierId  string `form:"cashierId"`
+	Type       string `form:"type"`
+	ProductId  string `form:"productId"`
+	From       string `form:"from"`
+	To         string `form:"to"`
+	MinTotal   string `form:"minTotal"`
+	MaxTotal   string `form:"maxTotal"`
 	Limit      string `form:"limit"`
 	Offset     string `form:"offset"`
 	CreatedAt  string `form:"createdAt"`
 }
 
-func (cr *CheckoutRequest) NewCheckouts() (Checkout, []ProductCheckout) {
+func (cr *CheckoutRequest) PaymentRequests() []PaymentRequest {
+	if len(cr.Payments) > 0 {
+		return cr.Payments
+	}
+
+	// clients without a payments breakdown pay everything in cash
+	return []PaymentRequest{
+		{
+			Method: PaymentMethodCash,
+			Amount: cr.Paid,
+		},
+	}
+}
+
+func MergeProductDetails(productDetails []ProductCheckoutRequest) []ProductCheckoutRequest {
+	var merged []ProductCheckoutRequest
+	productIndex := map[string]int{}
+	for _, v := range productDetails {
+		if idx, ok := productIndex[v.ProductID]; ok {
+			merged[idx].Quantity += v.Quantity
+			continue
+		}
+
+		productIndex[v.ProductID] = len(merged)
+		merged = append(merged, v)
+	}
+
+	return merged
+}
+
+func (qr *CheckoutQuoteRequest) PaymentRequests() []PaymentRequest {
+	if len(qr.Payments) > 0 || qr.Paid == 0 {
+		return qr.Payments
+	}
+
+	return []PaymentRequest{
+		{
+			Method: PaymentMethodCash,
+			Amount: qr.Paid,
+		},
+	}
+}
+
+func (qr *CheckoutQuoteRequest) NewProductCheckouts() []ProductCheckout {
+	var productCheckouts []ProductCheckout
+	for _, v := range MergeProductDetails(qr.ProductDetails) {
+		productCheckouts = append(productCheckouts, ProductCheckout{
+			ProductID: v.ProductID,
+			Quantity:  v.Quantity,
+		})
+	}
+
+	return productCheckouts
+}
+
+func NewCheckoutQuoteResponse(productCheckouts []ProductCheckout) CheckoutQuoteResponse {
+	quote := CheckoutQuoteResponse{
+		ProductDetails: []CheckoutProductDetailResponse{},
+	}
+	quote.Subtotal, quote.Discount, quote.Tax, quote.Total = SumProductCheckouts(productCheckouts)
+
+	for _, pc := range productCheckouts {
+		quote.ProductDetails = append(quote.ProductDetails, pc.NewProductDetailResponse())
+	}
+
+	return quote
+}
+
+func (pc *ProductCheckout) ApplyTax(taxRate *TaxRate) {
+	pc.Tax, pc.TaxRate, pc.TaxInclusive = 0, 0, false
+	pc.Total = pc.Price*pc.Quantity - pc.Discount
+	if taxRate == nil {
+		return
+	}
+
+	pc.TaxRate = taxRate.Rate
+	pc.TaxInclusive = taxRate.Inclusive
+	pc.Tax, pc.Total = taxRate.Apply(pc.Total)
+}
+
+func SumProductCheckouts(productCheckouts []ProductCheckout) (int, int, int, int) {
+	var subtotal, discount, tax, total int
+	for _, pc := range productCheckouts {
+		subtotal += pc.Price * pc.Quantity
+		discount += pc.Discount
+		tax += pc.Tax
+		total += pc.Total
+	}
+
+	return subtotal, discount, tax, total
+}
+
+func (pc *ProductCheckout) NewProductDetailResponse() CheckoutProductDetailResponse {
+	detail := CheckoutProductDetailResponse{
+		ID:           pc.ID,
+		ProductID:    pc.ProductID,
+		Name:         pc.ProductName,
+		Sku:          pc.ProductSku,
+		Quantity:     pc.Quantity,
+		Price:        pc.Price,
+		Discount:     pc.Discount,
+		Promotions:   NewAppliedPromotionResponses(pc.Promotions),
+		Tax:          pc.Tax,
+		TaxRate:      pc.TaxRate,
+		TaxInclusive: pc.TaxInclusive,
+		Total:        pc.Total,
+	}
+	if pc.RestockState != nil {
+		detail.RestockState = *pc.RestockState
+	}
+
+	return detail
+}
+
+func (cr *CheckoutRequest) NewCheckouts() (Checkout, []ProductCheckout, []Payment) {
 	id := uuid.New()
 	rawCreatedAt := time.Now().Format(time.RFC3339)
 	createdAt, _ := time.Parse(time.RFC3339, rawCreatedAt)
@@ -80,12 +291,13 @@ func (cr *CheckoutRequest) NewCheckouts() (Checkout, []ProductCheckout) {
 		ID:             id.String(),
 		CreatedAt:      createdAt,
 		UserCustomerID: cr.CustomerID,
-		Paid:           cr.Paid,
+		UserAdminID:    cr.UserAdminID,
+		Type:           CheckoutTypeSale,
 		Change:         cr.Change,
 	}
 
 	var productCheckouts []ProductCheckout
-	for _, v := range cr.ProductDetails {
+	for _, v := range MergeProductDetails(cr.ProductDetails) {
 		id := uuid.New()
 		productCheckout := ProductCheckout{
 			ID:         id.String(),
@@ -97,5 +309,143 @@ func (cr *CheckoutRequest) NewCheckouts() (Checkout, []ProductCheckout) {
 		productCheckouts = append(productCheckouts, productCheckout)
 	}
 
-	return checkout, productCheckouts
+	var payments []Payment
+	for _, v := range cr.PaymentRequests() {
+		payment := NewPayment(checkout.ID, v.Method, v.Amount, createdAt)
+		if v.Method == PaymentMethodGiftCard {
+			payment.GiftCardCode = NormalizeGiftCardCode(v.GiftCardCode)
+		}
+
+		checkout.Paid += payment.Amount
+		payments = append(payments, payment)
+	}
+
+	return checkout, productCheckouts, payments
+}
+
+func (c *Checkout) NewCheckoutResponse(productCheckouts []ProductCheckout, payments []Payment) CheckoutResponse {
+	response := CheckoutResponse{
+		TransactionID:  c.ID,
+		CreatedAt:      c.CreatedAt,
+		CustomerID:     c.UserCustomerID,
+		CashierID:      c.UserAdminID,
+		Type:           c.Type,
+		ProductDetails: []CheckoutProductDetailResponse{},
+		Subtotal:       c.Subtotal,
+		Discount:       c.Discount,
+		Tax:            c.Tax,
+		Total:          c.Total,
+		Paid:           c.Paid,
+		Change:         *c.Change,
+		Payments:       NewPaymentResponses(payments),
+	}
+	if c.ShiftID != nil {
+		response.ShiftID = *c.ShiftID
+	}
+	if c.ReturnOf != nil {
+		response.ReturnOf = *c.ReturnOf
+	}
+	if c.ReturnReason != nil {
+		response.ReturnReason = *c.ReturnReason
+	}
+	response.VoidedAt = c.VoidedAt
+	if c.VoidedBy != nil {
+		response.VoidedBy = *c.VoidedBy
+	}
+	if c.VoidReason != nil {
+		response.VoidReason = *c.VoidReason
+	}
+
+	for _, pc := range productCheckouts {
+		response.ProductDetails = append(response.ProductDetails, pc.NewProductDetailResponse())
+	}
+
+	return response
+}
+
+func NewCheckoutResponses(checkoutLines []GetCheckoutHistory) []CheckoutResponse {
+	checkouts := []CheckoutResponse{}
+	checkoutIndex := map[string]int{}
+	for _, cl := range checkoutLines {
+		idx, ok := checkoutIndex[cl.TransactionID]
+		if !ok {
+			idx = len(checkouts)
+			checkoutIndex[cl.TransactionID] = idx
+			checkouts = append(checkouts, CheckoutResponse{
+				TransactionID:  cl.TransactionID,
+				CreatedAt:      cl.CreatedAt,
+				CustomerID:     cl.CustomerID,
+				CashierID:      cl.CashierID,
+				ShiftID:        cl.ShiftID,
+				Type:           cl.Type,
+				ReturnOf:       cl.ReturnOf,
+				ReturnReason:   cl.ReturnReason,
+				ProductDetails: []CheckoutProductDetailResponse{},
+				Subtotal:       cl.Subtotal,
+				Discount:       cl.Discount,
+				Tax:            cl.Tax,
+				Total:          cl.Total,
+				Paid:           cl.Paid,
+				Change:         cl.Change,
+				VoidedAt:       cl.VoidedAt,
+				VoidedBy:       cl.VoidedBy,
+				VoidReason:     cl.VoidReason,
+				Payments:       []PaymentResponse{},
+			})
+		}
+
+		// a gift card sale has no product lines
+		if len(cl.ProductCheckoutID) == 0 {
+			continue
+		}
+
+		checkouts[idx].ProductDetails = append(checkouts[idx].ProductDetails, CheckoutProductDetailResponse{
+			ID:           cl.ProductCheckoutID,
+			ProductID:    cl.ProductID,
+			Name:         cl.ProductName,
+			Sku:          cl.ProductSku,
+			Quantity:     cl.Quantity,
+			Price:        cl.Price,
+			Discount:     cl.LineDiscount,
+			Promotions:   []AppliedPromotionResponse{},
+			Tax:          cl.LineTax,
+			TaxRate:      cl.TaxRate,
+			TaxInclusive: cl.TaxInclusive,
+			Total:        cl.LineTotal,
+			RestockState: cl.RestockState,
+		})
+	}
+
+	return checkouts
+}
+
+type CheckoutSummaryQueryParams struct {
+	CashierId string `form:"cashierId"`
+	From      string `form:"from"`
+	To        string `form:"to"`
+}
+
+type CashierSalesSummary struct {
+	CashierID        string `json:"cashierId"`
+	CashierName      string `json:"cashierName"`
+	TransactionCount int    `json:"transactionCount"`
+	Total            int    `json:"total"`
+	Paid             int    `json:"paid"`
+	Change           int    `json:"change"`
+}
+
+func (q *CheckoutHistoryQueryParams) LimitOffset() (int, int) {
+	limit := 5
+	qlimit, _ := strconv.Atoi(q.Limit)
+	if qlimit > 0 {
+		limit = qlimit
+	}
+
+	offset := 0
+	qoffset, _ := strconv.Atoi(q.Offset)
+	if qoffset > 0 {
+		offset = qoffset
+	}
+
+	return limit, offset
 }
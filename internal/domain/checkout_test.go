@@ -0,0 +1,147 @@
+package domain
+
+import "testing"
+
+func TestNewCheckoutResponses(t *testing.T) {
+	tests := []struct {
+		name      string
+		lines     []GetCheckoutHistory
+		wantIDs   []string
+		wantLines []int
+	}{
+		{
+			name:  "no lines",
+			lines: nil,
+		},
+		{
+			name: "lines grouped in query order",
+			lines: []GetCheckoutHistory{
+				{TransactionID: "b", ProductCheckoutID: "b1", ProductName: "Tea", Price: 100, LineTotal: 200},
+				{TransactionID: "a", ProductCheckoutID: "a1", ProductName: "Coffee", Price: 300, LineTotal: 300},
+				{TransactionID: "b", ProductCheckoutID: "b2", ProductName: "Milk", Price: 50, LineTotal: 50},
+			},
+			wantIDs:   []string{"b", "a"},
+			wantLines: []int{2, 1},
+		},
+		{
+			name: "gift card sale without product lines",
+			lines: []GetCheckoutHistory{
+				{TransactionID: "g", Type: CheckoutTypeGiftCard, Total: 50000},
+			},
+			wantIDs:   []string{"g"},
+			wantLines: []int{0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkouts := NewCheckoutResponses(tt.lines)
+			if len(checkouts) != len(tt.wantIDs) {
+				t.Fatalf("got %d checkouts, want %d", len(checkouts), len(tt.wantIDs))
+			}
+			for i, checkout := range checkouts {
+				if checkout.TransactionID != tt.wantIDs[i] {
+					t.Errorf("checkouts[%d] = %s, want %s", i, checkout.TransactionID, tt.wantIDs[i])
+				}
+				if len(checkout.ProductDetails) != tt.wantLines[i] {
+					t.Errorf("checkout %s has %d lines, want %d", checkout.TransactionID, len(checkout.ProductDetails), tt.wantLines[i])
+				}
+				if checkout.Payments == nil {
+					t.Errorf("checkout %s payments are null", checkout.TransactionID)
+				}
+			}
+		})
+	}
+}
+
+func TestNewCheckoutsPayments(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     CheckoutRequest
+		wantMethods []string
+		wantPaid    int
+	}{
+		{
+			name:        "paid without payments is cash",
+			request:     CheckoutRequest{Paid: 15000},
+			wantMethods: []string{PaymentMethodCash},
+			wantPaid:    15000,
+		},
+		{
+			name: "split tender",
+			request: CheckoutRequest{Payments: []PaymentRequest{
+				{Method: PaymentMethodQris, Amount: 10000},
+				{Method: PaymentMethodCash, Amount: 5000},
+				{Method: PaymentMethodPoints, Amount: 500},
+			}},
+			wantMethods: []string{PaymentMethodQris, PaymentMethodCash, PaymentMethodPoints},
+			wantPaid:    15500,
+		},
+		{
+			name: "payments win over paid",
+			request: CheckoutRequest{Paid: 1, Payments: []PaymentRequest{
+				{Method: PaymentMethodDebitCard, Amount: 7000},
+			}},
+			wantMethods: []string{PaymentMethodDebitCard},
+			wantPaid:    7000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkout, _, payments := tt.request.NewCheckouts()
+			if checkout.Paid != tt.wantPaid {
+				t.Errorf("paid = %d, want %d", checkout.Paid, tt.wantPaid)
+			}
+			if len(payments) != len(tt.wantMethods) {
+				t.Fatalf("got %d payments, want %d", len(payments), len(tt.wantMethods))
+			}
+			for i, p := range payments {
+				if p.Method != tt.wantMethods[i] || p.CheckoutID != checkout.ID {
+					t.Errorf("payments[%d] = %s of %s, want %s of %s", i, p.Method, p.CheckoutID, tt.wantMethods[i], checkout.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeProductDetails(t *testing.T) {
+	tests := []struct {
+		name    string
+		details []ProductCheckoutRequest
+		want    []ProductCheckoutRequest
+	}{
+		{
+			name:    "no duplicates",
+			details: []ProductCheckoutRequest{{ProductID: "a", Quantity: 1}, {ProductID: "b", Quantity: 2}},
+			want:    []ProductCheckoutRequest{{ProductID: "a", Quantity: 1}, {ProductID: "b", Quantity: 2}},
+		},
+		{
+			name:    "duplicates are summed in first listed order",
+			details: []ProductCheckoutRequest{{ProductID: "b", Quantity: 1}, {ProductID: "a", Quantity: 2}, {ProductID: "b", Quantity: 3}},
+			want:    []ProductCheckoutRequest{{ProductID: "b", Quantity: 4}, {ProductID: "a", Quantity: 2}},
+		},
+		{
+			name:    "same product only",
+			details: []ProductCheckoutRequest{{ProductID: "a", Quantity: 1}, {ProductID: "a", Quantity: 1}, {ProductID: "a", Quantity: 1}},
+			want:    []ProductCheckoutRequest{{ProductID: "a", Quantity: 3}},
+		},
+		{
+			name: "empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MergeProductDetails(tt.details)
+			if len(got) != len(tt.want) {
+				t.Fatalf("MergeProductDetails() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("MergeProductDetails()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Coupon struct {
+	ID                        string     `db:"id"`
+	Sid                       int        `db:"sid"`
+	CreatedAt                 time.Time  `db:"created_at"`
+	Code                      string     `db:"code"`
+	Type                      string     `db:"type"`
+	Value                     int        `db:"value"`
+	StartsAt                  *time.Time `db:"starts_at"`
+	EndsAt                    *time.Time `db:"ends_at"`
+	MaxRedemptions            *int       `db:"max_redemptions"`
+	MaxRedemptionsPerCustomer *int       `db:"max_redemptions_per_customer"`
+	RedemptionCount           int        `db:"redemption_count"`
+	IsActive                  bool       `db:"is_active"`
+}
+
+type CouponRequest struct {
+	Code                      string     `json:"code" binding:"required,alphanum,gte=3,lte=30"`
+	Type                      string     `json:"type" binding:"required,oneof=percentage fixed"`
+	Value                     int        `json:"value" binding:"required,min=1"`
+	StartsAt                  *time.Time `json:"startsAt"`
+	EndsAt                    *time.Time `json:"endsAt"`
+	MaxRedemptions            *int       `json:"maxRedemptions" binding:"omitempty,min=1"`
+	MaxRedemptionsPerCustomer *int       `json:"maxRedemptionsPerCustomer" binding:"omitempty,min=1"`
+	IsActive                  *bool      `json:"isActive" binding:"required"`
+}
+
+type CouponResponse struct {
+	ID                        string     `json:"id"`
+	CreatedAt                 time.Time  `json:"createdAt"`
+	Code                      string     `json:"code"`
+	Type                      string     `json:"type"`
+	Value                     int        `json:"value"`
+	StartsAt                  *time.Time `json:"startsAt"`
+	EndsAt                    *time.Time `json:"endsAt"`
+	MaxRedemptions            *int       `json:"maxRedemptions"`
+	MaxRedemptionsPerCustomer *int       `json:"maxRedemptionsPerCustomer"`
+	RedemptionCount           int        `json:"redemptionCount"`
+	IsActive                  bool       `json:"isActive"`
+}
+
+type CouponRedemption struct {
+	ID             string    `db:"id"`
+	Sid            int       `db:"sid"`
+	CreatedAt      time.Time `db:"created_at"`
+	CouponID       string    `db:"coupon_id"`
+	CheckoutID     string    `db:"checkout_id"`
+	UserCustomerID string    `db:"user_customer_id"`
+	Discount       int       `db:"discount"`
+}
+
+type CouponReportQueryParams struct {
+	From string `form:"from"`
+	To   string `form:"to"`
+}
+
+type CouponReport struct {
+	CouponID        string `json:"couponId"`
+	Code            string `json:"code"`
+	RedemptionCount int    `json:"redemptionCount"`
+	CustomerCount   int    `json:"customerCount"`
+	Discount        int    `json:"discount"`
+}
+
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func (cr *CouponRequest) NewCoupon() Coupon {
+	id := uuid.New()
+	rawCreatedAt := time.Now().Format(time.RFC3339)
+	createdAt, _ := time.Parse(time.RFC3339, rawCreatedAt)
+
+	return Coupon{
+		ID:                        id.String(),
+		CreatedAt:                 createdAt,
+		Code:                      NormalizeCouponCode(cr.Code),
+		Type:                      cr.Type,
+		Value:                     cr.Value,
+		StartsAt:                  cr.StartsAt,
+		EndsAt:                    cr.EndsAt,
+		MaxRedemptions:            cr.MaxRedemptions,
+		MaxRedemptionsPerCustomer: cr.MaxRedemptionsPerCustomer,
+		IsActive:                  *cr.IsActive,
+	}
+}
+
+func (c *Coupon) NewCouponResponse() CouponResponse {
+	return CouponResponse{
+		ID:                        c.ID,
+		CreatedAt:                 c.CreatedAt,
+		Code:                      c.Code,
+		Type:                      c.Type,
+		Value:                     c.Value,
+		StartsAt:                  c.StartsAt,
+		EndsAt:                    c.EndsAt,
+		MaxRedemptions:            c.MaxRedemptions,
+		MaxRedemptionsPerCustomer: c.MaxRedemptionsPerCustomer,
+		RedemptionCount:           c.RedemptionCount,
+		IsActive:                  c.IsActive,
+	}
+}
+
+func (c *Coupon) Validate() string {
+	if c.Type == PromotionTypePercentage && c.Value > 100 {
+		return "percentage value should be between 1 and 100"
+	}
+	if c.StartsAt != nil && c.EndsAt != nil && !c.EndsAt.After(*c.StartsAt) {
+		return "endsAt should be after startsAt"
+	}
+
+	return ""
+}
+
+func (c *Coupon) Unredeemable(now time.Time, customerRedemptions int) string {
+	if !c.IsActive {
+		return "coupon is not active"
+	}
+	if c.StartsAt != nil && now.Before(*c.StartsAt) {
+		return "coupon is not valid yet"
+	}
+	if c.EndsAt != nil && !now.Before(*c.EndsAt) {
+		return "coupon has expired"
+	}
+	if c.MaxRedemptions != nil && c.RedemptionCount >= *c.MaxRedemptions {
+		return "coupon has been fully redeemed"
+	}
+	if c.MaxRedemptionsPerCustomer != nil && customerRedemptions >= *c.MaxRedemptionsPerCustomer {
+		return "customer has reached the coupon limit"
+	}
+
+	return ""
+}
+
+// a coupon stacks on top of the promotions the lines already got
+func (c *Coupon) Apply(productCheckouts []ProductCheckout, now time.Time) {
+	promotion := Promotion{
+		Name:      c.Code,
+		Scope:     PromotionScopeBasket,
+		Type:      c.Type,
+		Value:     c.Value,
+		Stackable: true,
+		IsActive:  true,
+		couponID:  c.ID,
+	}
+
+	ApplyPromotions(productCheckouts, nil, []Promotion{promotion}, now)
+}
+
+func CouponDiscount(productCheckouts []ProductCheckout) int {
+	discount := 0
+	for _, pc := range productCheckouts {
+		for _, ap := range pc.Promotions {
+			if ap.CouponID != nil {
+				discount += ap.Discount
+			}
+		}
+	}
+
+	return discount
+}
+
+func NewCouponRedemption(coupon Coupon, checkout Checkout, discount int) CouponRedemption {
+	id := uuid.New()
+
+	return CouponRedemption{
+		ID:             id.String(),
+		CreatedAt:      checkout.CreatedAt,
+		CouponID:       coupon.ID,
+		CheckoutID:     checkout.ID,
+		UserCustomerID: checkout.UserCustomerID,
+		Discount:       discount,
+	}
+}
@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalizeCouponCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{code: "SAVE10", want: "SAVE10"},
+		{code: "save10", want: "SAVE10"},
+		{code: " Save10 ", want: "SAVE10"},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeCouponCode(tt.code); got != tt.want {
+			t.Errorf("NormalizeCouponCode(%q) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestCouponUnredeemable(t *testing.T) {
+	now := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	before, after := now.Add(-time.Hour), now.Add(time.Hour)
+	limit := func(n int) *int { return &n }
+
+	tests := []struct {
+		name                string
+		coupon              Coupon
+		customerRedemptions int
+		want                string
+	}{
+		{name: "redeemable", coupon: Coupon{IsActive: true, StartsAt: &before, EndsAt: &after}},
+		{name: "inactive", coupon: Coupon{}, want: "coupon is not active"},
+		{name: "not started", coupon: Coupon{IsActive: true, StartsAt: &after}, want: "coupon is not valid yet"},
+		{name: "expired", coupon: Coupon{IsActive: true, EndsAt: &now}, want: "coupon has expired"},
+		{
+			name:   "last redemption left",
+			coupon: Coupon{IsActive: true, MaxRedemptions: limit(3), RedemptionCount: 2},
+		},
+		{
+			name:   "fully redeemed",
+			coupon: Coupon{IsActive: true, MaxRedemptions: limit(3), RedemptionCount: 3},
+			want:   "coupon has been fully redeemed",
+		},
+		{
+			name:                "customer under the limit",
+			coupon:              Coupon{IsActive: true, MaxRedemptionsPerCustomer: limit(2)},
+			customerRedemptions: 1,
+		},
+		{
+			name:                "customer at the limit",
+			coupon:              Coupon{IsActive: true, MaxRedemptionsPerCustomer: limit(2)},
+			customerRedemptions: 2,
+			want:                "customer has reached the coupon limit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.coupon.Unredeemable(now, tt.customerRedemptions); got != tt.want {
+				t.Errorf("Unredeemable() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCouponApply(t *testing.T) {
+	now := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		coupon           Coupon
+		priorDiscount    int
+		wantDiscounts    []int
+		wantCouponAmount int
+	}{
+		{
+			name:             "percentage",
+			coupon:           Coupon{ID: "c", Code: "SAVE10", Type: PromotionTypePercentage, Value: 10},
+			wantDiscounts:    []int{300, 100},
+			wantCouponAmount: 400,
+		},
+		{
+			name:             "percentage after a promotion",
+			coupon:           Coupon{ID: "c", Code: "SAVE10", Type: PromotionTypePercentage, Value: 10},
+			priorDiscount:    1000,
+			wantDiscounts:    []int{1200, 100},
+			wantCouponAmount: 300,
+		},
+		{
+			name:             "fixed spread over the basket",
+			coupon:           Coupon{ID: "c", Code: "MINUS400", Type: PromotionTypeFixed, Value: 400},
+			wantDiscounts:    []int{300, 100},
+			wantCouponAmount: 400,
+		},
+		{
+			name:             "fixed capped at the basket",
+			coupon:           Coupon{ID: "c", Code: "MINUS9999", Type: PromotionTypeFixed, Value: 9999},
+			priorDiscount:    1000,
+			wantDiscounts:    []int{3000, 1000},
+			wantCouponAmount: 3000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			productCheckouts := []ProductCheckout{
+				{ID: "line-a", ProductID: "a", Price: 1000, Quantity: 3, Discount: tt.priorDiscount},
+				{ID: "line-b", ProductID: "b", Price: 500, Quantity: 2},
+			}
+			if tt.priorDiscount > 0 {
+				productCheckouts[0].Promotions = []AppliedPromotion{{Discount: tt.priorDiscount}}
+			}
+			tt.coupon.Apply(productCheckouts, now)
+
+			for i, pc := range productCheckouts {
+				if pc.Discount != tt.wantDiscounts[i] {
+					t.Errorf("%s discount = %d, want %d", pc.ID, pc.Discount, tt.wantDiscounts[i])
+				}
+			}
+			if got := CouponDiscount(productCheckouts); got != tt.wantCouponAmount {
+				t.Errorf("CouponDiscount() = %d, want %d", got, tt.wantCouponAmount)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	CreditEntryCharge   = "charge"
+	CreditEntryPayment  = "payment"
+	CreditEntryRefund   = "refund"
+	CreditEntryReversal = "reversal"
+)
+
+// a positive amount is owed by the customer
+type CreditEntry struct {
+	ID             string    `db:"id"`
+	Sid            int       `db:"sid"`
+	CreatedAt      time.Time `db:"created_at"`
+	UserCustomerID string    `db:"user_customer_id"`
+	CheckoutID     *string   `db:"checkout_id"`
+	UserAdminID    string    `db:"user_admin_id"`
+	ShiftID        *string   `db:"shift_id"`
+	Type           string    `db:"type"`
+	Method         *string   `db:"method"`
+	Amount         int       `db:"amount"`
+}
+
+type CreditLimitRequest struct {
+	CreditLimit *int `json:"creditLimit" binding:"required,min=0"`
+}
+
+type CreditPaymentRequest struct {
+	UserAdminID string `json:"-"`
+	Method      string `json:"method" binding:"required,oneof=cash debit_card qris e_wallet"`
+	Amount      int    `json:"amount" binding:"required,min=1"`
+}
+
+type CreditAccountResponse struct {
+	CustomerID  string `json:"customerId"`
+	CreditLimit int    `json:"creditLimit"`
+	Balance     int    `json:"balance"`
+	Available   int    `json:"available"`
+}
+
+type CreditEntryResponse struct {
+	ID         string    `json:"id"`
+	CreatedAt  time.Time `json:"createdAt"`
+	CheckoutID string    `json:"checkoutId,omitempty"`
+	CashierID  string    `json:"cashierId,omitempty"`
+	Type       string    `json:"type"`
+	Method     string    `json:"method,omitempty"`
+	Amount     int       `json:"amount"`
+	Balance    int       `json:"balance"`
+}
+
+type CreditStatementQueryParams struct {
+	From string `form:"from"`
+	To   string `form:"to"`
+}
+
+type CreditStatementResponse struct {
+	CustomerID     string                `json:"customerId"`
+	OpeningBalance int                   `json:"openingBalance"`
+	ClosingBalance int                   `json:"closingBalance"`
+	Entries        []CreditEntryResponse `json:"entries"`
+}
+
+type CreditAging struct {
+	CustomerID   string `json:"customerId"`
+	CustomerName string `json:"customerName"`
+	Balance      int    `json:"balance"`
+	Current      int    `json:"current"`
+	Days31To60   int    `json:"days31To60"`
+	Days61To90   int    `json:"days61To90"`
+	Over90Days   int    `json:"over90Days"`
+}
+
+type CreditAccountEntries struct {
+	CustomerID   string
+	CustomerName string
+	Entries      []CreditEntry
+}
+
+func NewCreditEntry(customerID string, checkoutID *string, userAdminID string, entryType string, method *string, amount int, createdAt time.Time) CreditEntry {
+	id := uuid.New()
+
+	return CreditEntry{
+		ID:             id.String(),
+		CreatedAt:      createdAt,
+		UserCustomerID: customerID,
+		CheckoutID:     checkoutID,
+		UserAdminID:    userAdminID,
+		Type:           entryType,
+		Method:         method,
+		Amount:         amount,
+	}
+}
+
+func CreditAvailable(creditLimit int, balance int) int {
+	return max(creditLimit-balance, 0)
+}
+
+func NewCheckoutCreditEntries(checkout Checkout, payments []Payment) []CreditEntry {
+	amount := PaymentsAmount(payments, PaymentMethodStoreCredit)
+	if amount == 0 {
+		return nil
+	}
+
+	entryType := CreditEntryCharge
+	if amount < 0 {
+		entryType = CreditEntryRefund
+	}
+
+	entry := NewCreditEntry(checkout.UserCustomerID, &checkout.ID, checkout.UserAdminID, entryType, nil, amount, checkout.CreatedAt)
+	entry.ShiftID = checkout.ShiftID
+
+	return []CreditEntry{entry}
+}
+
+func NewVoidCreditEntries(saleEntries []CreditEntry, userAdminID string, createdAt time.Time) []CreditEntry {
+	var entries []CreditEntry
+	for _, e := range saleEntries {
+		entries = append(entries, NewCreditEntry(e.UserCustomerID, e.CheckoutID, userAdminID, CreditEntryReversal, nil, -e.Amount, createdAt))
+	}
+
+	return entries
+}
+
+func (cr *CreditPaymentRequest) NewCreditEntry(customerID string) CreditEntry {
+	rawCreatedAt := time.Now().Format(time.RFC3339)
+	createdAt, _ := time.Parse(time.RFC3339, rawCreatedAt)
+
+	return NewCreditEntry(customerID, nil, cr.UserAdminID, CreditEntryPayment, &cr.Method, -cr.Amount, createdAt)
+}
+
+func NewCreditEntryResponses(entries []CreditEntry, openingBalance int) []CreditEntryResponse {
+	balance := openingBalance
+	responses := []CreditEntryResponse{}
+	for _, e := range entries {
+		balance += e.Amount
+		response := CreditEntryResponse{
+			ID:        e.ID,
+			CreatedAt: e.CreatedAt,
+			CashierID: e.UserAdminID,
+			Type:      e.Type,
+			Amount:    e.Amount,
+			Balance:   balance,
+		}
+		if e.CheckoutID != nil {
+			response.CheckoutID = *e.CheckoutID
+		}
+		if e.Method != nil {
+			response.Method = *e.Method
+		}
+
+		responses = append(responses, response)
+	}
+
+	return responses
+}
+
+func NewCreditAging(customerID string, customerName string, entries []CreditEntry, now time.Time) CreditAging {
+	type charge struct {
+		createdAt time.Time
+		amount    int
+	}
+
+	var charges []charge
+	settled := 0
+	for _, e := range entries {
+		if e.Amount > 0 {
+			charges = append(charges, charge{createdAt: e.CreatedAt, amount: e.Amount})
+		} else {
+			settled -= e.Amount
+		}
+	}
+
+	aging := CreditAging{
+		CustomerID:   customerID,
+		CustomerName: customerName,
+	}
+	// payments and refunds settle the oldest charges first
+	for _, c := range charges {
+		settle := min(settled, c.amount)
+		settled -= settle
+		outstanding := c.amount - settle
+		if outstanding == 0 {
+			continue
+		}
+
+		aging.Balance += outstanding
+		days := int(now.Sub(c.createdAt).Hours() / 24)
+		switch {
+		case days <= 30:
+			aging.Current += outstanding
+		case days <= 60:
+			aging.Days31To60 += outstanding
+		case days <= 90:
+			aging.Days61To90 += outstanding
+		default:
+			aging.Over90Days += outstanding
+		}
+	}
+
+	return aging
+}
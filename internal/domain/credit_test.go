@@ -0,0 +1,176 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCreditAvailable(t *testing.T) {
+	tests := []struct {
+		name        string
+		creditLimit int
+		balance     int
+		want        int
+	}{
+		{name: "no balance", creditLimit: 1500, want: 1500},
+		{name: "partly used", creditLimit: 1500, balance: 1000, want: 500},
+		{name: "fully used", creditLimit: 1500, balance: 1500, want: 0},
+		{name: "limit lowered below the balance", creditLimit: 500, balance: 1000, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CreditAvailable(tt.creditLimit, tt.balance); got != tt.want {
+				t.Errorf("CreditAvailable() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCheckoutCreditEntries(t *testing.T) {
+	tests := []struct {
+		name       string
+		payments   []Payment
+		wantType   string
+		wantAmount int
+	}{
+		{name: "no store credit", payments: []Payment{{Method: PaymentMethodCash, Amount: 10000}}},
+		{
+			name:       "sale charges the account",
+			payments:   []Payment{{Method: PaymentMethodStoreCredit, Amount: 7000}, {Method: PaymentMethodCash, Amount: 3000}},
+			wantType:   CreditEntryCharge,
+			wantAmount: 7000,
+		},
+		{
+			name:       "return refunds the account",
+			payments:   []Payment{{Method: PaymentMethodStoreCredit, Amount: -2500}},
+			wantType:   CreditEntryRefund,
+			wantAmount: -2500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shiftID := "shift"
+			checkout := Checkout{ID: "sale", UserCustomerID: "customer", UserAdminID: "cashier", ShiftID: &shiftID}
+			entries := NewCheckoutCreditEntries(checkout, tt.payments)
+
+			if len(tt.wantType) == 0 {
+				if len(entries) > 0 {
+					t.Fatalf("got %d entries, want none", len(entries))
+				}
+				return
+			}
+			if len(entries) != 1 {
+				t.Fatalf("got %d entries, want 1", len(entries))
+			}
+			e := entries[0]
+			if e.Type != tt.wantType || e.Amount != tt.wantAmount {
+				t.Errorf("entry = %s %d, want %s %d", e.Type, e.Amount, tt.wantType, tt.wantAmount)
+			}
+			if *e.CheckoutID != checkout.ID || e.UserAdminID != checkout.UserAdminID || *e.ShiftID != shiftID {
+				t.Errorf("entry isn't linked to the checkout")
+			}
+		})
+	}
+}
+
+func TestNewVoidCreditEntries(t *testing.T) {
+	checkoutID := "sale"
+	saleEntries := []CreditEntry{
+		{UserCustomerID: "customer", CheckoutID: &checkoutID, UserAdminID: "cashier", Type: CreditEntryCharge, Amount: 7000},
+	}
+
+	entries := NewVoidCreditEntries(saleEntries, "manager", time.Now())
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	e := entries[0]
+	if e.Type != CreditEntryReversal || e.Amount != -7000 || e.UserAdminID != "manager" || *e.CheckoutID != checkoutID {
+		t.Errorf("entry = %s %d by %s, want a reversal of -7000 by manager", e.Type, e.Amount, e.UserAdminID)
+	}
+}
+
+func TestNewCreditEntryResponses(t *testing.T) {
+	entries := []CreditEntry{
+		{Type: CreditEntryCharge, Amount: 5000},
+		{Type: CreditEntryPayment, Amount: -2000},
+		{Type: CreditEntryRefund, Amount: -500},
+		{Type: CreditEntryCharge, Amount: 1000},
+	}
+
+	tests := []struct {
+		name           string
+		openingBalance int
+		wantBalances   []int
+	}{
+		{name: "new account", wantBalances: []int{5000, 3000, 2500, 3500}},
+		{name: "carried balance", openingBalance: 1000, wantBalances: []int{6000, 4000, 3500, 4500}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := NewCreditEntryResponses(entries, tt.openingBalance)
+			for i, r := range responses {
+				if r.Balance != tt.wantBalances[i] {
+					t.Errorf("balance after entry %d = %d, want %d", i, r.Balance, tt.wantBalances[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNewCreditAging(t *testing.T) {
+	now := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+
+	tests := []struct {
+		name    string
+		entries []CreditEntry
+		want    CreditAging
+	}{
+		{
+			name:    "settled",
+			entries: []CreditEntry{{CreatedAt: daysAgo(100), Amount: 1000}, {CreatedAt: daysAgo(5), Amount: -1000}},
+			want:    CreditAging{},
+		},
+		{
+			name: "one charge per bucket",
+			entries: []CreditEntry{
+				{CreatedAt: daysAgo(120), Amount: 400},
+				{CreatedAt: daysAgo(75), Amount: 300},
+				{CreatedAt: daysAgo(45), Amount: 200},
+				{CreatedAt: daysAgo(30), Amount: 100},
+			},
+			want: CreditAging{Balance: 1000, Current: 100, Days31To60: 200, Days61To90: 300, Over90Days: 400},
+		},
+		{
+			name: "payments settle the oldest charges first",
+			entries: []CreditEntry{
+				{CreatedAt: daysAgo(120), Amount: 400},
+				{CreatedAt: daysAgo(45), Amount: 200},
+				{CreatedAt: daysAgo(10), Amount: 100},
+				{CreatedAt: daysAgo(1), Amount: -500},
+			},
+			want: CreditAging{Balance: 200, Current: 100, Days31To60: 100},
+		},
+		{
+			name: "refunds settle like payments",
+			entries: []CreditEntry{
+				{CreatedAt: daysAgo(61), Amount: 300},
+				{CreatedAt: daysAgo(31), Amount: 300},
+				{CreatedAt: daysAgo(2), Amount: -300},
+			},
+			want: CreditAging{Balance: 300, Days31To60: 300},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.want.CustomerID, tt.want.CustomerName = "customer", "Test"
+			if got := NewCreditAging("customer", "Test", tt.entries, now); got != tt.want {
+				t.Errorf("NewCreditAging() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
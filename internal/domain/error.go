@@ -34,6 +34,14 @@ func NewUnauthenticatedError(message string) MessageErr {
 	}
 }
 
+func NewForbiddenError(message string) MessageErr {
+	return &ErrorData{
+		ErrMessage: message,
+		ErrStatus:  http.StatusForbidden,
+		ErrError:   "FORBIDDEN",
+	}
+}
+
 func NewNotFoundError(message string) MessageErr {
 	return &ErrorData{
 		ErrMessage: message,
@@ -65,3 +73,19 @@ func NewConflictError(message string) MessageErr {
 		ErrError:   "CONFLICT_ERROR",
 	}
 }
+
+func NewUnprocessableEntityError(message string) MessageErr {
+	return &ErrorData{
+		ErrMessage: message,
+		ErrStatus:  http.StatusUnprocessableEntity,
+		ErrError:   "UNPROCESSABLE_ENTITY",
+	}
+}
+
+func NewTooManyRequestsError(message string) MessageErr {
+	return &ErrorData{
+		ErrMessage: message,
+		ErrStatus:  http.StatusTooManyRequests,
+		ErrError:   "TOO_MANY_REQUESTS",
+	}
+}
@@ -0,0 +1,237 @@
+package domain
+
+import (
+	"crypto/rand"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	GiftCardStatusActive = "active"
+	GiftCardStatusFrozen = "frozen"
+)
+
+var (
+	GiftCardEntryIssue    = "issue"
+	GiftCardEntryRedeem   = "redeem"
+	GiftCardEntryRefund   = "refund"
+	GiftCardEntryReversal = "reversal"
+)
+
+var CheckoutTypeGiftCard = "gift_card"
+
+var GiftCardIssueMethods = []string{
+	PaymentMethodCash,
+	PaymentMethodDebitCard,
+	PaymentMethodQris,
+	PaymentMethodEWallet,
+}
+
+const giftCardCodeChars = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+type GiftCard struct {
+	ID             string     `db:"id"`
+	Sid            int        `db:"sid"`
+	CreatedAt      time.Time  `db:"created_at"`
+	Code           string     `db:"code"`
+	CheckoutID     string     `db:"checkout_id"`
+	InitialBalance int        `db:"initial_balance"`
+	Status         string     `db:"status"`
+	ExpiresAt      *time.Time `db:"expires_at"`
+}
+
+type GiftCardEntry struct {
+	ID         string    `db:"id"`
+	Sid        int       `db:"sid"`
+	CreatedAt  time.Time `db:"created_at"`
+	GiftCardID string    `db:"gift_card_id"`
+	CheckoutID *string   `db:"checkout_id"`
+	Type       string    `db:"type"`
+	Amount     int       `db:"amount"`
+}
+
+type GiftCardRequest struct {
+	UserAdminID string           `json:"-"`
+	CustomerID  string           `json:"customerId" binding:"required"`
+	Code        string           `json:"code" binding:"omitempty,alphanum,gte=8,lte=30"`
+	Amount      int              `json:"amount" binding:"required,min=1"`
+	ExpiresAt   *time.Time       `json:"expiresAt"`
+	Payments    []PaymentRequest `json:"payments" binding:"required,min=1,dive"`
+	Change      *int             `json:"change" binding:"required,min=0,number"`
+}
+
+type GiftCardStatusRequest struct {
+	Status    string     `json:"status" binding:"required,oneof=active frozen"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+type GiftCardResponse struct {
+	ID             string     `json:"id"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	Code           string     `json:"code"`
+	TransactionID  string     `json:"transactionId"`
+	InitialBalance int        `json:"initialBalance"`
+	Balance        int        `json:"balance"`
+	Status         string     `json:"status"`
+	ExpiresAt      *time.Time `json:"expiresAt"`
+}
+
+func NormalizeGiftCardCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func NewGiftCardCode() (string, error) {
+	code := make([]byte, 16)
+	charCount := big.NewInt(int64(len(giftCardCodeChars)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, charCount)
+		if err != nil {
+			return "", err
+		}
+		code[i] = giftCardCodeChars[n.Int64()]
+	}
+
+	return string(code), nil
+}
+
+func (gr *GiftCardRequest) NewGiftCard() (GiftCard, Checkout, []Payment, error) {
+	rawCreatedAt := time.Now().Format(time.RFC3339)
+	createdAt, _ := time.Parse(time.RFC3339, rawCreatedAt)
+
+	checkoutID := uuid.New()
+	checkout := Checkout{
+		ID:             checkoutID.String(),
+		CreatedAt:      createdAt,
+		UserCustomerID: gr.CustomerID,
+		UserAdminID:    gr.UserAdminID,
+		Type:           CheckoutTypeGiftCard,
+		Change:         gr.Change,
+		Subtotal:       gr.Amount,
+		Total:          gr.Amount,
+	}
+
+	var payments []Payment
+	for _, v := range gr.Payments {
+		payment := NewPayment(checkout.ID, v.Method, v.Amount, createdAt)
+
+		checkout.Paid += payment.Amount
+		payments = append(payments, payment)
+	}
+
+	code := NormalizeGiftCardCode(gr.Code)
+	if len(code) == 0 {
+		var err error
+		code, err = NewGiftCardCode()
+		if err != nil {
+			return GiftCard{}, Checkout{}, nil, err
+		}
+	}
+
+	id := uuid.New()
+	giftCard := GiftCard{
+		ID:             id.String(),
+		CreatedAt:      createdAt,
+		Code:           code,
+		CheckoutID:     checkout.ID,
+		InitialBalance: gr.Amount,
+		Status:         GiftCardStatusActive,
+		ExpiresAt:      gr.ExpiresAt,
+	}
+
+	return giftCard, checkout, payments, nil
+}
+
+func (g *GiftCard) NewGiftCardResponse(balance int) GiftCardResponse {
+	return GiftCardResponse{
+		ID:             g.ID,
+		CreatedAt:      g.CreatedAt,
+		Code:           g.Code,
+		TransactionID:  g.CheckoutID,
+		InitialBalance: g.InitialBalance,
+		Balance:        balance,
+		Status:         g.Status,
+		ExpiresAt:      g.ExpiresAt,
+	}
+}
+
+func (g *GiftCard) Unredeemable(now time.Time) string {
+	if g.Status == GiftCardStatusFrozen {
+		return "gift card is frozen"
+	}
+	if g.ExpiresAt != nil && !now.Before(*g.ExpiresAt) {
+		return "gift card is expired"
+	}
+
+	return ""
+}
+
+func NewGiftCardEntry(giftCardID string, checkoutID *string, entryType string, amount int, createdAt time.Time) GiftCardEntry {
+	id := uuid.New()
+
+	return GiftCardEntry{
+		ID:         id.String(),
+		CreatedAt:  createdAt,
+		GiftCardID: giftCardID,
+		CheckoutID: checkoutID,
+		Type:       entryType,
+		Amount:     amount,
+	}
+}
+
+func NewGiftCardPaymentEntries(checkout Checkout, payments []Payment) []GiftCardEntry {
+	var entries []GiftCardEntry
+	for _, p := range payments {
+		if p.Method != PaymentMethodGiftCard || p.GiftCardID == nil || p.Amount == 0 {
+			continue
+		}
+
+		entryType := GiftCardEntryRedeem
+		if p.Amount < 0 {
+			entryType = GiftCardEntryRefund
+		}
+		entries = append(entries, NewGiftCardEntry(*p.GiftCardID, &checkout.ID, entryType, -p.Amount, checkout.CreatedAt))
+	}
+
+	return entries
+}
+
+func NewVoidGiftCardEntries(saleEntries []GiftCardEntry, createdAt time.Time) []GiftCardEntry {
+	var entries []GiftCardEntry
+	for _, e := range saleEntries {
+		entries = append(entries, NewGiftCardEntry(e.GiftCardID, e.CheckoutID, GiftCardEntryReversal, -e.Amount, createdAt))
+	}
+
+	return entries
+}
+
+func GiftCardCodes(payments []Payment) []string {
+	seen := map[string]bool{}
+	var codes []string
+	for _, p := range payments {
+		if p.Method != PaymentMethodGiftCard || seen[p.GiftCardCode] {
+			continue
+		}
+
+		seen[p.GiftCardCode] = true
+		codes = append(codes, p.GiftCardCode)
+	}
+	// sorted so concurrent checkouts lock the cards in the same order
+	slices.Sort(codes)
+
+	return codes
+}
+
+func GiftCardRedemptions(payments []Payment) map[string]int {
+	redeemed := map[string]int{}
+	for _, p := range payments {
+		if p.Method == PaymentMethodGiftCard {
+			redeemed[p.GiftCardCode] += p.Amount
+		}
+	}
+
+	return redeemed
+}
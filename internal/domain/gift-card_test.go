@@ -0,0 +1,231 @@
+package domain
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewGiftCardCode(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		code, err := NewGiftCardCode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(code) != 16 {
+			t.Fatalf("code %s has %d characters, want 16", code, len(code))
+		}
+		for _, c := range code {
+			if !strings.ContainsRune(giftCardCodeChars, c) {
+				t.Fatalf("code %s has %q", code, c)
+			}
+		}
+		if seen[code] {
+			t.Fatalf("code %s was generated twice", code)
+		}
+		seen[code] = true
+	}
+}
+
+func TestNewGiftCard(t *testing.T) {
+	change := 5000
+	tests := []struct {
+		name     string
+		code     string
+		wantCode string
+	}{
+		{name: "code given", code: " gift2024card ", wantCode: "GIFT2024CARD"},
+		{name: "code generated"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := GiftCardRequest{
+				UserAdminID: "cashier",
+				CustomerID:  "customer",
+				Code:        tt.code,
+				Amount:      50000,
+				Payments: []PaymentRequest{
+					{Method: PaymentMethodCash, Amount: 30000},
+					{Method: PaymentMethodQris, Amount: 25000},
+				},
+				Change: &change,
+			}
+			giftCard, checkout, payments, err := request.NewGiftCard()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(tt.wantCode) > 0 && giftCard.Code != tt.wantCode {
+				t.Errorf("code = %s, want %s", giftCard.Code, tt.wantCode)
+			}
+			if len(giftCard.Code) == 0 {
+				t.Errorf("gift card has no code")
+			}
+			if giftCard.CheckoutID != checkout.ID || giftCard.InitialBalance != 50000 || giftCard.Status != GiftCardStatusActive {
+				t.Errorf("gift card = %+v", giftCard)
+			}
+			if checkout.Type != CheckoutTypeGiftCard || checkout.Total != 50000 || checkout.Paid != 55000 {
+				t.Errorf("checkout type %s, total %d, paid %d", checkout.Type, checkout.Total, checkout.Paid)
+			}
+			if len(payments) != 2 || payments[0].CheckoutID != checkout.ID {
+				t.Errorf("payments = %+v", payments)
+			}
+		})
+	}
+}
+
+func TestGiftCardUnredeemable(t *testing.T) {
+	now := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	after := now.Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		giftCard GiftCard
+		want     string
+	}{
+		{name: "active", giftCard: GiftCard{Status: GiftCardStatusActive}},
+		{name: "not expired yet", giftCard: GiftCard{Status: GiftCardStatusActive, ExpiresAt: &after}},
+		{name: "frozen", giftCard: GiftCard{Status: GiftCardStatusFrozen}, want: "gift card is frozen"},
+		{name: "expired", giftCard: GiftCard{Status: GiftCardStatusActive, ExpiresAt: &now}, want: "gift card is expired"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.giftCard.Unredeemable(now); got != tt.want {
+				t.Errorf("Unredeemable() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewGiftCardPaymentEntries(t *testing.T) {
+	first, second := "first", "second"
+
+	tests := []struct {
+		name        string
+		payments    []Payment
+		wantTypes   []string
+		wantAmounts []int
+	}{
+		{name: "no gift cards", payments: []Payment{{Method: PaymentMethodCash, Amount: 1000}}},
+		{
+			name: "sale takes off every card",
+			payments: []Payment{
+				{Method: PaymentMethodGiftCard, GiftCardID: &first, Amount: 3000},
+				{Method: PaymentMethodCash, Amount: 1000},
+				{Method: PaymentMethodGiftCard, GiftCardID: &second, Amount: 2000},
+			},
+			wantTypes:   []string{GiftCardEntryRedeem, GiftCardEntryRedeem},
+			wantAmounts: []int{-3000, -2000},
+		},
+		{
+			name:        "return puts back on the card",
+			payments:    []Payment{{Method: PaymentMethodGiftCard, GiftCardID: &first, Amount: -1500}},
+			wantTypes:   []string{GiftCardEntryRefund},
+			wantAmounts: []int{1500},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkout := Checkout{ID: "sale", CreatedAt: time.Now()}
+			entries := NewGiftCardPaymentEntries(checkout, tt.payments)
+			if len(entries) != len(tt.wantTypes) {
+				t.Fatalf("got %d entries, want %d", len(entries), len(tt.wantTypes))
+			}
+			for i, e := range entries {
+				if e.Type != tt.wantTypes[i] || e.Amount != tt.wantAmounts[i] {
+					t.Errorf("entries[%d] = %s %d, want %s %d", i, e.Type, e.Amount, tt.wantTypes[i], tt.wantAmounts[i])
+				}
+				if *e.CheckoutID != checkout.ID {
+					t.Errorf("entries[%d] isn't linked to the checkout", i)
+				}
+			}
+		})
+	}
+}
+
+func TestNewVoidGiftCardEntries(t *testing.T) {
+	checkoutID := "sale"
+	saleEntries := []GiftCardEntry{
+		{GiftCardID: "first", CheckoutID: &checkoutID, Type: GiftCardEntryRedeem, Amount: -3000},
+		{GiftCardID: "second", CheckoutID: &checkoutID, Type: GiftCardEntryRedeem, Amount: -2000},
+	}
+
+	entries := NewVoidGiftCardEntries(saleEntries, time.Now())
+	if len(entries) != len(saleEntries) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(saleEntries))
+	}
+	for i, e := range entries {
+		if e.Type != GiftCardEntryReversal || e.GiftCardID != saleEntries[i].GiftCardID || e.Amount != -saleEntries[i].Amount {
+			t.Errorf("entries[%d] = %s %s %d", i, e.GiftCardID, e.Type, e.Amount)
+		}
+	}
+}
+
+func TestGiftCardCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		payments []Payment
+		want     []string
+	}{
+		{name: "no gift cards", payments: []Payment{{Method: PaymentMethodCash}}},
+		{
+			name: "distinct and sorted",
+			payments: []Payment{
+				{Method: PaymentMethodGiftCard, GiftCardCode: "ZULU"},
+				{Method: PaymentMethodCash},
+				{Method: PaymentMethodGiftCard, GiftCardCode: "ALPHA"},
+				{Method: PaymentMethodGiftCard, GiftCardCode: "ZULU"},
+			},
+			want: []string{"ALPHA", "ZULU"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GiftCardCodes(tt.payments); !slices.Equal(got, tt.want) {
+				t.Errorf("GiftCardCodes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGiftCardRedemptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		payments []Payment
+		want     map[string]int
+	}{
+		{name: "no gift cards", payments: []Payment{{Method: PaymentMethodCash, Amount: 1000}}, want: map[string]int{}},
+		{
+			name: "one card paid twice",
+			payments: []Payment{
+				{Method: PaymentMethodGiftCard, GiftCardCode: "ALPHA", Amount: 600},
+				{Method: PaymentMethodCash, Amount: 100},
+				{Method: PaymentMethodGiftCard, GiftCardCode: "ALPHA", Amount: 500},
+			},
+			want: map[string]int{"ALPHA": 1100},
+		},
+		{
+			name: "two cards",
+			payments: []Payment{
+				{Method: PaymentMethodGiftCard, GiftCardCode: "ALPHA", Amount: 600},
+				{Method: PaymentMethodGiftCard, GiftCardCode: "ZULU", Amount: 400},
+			},
+			want: map[string]int{"ALPHA": 600, "ZULU": 400},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GiftCardRedemptions(tt.payments); !maps.Equal(got, tt.want) {
+				t.Errorf("GiftCardRedemptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
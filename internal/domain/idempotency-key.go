@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// keys sent by clients are kept apart from the ones built for carts
+	ClientIdempotencyKeyPrefix = "client:"
+)
+
+type IdempotencyKey struct {
+	ID             string          `db:"id"`
+	Sid            int             `db:"sid"`
+	UserAdminID    string          `db:"user_admin_id"`
+	Key            string          `db:"key"`
+	RequestHash    string          `db:"request_hash"`
+	ResponseStatus int             `db:"response_status"`
+	ResponseBody   json.RawMessage `db:"response_body"`
+	CheckoutID     string          `db:"checkout_id"`
+	CreatedAt      time.Time       `db:"created_at"`
+	ExpiresAt      time.Time       `db:"expires_at"`
+}
+
+type StoredResponse struct {
+	Status     int
+	Body       json.RawMessage
+	CheckoutID string
+	Replayed   bool
+}
+
+func NewIdempotencyKey(userAdminID string, key string, requestHash string, checkoutID string, response StoredResponse, ttl time.Duration) IdempotencyKey {
+	id := uuid.New()
+	rawCreatedAt := time.Now().Format(time.RFC3339)
+	createdAt, _ := time.Parse(time.RFC3339, rawCreatedAt)
+
+	return IdempotencyKey{
+		ID:             id.String(),
+		UserAdminID:    userAdminID,
+		Key:            key,
+		RequestHash:    requestHash,
+		ResponseStatus: response.Status,
+		ResponseBody:   response.Body,
+		CheckoutID:     checkoutID,
+		CreatedAt:      createdAt,
+		ExpiresAt:      createdAt.Add(ttl),
+	}
+}
+
+func (ik *IdempotencyKey) StoredResponse() *StoredResponse {
+	return &StoredResponse{
+		Status:     ik.ResponseStatus,
+		Body:       ik.ResponseBody,
+		CheckoutID: ik.CheckoutID,
+		Replayed:   true,
+	}
+}
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	LoginAttemptSuccess            = "success"
+	LoginAttemptInvalidCredentials = "invalid_credentials"
+	LoginAttemptThrottled          = "throttled"
+	// the password was right, the login waits for the second factor
+	LoginAttemptPasswordVerified    = "password_verified"
+	LoginAttemptInvalidSecondFactor = "invalid_second_factor"
+)
+
+const (
+	// failures older than this are forgotten
+	LoginFailureWindow = 15 * time.Minute
+	// failures of an account before every next attempt has to wait
+	LoginDelayAfterFailures = 3
+	LoginMaxDelay           = time.Minute
+	AccountLockoutFailures  = 10
+	IPLockoutFailures       = 50
+	LoginLockoutDuration    = 15 * time.Minute
+)
+
+type LoginAttempt struct {
+	ID          string    `db:"id"`
+	Sid         int       `db:"sid"`
+	CreatedAt   time.Time `db:"created_at"`
+	PhoneNumber string    `db:"phone_number"`
+	IP          string    `db:"ip"`
+	UserAgent   string    `db:"user_agent"`
+	UserAdminID *string   `db:"user_admin_id"`
+	Result      string    `db:"result"`
+}
+
+// an account's failures stop counting at its last successful login
+type LoginFailures struct {
+	Count       int
+	LastFailure *time.Time
+}
+
+type LoginAttemptQueryParams struct {
+	PhoneNumber string `form:"phoneNumber"`
+	IP          string `form:"ip"`
+	Result      string `form:"result"`
+	Limit       string `form:"limit"`
+	Offset      string `form:"offset"`
+}
+
+type LoginAttemptResponse struct {
+	ID          string    `json:"id"`
+	CreatedAt   time.Time `json:"createdAt"`
+	PhoneNumber string    `json:"phoneNumber"`
+	IP          string    `json:"ip"`
+	UserAgent   string    `json:"userAgent"`
+	UserAdminID *string   `json:"userAdminId"`
+	Result      string    `json:"result"`
+}
+
+func NewLoginAttempt(body LoginUserAdmin, userAdminID *string, result string) LoginAttempt {
+	id := uuid.New()
+	rawCreatedAt := time.Now().Format(time.RFC3339)
+	createdAt, _ := time.Parse(time.RFC3339, rawCreatedAt)
+
+	return LoginAttempt{
+		ID:          id.String(),
+		CreatedAt:   createdAt,
+		PhoneNumber: body.PhoneNumber,
+		IP:          body.IP,
+		UserAgent:   body.UserAgent,
+		UserAdminID: userAdminID,
+		Result:      result,
+	}
+}
+
+func LoginRetryAfter(account LoginFailures, ip LoginFailures, now time.Time) time.Duration {
+	var wait time.Duration
+	if ip.Count >= IPLockoutFailures {
+		wait = ip.LastFailure.Add(LoginLockoutDuration).Sub(now)
+	}
+
+	if account.Count >= AccountLockoutFailures {
+		wait = max(wait, account.LastFailure.Add(LoginLockoutDuration).Sub(now))
+	} else if account.Count >= LoginDelayAfterFailures {
+		// every failure past the first few doubles the wait
+		delay := time.Duration(math.Pow(2, float64(account.Count-LoginDelayAfterFailures))) * time.Second
+		wait = max(wait, account.LastFailure.Add(min(delay, LoginMaxDelay)).Sub(now))
+	}
+
+	return max(wait, 0)
+}
+
+func (q *LoginAttemptQueryParams) LimitOffset() (int, int) {
+	limit := 5
+	qlimit, _ := strconv.Atoi(q.Limit)
+	if qlimit > 0 {
+		limit = qlimit
+	}
+
+	offset := 0
+	qoffset, _ := strconv.Atoi(q.Offset)
+	if qoffset > 0 {
+		offset = qoffset
+	}
+
+	return limit, offset
+}
+
+func (la *LoginAttempt) NewLoginAttemptResponse() LoginAttemptResponse {
+	return LoginAttemptResponse{
+		ID:          la.ID,
+		CreatedAt:   la.CreatedAt,
+		PhoneNumber: la.PhoneNumber,
+		IP:          la.IP,
+		UserAgent:   la.UserAgent,
+		UserAdminID: la.UserAdminID,
+		Result:      la.Result,
+	}
+}
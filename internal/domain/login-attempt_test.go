@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoginRetryAfter(t *testing.T) {
+	now := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) *time.Time {
+		at := now.Add(-d)
+		return &at
+	}
+
+	tests := []struct {
+		name    string
+		account LoginFailures
+		ip      LoginFailures
+		want    time.Duration
+	}{
+		{name: "no failures", want: 0},
+		{name: "a few failures", account: LoginFailures{Count: 2, LastFailure: ago(0)}, want: 0},
+		{name: "first delay", account: LoginFailures{Count: 3, LastFailure: ago(0)}, want: time.Second},
+		{name: "delay doubles", account: LoginFailures{Count: 5, LastFailure: ago(0)}, want: 4 * time.Second},
+		{name: "delay counts from the last failure", account: LoginFailures{Count: 5, LastFailure: ago(3 * time.Second)}, want: time.Second},
+		{name: "delay already waited", account: LoginFailures{Count: 5, LastFailure: ago(10 * time.Second)}, want: 0},
+		{name: "delay capped", account: LoginFailures{Count: 9, LastFailure: ago(0)}, want: LoginMaxDelay},
+		{name: "account locked out", account: LoginFailures{Count: 10, LastFailure: ago(5 * time.Minute)}, want: 10 * time.Minute},
+		{name: "account lockout over", account: LoginFailures{Count: 10, LastFailure: ago(LoginLockoutDuration)}, want: 0},
+		{name: "ip locked out", ip: LoginFailures{Count: 50, LastFailure: ago(time.Minute)}, want: 14 * time.Minute},
+		{
+			name:    "longest wait wins",
+			account: LoginFailures{Count: 4, LastFailure: ago(0)},
+			ip:      LoginFailures{Count: 50, LastFailure: ago(14 * time.Minute)},
+			want:    time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LoginRetryAfter(tt.account, tt.ip, now); got != tt.want {
+				t.Errorf("LoginRetryAfter() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
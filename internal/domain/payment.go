@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	PaymentMethodCash        = "cash"
+	PaymentMethodDebitCard   = "debit_card"
+	PaymentMethodQris        = "qris"
+	PaymentMethodEWallet     = "e_wallet"
+	PaymentMethodPoints      = "points"
+	PaymentMethodStoreCredit = "store_credit"
+	PaymentMethodGiftCard    = "gift_card"
+)
+
+var PaymentMethod = []string{
+	PaymentMethodCash,
+	PaymentMethodDebitCard,
+	PaymentMethodQris,
+	PaymentMethodEWallet,
+	PaymentMethodPoints,
+	PaymentMethodStoreCredit,
+	PaymentMethodGiftCard,
+}
+
+type Payment struct {
+	ID           string    `db:"id"`
+	Sid          int       `db:"sid"`
+	CheckoutID   string    `db:"checkout_id"`
+	Method       string    `db:"method"`
+	Amount       int       `db:"amount"`
+	GiftCardID   *string   `db:"gift_card_id"`
+	GiftCardCode string    `db:"-"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+type PaymentRequest struct {
+	Method       string `json:"method" binding:"required,oneof=cash debit_card qris e_wallet points store_credit gift_card"`
+	Amount       int    `json:"amount" binding:"required,min=1,number"`
+	GiftCardCode string `json:"giftCardCode" binding:"required_if=Method gift_card,omitempty,lte=30"`
+}
+
+type PaymentResponse struct {
+	Method     string `json:"method"`
+	Amount     int    `json:"amount"`
+	GiftCardID string `json:"giftCardId,omitempty"`
+}
+
+func NewPayment(checkoutID string, method string, amount int, createdAt time.Time) Payment {
+	id := uuid.New()
+
+	return Payment{
+		ID:         id.String(),
+		CheckoutID: checkoutID,
+		Method:     method,
+		Amount:     amount,
+		CreatedAt:  createdAt,
+	}
+}
+
+func PaymentsAmount(payments []Payment, method string) int {
+	amount := 0
+	for _, p := range payments {
+		if p.Method == method {
+			amount += p.Amount
+		}
+	}
+
+	return amount
+}
+
+func NewPaymentResponses(payments []Payment) []PaymentResponse {
+	responses := []PaymentResponse{}
+	for _, p := range payments {
+		response := PaymentResponse{
+			Method: p.Method,
+			Amount: p.Amount,
+		}
+		if p.GiftCardID != nil {
+			response.GiftCardID = *p.GiftCardID
+		}
+
+		responses = append(responses, response)
+	}
+
+	return responses
+}
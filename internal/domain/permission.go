@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"slices"
+)
+
+const (
+	PermissionProductRead    = "product:read"
+	PermissionProductWrite   = "product:write"
+	PermissionProductDelete  = "product:delete"
+	PermissionCheckoutCreate = "checkout:create"
+	PermissionCheckoutRead   = "checkout:read"
+	PermissionCheckoutRefund = "checkout:refund"
+	PermissionCheckoutVoid   = "checkout:void"
+	// voiding a sale rung up by someone else
+	PermissionCheckoutVoidAny = "checkout:void_any"
+	PermissionCustomerRead    = "customer:read"
+	PermissionCustomerWrite   = "customer:write"
+	PermissionCreditManage    = "credit:manage"
+	PermissionGiftCardManage  = "gift_card:manage"
+	PermissionPricingRead     = "pricing:read"
+	PermissionPricingWrite    = "pricing:write"
+	PermissionShiftOperate    = "shift:operate"
+	PermissionShiftRead       = "shift:read"
+	PermissionReportRead      = "report:read"
+	PermissionDayClose        = "report:close_day"
+	PermissionStaffInvite     = "staff:invite"
+	PermissionStaffManage     = "staff:manage"
+)
+
+var cashierPermissions = []string{
+	PermissionProductRead,
+	PermissionCheckoutCreate,
+	PermissionCheckoutRead,
+	PermissionCheckoutVoid,
+	PermissionCustomerRead,
+	PermissionCustomerWrite,
+	PermissionPricingRead,
+	PermissionShiftOperate,
+}
+
+var stockClerkPermissions = []string{
+	PermissionProductRead,
+	PermissionProductWrite,
+}
+
+var managerPermissions = slices.Concat(cashierPermissions, []string{
+	PermissionProductWrite,
+	PermissionProductDelete,
+	PermissionCheckoutRefund,
+	PermissionCheckoutVoidAny,
+	PermissionCreditManage,
+	PermissionGiftCardManage,
+	PermissionPricingWrite,
+	PermissionShiftRead,
+	PermissionReportRead,
+	PermissionDayClose,
+	PermissionStaffInvite,
+})
+
+var ownerPermissions = slices.Concat(managerPermissions, []string{
+	PermissionStaffManage,
+})
+
+var RolePermissions = map[string][]string{
+	UserAdminRoleOwner:      ownerPermissions,
+	UserAdminRoleManager:    managerPermissions,
+	UserAdminRoleCashier:    cashierPermissions,
+	UserAdminRoleStockClerk: stockClerkPermissions,
+}
+
+func HasPermission(role string, permission string) bool {
+	return slices.Contains(RolePermissions[role], permission)
+}
@@ -0,0 +1,54 @@
+package domain
+
+import "testing"
+
+func TestHasPermission(t *testing.T) {
+	owner := UserAdminRoleOwner
+	manager := UserAdminRoleManager
+	cashier := UserAdminRoleCashier
+	stockClerk := UserAdminRoleStockClerk
+	roles := []string{owner, manager, cashier, stockClerk}
+
+	tests := []struct {
+		permission string
+		allowed    []string
+	}{
+		{PermissionProductRead, []string{owner, manager, cashier, stockClerk}},
+		{PermissionProductWrite, []string{owner, manager, stockClerk}},
+		{PermissionProductDelete, []string{owner, manager}},
+		{PermissionCheckoutCreate, []string{owner, manager, cashier}},
+		{PermissionCheckoutRead, []string{owner, manager, cashier}},
+		{PermissionCheckoutRefund, []string{owner, manager}},
+		{PermissionCheckoutVoid, []string{owner, manager, cashier}},
+		{PermissionCheckoutVoidAny, []string{owner, manager}},
+		{PermissionCustomerRead, []string{owner, manager, cashier}},
+		{PermissionCustomerWrite, []string{owner, manager, cashier}},
+		{PermissionCreditManage, []string{owner, manager}},
+		{PermissionGiftCardManage, []string{owner, manager}},
+		{PermissionPricingRead, []string{owner, manager, cashier}},
+		{PermissionPricingWrite, []string{owner, manager}},
+		{PermissionShiftOperate, []string{owner, manager, cashier}},
+		{PermissionShiftRead, []string{owner, manager}},
+		{PermissionReportRead, []string{owner, manager}},
+		{PermissionDayClose, []string{owner, manager}},
+		{PermissionStaffInvite, []string{owner, manager}},
+		{PermissionStaffManage, []string{owner}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.permission, func(t *testing.T) {
+			for _, role := range roles {
+				want := false
+				for _, allowed := range tt.allowed {
+					want = want || allowed == role
+				}
+				if got := HasPermission(role, tt.permission); got != want {
+					t.Errorf("HasPermission(%q, %q) = %v, want %v", role, tt.permission, got, want)
+				}
+			}
+			if HasPermission("staff", tt.permission) {
+				t.Errorf("unknown role has %q", tt.permission)
+			}
+		})
+	}
+}
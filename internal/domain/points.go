@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	PointsEntryEarn     = "earn"
+	PointsEntryRedeem   = "redeem"
+	PointsEntryRefund   = "refund"
+	PointsEntryReversal = "reversal"
+)
+
+// a customer's balance is the sum of their entries
+type PointsEntry struct {
+	ID             string    `db:"id"`
+	Sid            int       `db:"sid"`
+	CreatedAt      time.Time `db:"created_at"`
+	UserCustomerID string    `db:"user_customer_id"`
+	CheckoutID     *string   `db:"checkout_id"`
+	Type           string    `db:"type"`
+	Points         int       `db:"points"`
+}
+
+type PointsEntryResponse struct {
+	ID         string    `json:"id"`
+	CreatedAt  time.Time `json:"createdAt"`
+	CheckoutID string    `json:"checkoutId,omitempty"`
+	Type       string    `json:"type"`
+	Points     int       `json:"points"`
+}
+
+type PointsBalanceResponse struct {
+	CustomerID string `json:"customerId"`
+	Balance    int    `json:"balance"`
+}
+
+type PointsLedgerQueryParams struct {
+	Limit  string `form:"limit"`
+	Offset string `form:"offset"`
+}
+
+func NewPointsEntry(customerID string, checkoutID string, entryType string, points int, createdAt time.Time) PointsEntry {
+	id := uuid.New()
+
+	return PointsEntry{
+		ID:             id.String(),
+		CreatedAt:      createdAt,
+		UserCustomerID: customerID,
+		CheckoutID:     &checkoutID,
+		Type:           entryType,
+		Points:         points,
+	}
+}
+
+func (pe *PointsEntry) NewPointsEntryResponse() PointsEntryResponse {
+	response := PointsEntryResponse{
+		ID:        pe.ID,
+		CreatedAt: pe.CreatedAt,
+		Type:      pe.Type,
+		Points:    pe.Points,
+	}
+	if pe.CheckoutID != nil {
+		response.CheckoutID = *pe.CheckoutID
+	}
+
+	return response
+}
+
+func EarnedPoints(amount int, rate float64) int {
+	if amount <= 0 {
+		return 0
+	}
+
+	return int(math.Floor(float64(amount) * rate / 100))
+}
+
+func NewSalePointsEntries(checkout Checkout, payments []Payment, rate float64) []PointsEntry {
+	var entries []PointsEntry
+
+	redeemed := PaymentsAmount(payments, PaymentMethodPoints)
+	if redeemed > 0 {
+		entries = append(entries, NewPointsEntry(checkout.UserCustomerID, checkout.ID, PointsEntryRedeem, -redeemed, checkout.CreatedAt))
+	}
+	storeCredit := PaymentsAmount(payments, PaymentMethodStoreCredit)
+	earned := EarnedPoints(checkout.Total-redeemed-storeCredit, rate)
+	if earned > 0 {
+		entries = append(entries, NewPointsEntry(checkout.UserCustomerID, checkout.ID, PointsEntryEarn, earned, checkout.CreatedAt))
+	}
+
+	return entries
+}
+
+func NewReturnPointsEntries(sale Checkout, ret Checkout, payments []Payment, saleEntries []PointsEntry, earlierEntries []PointsEntry, returnedBefore int) []PointsEntry {
+	var entries []PointsEntry
+
+	refunded := -PaymentsAmount(payments, PaymentMethodPoints)
+	if refunded > 0 {
+		entries = append(entries, NewPointsEntry(ret.UserCustomerID, ret.ID, PointsEntryRefund, refunded, ret.CreatedAt))
+	}
+
+	earned := 0
+	for _, e := range saleEntries {
+		if e.Type == PointsEntryEarn {
+			earned += e.Points
+		}
+	}
+	reversed := 0
+	for _, e := range earlierEntries {
+		if e.Type == PointsEntryReversal {
+			reversed -= e.Points
+		}
+	}
+	// taken of the total returned so far and rounded down, so partial returns
+	// never take back more than the sale earned and the last one takes the rest
+	if sale.Total > 0 {
+		reversal := earned*(returnedBefore-ret.Total)/sale.Total - reversed
+		if reversal > 0 {
+			entries = append(entries, NewPointsEntry(ret.UserCustomerID, ret.ID, PointsEntryReversal, -reversal, ret.CreatedAt))
+		}
+	}
+
+	return entries
+}
+
+func NewVoidPointsEntries(saleEntries []PointsEntry, createdAt time.Time) []PointsEntry {
+	var entries []PointsEntry
+	for _, e := range saleEntries {
+		entryType := PointsEntryReversal
+		if e.Points < 0 {
+			entryType = PointsEntryRefund
+		}
+
+		entries = append(entries, NewPointsEntry(e.UserCustomerID, *e.CheckoutID, entryType, -e.Points, createdAt))
+	}
+
+	return entries
+}
+
+func (q *PointsLedgerQueryParams) LimitOffset() (int, int) {
+	limit := 5
+	qlimit, _ := strconv.Atoi(q.Limit)
+	if qlimit > 0 {
+		limit = qlimit
+	}
+
+	offset := 0
+	qoffset, _ := strconv.Atoi(q.Offset)
+	if qoffset > 0 {
+		offset = qoffset
+	}
+
+	return limit, offset
+}
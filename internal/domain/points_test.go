@@ -0,0 +1,264 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEarnedPoints(t *testing.T) {
+	tests := []struct {
+		amount int
+		rate   float64
+		want   int
+	}{
+		{amount: 10000, rate: 1, want: 100},
+		{amount: 199, rate: 1, want: 1},
+		{amount: 99, rate: 1, want: 0},
+		{amount: 1000, rate: 2.5, want: 25},
+		{amount: 1010, rate: 2.5, want: 25},
+		{amount: 10000, rate: 0, want: 0},
+		{amount: 0, rate: 1, want: 0},
+		{amount: -5000, rate: 1, want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := EarnedPoints(tt.amount, tt.rate); got != tt.want {
+			t.Errorf("EarnedPoints(%d, %v) = %d, want %d", tt.amount, tt.rate, got, tt.want)
+		}
+	}
+}
+
+func TestNewSalePointsEntries(t *testing.T) {
+	tests := []struct {
+		name       string
+		total      int
+		payments   []Payment
+		rate       float64
+		wantRedeem int
+		wantEarn   int
+	}{
+		{
+			name:     "cash",
+			total:    10000,
+			payments: []Payment{{Method: PaymentMethodCash, Amount: 10000}},
+			rate:     1,
+			wantEarn: 100,
+		},
+		{
+			name:     "rounded down",
+			total:    199,
+			payments: []Payment{{Method: PaymentMethodCash, Amount: 199}},
+			rate:     1,
+			wantEarn: 1,
+		},
+		{
+			name:       "points don't earn points",
+			total:      10000,
+			payments:   []Payment{{Method: PaymentMethodPoints, Amount: 4000}, {Method: PaymentMethodQris, Amount: 6000}},
+			rate:       1,
+			wantRedeem: -4000,
+			wantEarn:   60,
+		},
+		{
+			name:     "store credit doesn't earn points",
+			total:    10000,
+			payments: []Payment{{Method: PaymentMethodStoreCredit, Amount: 7000}, {Method: PaymentMethodCash, Amount: 3000}},
+			rate:     1,
+			wantEarn: 30,
+		},
+		{
+			name:  "points and store credit",
+			total: 10000,
+			payments: []Payment{
+				{Method: PaymentMethodPoints, Amount: 5000},
+				{Method: PaymentMethodStoreCredit, Amount: 5000},
+			},
+			rate:       1,
+			wantRedeem: -5000,
+		},
+		{
+			name:     "higher rate",
+			total:    10000,
+			payments: []Payment{{Method: PaymentMethodDebitCard, Amount: 10000}},
+			rate:     2.5,
+			wantEarn: 250,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkout := Checkout{ID: "sale", UserCustomerID: "customer", Total: tt.total}
+			entries := NewSalePointsEntries(checkout, tt.payments, tt.rate)
+
+			got := map[string]int{}
+			for _, e := range entries {
+				got[e.Type] += e.Points
+			}
+			if got[PointsEntryRedeem] != tt.wantRedeem {
+				t.Errorf("redeemed %d, want %d", got[PointsEntryRedeem], tt.wantRedeem)
+			}
+			if got[PointsEntryEarn] != tt.wantEarn {
+				t.Errorf("earned %d, want %d", got[PointsEntryEarn], tt.wantEarn)
+			}
+		})
+	}
+}
+
+func TestNewReturnPointsEntries(t *testing.T) {
+	tests := []struct {
+		name         string
+		saleTotal    int
+		returnTotal  int
+		refunds      []Payment
+		saleEntries  []PointsEntry
+		wantRefund   int
+		wantReversal int
+	}{
+		{
+			name:         "whole return takes back everything earned",
+			saleTotal:    10000,
+			returnTotal:  -10000,
+			saleEntries:  []PointsEntry{{Type: PointsEntryEarn, Points: 100}},
+			wantReversal: -100,
+		},
+		{
+			name:         "partial return takes back a share rounded down",
+			saleTotal:    3000,
+			returnTotal:  -1000,
+			saleEntries:  []PointsEntry{{Type: PointsEntryEarn, Points: 29}},
+			wantReversal: -9,
+		},
+		{
+			name:         "points tendered are refunded",
+			saleTotal:    10000,
+			returnTotal:  -5000,
+			refunds:      []Payment{{Method: PaymentMethodPoints, Amount: -2000}},
+			saleEntries:  []PointsEntry{{Type: PointsEntryRedeem, Points: -4000}, {Type: PointsEntryEarn, Points: 60}},
+			wantRefund:   2000,
+			wantReversal: -30,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sale := Checkout{ID: "sale", UserCustomerID: "customer", Total: tt.saleTotal}
+			ret := Checkout{ID: "return", UserCustomerID: "customer", Total: tt.returnTotal}
+			entries := NewReturnPointsEntries(sale, ret, tt.refunds, tt.saleEntries, nil, 0)
+
+			got := map[string]int{}
+			for _, e := range entries {
+				got[e.Type] += e.Points
+			}
+			if got[PointsEntryRefund] != tt.wantRefund {
+				t.Errorf("refunded %d, want %d", got[PointsEntryRefund], tt.wantRefund)
+			}
+			if got[PointsEntryReversal] != tt.wantReversal {
+				t.Errorf("reversed %d, want %d", got[PointsEntryReversal], tt.wantReversal)
+			}
+		})
+	}
+}
+
+func TestNewReturnPointsEntriesPartialReturns(t *testing.T) {
+	tests := []struct {
+		name    string
+		earned  int
+		returns []int
+	}{
+		{name: "three returns", earned: 7, returns: []int{3, 3, 4}},
+		{name: "thirds", earned: 29, returns: []int{1000, 1000, 1000}},
+		{name: "returns smaller than a point", earned: 2, returns: []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sale := Checkout{ID: "sale", UserCustomerID: "customer"}
+			for _, r := range tt.returns {
+				sale.Total += r
+			}
+			saleEntries := []PointsEntry{{Type: PointsEntryEarn, Points: tt.earned}}
+
+			var earlierEntries []PointsEntry
+			returnedBefore := 0
+			for i, r := range tt.returns {
+				ret := Checkout{ID: "return", UserCustomerID: "customer", Total: -r}
+				entries := NewReturnPointsEntries(sale, ret, nil, saleEntries, earlierEntries, returnedBefore)
+
+				earlierEntries = append(earlierEntries, entries...)
+				returnedBefore += r
+
+				reversed := 0
+				for _, e := range earlierEntries {
+					reversed -= e.Points
+				}
+				if earnedSoFar := tt.earned * returnedBefore / sale.Total; reversed > earnedSoFar {
+					t.Errorf("after return %d reversed %d, more than the %d earned so far", i, reversed, earnedSoFar)
+				}
+			}
+
+			reversed := 0
+			for _, e := range earlierEntries {
+				if e.Type != PointsEntryReversal {
+					t.Errorf("entry type = %s, want %s", e.Type, PointsEntryReversal)
+				}
+				reversed -= e.Points
+			}
+			if reversed != tt.earned {
+				t.Errorf("reversed %d, want %d", reversed, tt.earned)
+			}
+		})
+	}
+}
+
+func TestNewVoidPointsEntries(t *testing.T) {
+	checkoutID := "sale"
+	voidedAt := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		saleEntries []PointsEntry
+		wantTypes   []string
+		wantPoints  []int
+	}{
+		{name: "nothing to cancel"},
+		{
+			name:        "earned points are reversed",
+			saleEntries: []PointsEntry{{Type: PointsEntryEarn, Points: 100}},
+			wantTypes:   []string{PointsEntryReversal},
+			wantPoints:  []int{-100},
+		},
+		{
+			name:        "redeemed points are refunded",
+			saleEntries: []PointsEntry{{Type: PointsEntryRedeem, Points: -4000}, {Type: PointsEntryEarn, Points: 60}},
+			wantTypes:   []string{PointsEntryRefund, PointsEntryReversal},
+			wantPoints:  []int{4000, -60},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := range tt.saleEntries {
+				tt.saleEntries[i].UserCustomerID = "customer"
+				tt.saleEntries[i].CheckoutID = &checkoutID
+			}
+			entries := NewVoidPointsEntries(tt.saleEntries, voidedAt)
+			if len(entries) != len(tt.wantTypes) {
+				t.Fatalf("got %d entries, want %d", len(entries), len(tt.wantTypes))
+			}
+
+			balance := 0
+			for i, e := range entries {
+				if e.Type != tt.wantTypes[i] || e.Points != tt.wantPoints[i] {
+					t.Errorf("entries[%d] = %s %d, want %s %d", i, e.Type, e.Points, tt.wantTypes[i], tt.wantPoints[i])
+				}
+				if !e.CreatedAt.Equal(voidedAt) {
+					t.Errorf("entries[%d] created at %s", i, e.CreatedAt)
+				}
+				balance += e.Points + tt.saleEntries[i].Points
+			}
+			if balance != 0 {
+				t.Errorf("void leaves %d points behind", balance)
+			}
+		})
+	}
+}
@@ -53,17 +53,18 @@ type CreateProductResponse struct {
 }
 
 type ProductResponse struct {
-	ID          string    `json:"id"`
-	CreatedAt   time.Time `json:"createdAt"`
-	Name        string    `json:"name"`
-	Sku         string    `json:"sku"`
-	Category    string    `json:"category"`
-	ImageUrl    string    `json:"imageUrl"`
-	Notes       string    `json:"notes"`
-	Price       int       `json:"price"`
-	Stock       int       `json:"stock"`
-	Location    string    `json:"location"`
-	IsAvailable bool      `json:"isAvailable"`
+	ID           string    `json:"id"`
+	CreatedAt    time.Time `json:"createdAt"`
+	Name         string    `json:"name"`
+	Sku          string    `json:"sku"`
+	Category     string    `json:"category"`
+	ImageUrl     string    `json:"imageUrl"`
+	Notes        string    `json:"notes"`
+	Price        int       `json:"price"`
+	Stock        int       `json:"stock"`
+	DamagedStock int       `json:"damagedStock"`
+	Location     string    `json:"location"`
+	IsAvailable  bool      `json:"isAvailable"`
 }
 
 type ProductForCustomerResponse struct {
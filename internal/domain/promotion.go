@@ -0,0 +1,368 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	PromotionScopeProduct  = "product"
+	PromotionScopeCategory = "category"
+	PromotionScopeBasket   = "basket"
+)
+
+var (
+	PromotionTypePercentage  = "percentage"
+	PromotionTypeFixed       = "fixed"
+	PromotionTypeBundlePrice = "bundle_price"
+	PromotionTypeBuyXGetY    = "buy_x_get_y"
+)
+
+type Promotion struct {
+	ID          string     `db:"id"`
+	Sid         int        `db:"sid"`
+	CreatedAt   time.Time  `db:"created_at"`
+	Name        string     `db:"name"`
+	Scope       string     `db:"scope"`
+	ProductID   *string    `db:"product_id"`
+	Category    *string    `db:"category"`
+	Type        string     `db:"type"`
+	Value       int        `db:"value"`
+	BuyQuantity int        `db:"buy_quantity"`
+	GetQuantity int        `db:"get_quantity"`
+	StartsAt    *time.Time `db:"starts_at"`
+	EndsAt      *time.Time `db:"ends_at"`
+	StartHour   *int       `db:"start_hour"`
+	EndHour     *int       `db:"end_hour"`
+	Priority    int        `db:"priority"`
+	Stackable   bool       `db:"stackable"`
+	IsActive    bool       `db:"is_active"`
+
+	// set when the promotion stands in for a redeemed coupon
+	couponID string
+}
+
+type PromotionRequest struct {
+	Name        string     `json:"name" binding:"required,gte=1,lte=50"`
+	Scope       string     `json:"scope" binding:"required,oneof=product category basket"`
+	ProductID   string     `json:"productId" binding:"omitempty,uuid"`
+	Category    string     `json:"category" binding:"omitempty,oneof=Clothing Accessories Footwear Beverages"`
+	Type        string     `json:"type" binding:"required,oneof=percentage fixed bundle_price buy_x_get_y"`
+	Value       int        `json:"value" binding:"min=0"`
+	BuyQuantity int        `json:"buyQuantity" binding:"min=0"`
+	GetQuantity int        `json:"getQuantity" binding:"min=0"`
+	StartsAt    *time.Time `json:"startsAt"`
+	EndsAt      *time.Time `json:"endsAt"`
+	StartHour   *int       `json:"startHour" binding:"omitempty,min=0,max=23"`
+	EndHour     *int       `json:"endHour" binding:"omitempty,min=1,max=24"`
+	Priority    int        `json:"priority"`
+	Stackable   *bool      `json:"stackable" binding:"required"`
+	IsActive    *bool      `json:"isActive" binding:"required"`
+}
+
+type PromotionResponse struct {
+	ID          string     `json:"id"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	Name        string     `json:"name"`
+	Scope       string     `json:"scope"`
+	ProductID   string     `json:"productId,omitempty"`
+	Category    string     `json:"category,omitempty"`
+	Type        string     `json:"type"`
+	Value       int        `json:"value"`
+	BuyQuantity int        `json:"buyQuantity"`
+	GetQuantity int        `json:"getQuantity"`
+	StartsAt    *time.Time `json:"startsAt"`
+	EndsAt      *time.Time `json:"endsAt"`
+	StartHour   *int       `json:"startHour"`
+	EndHour     *int       `json:"endHour"`
+	Priority    int        `json:"priority"`
+	Stackable   bool       `json:"stackable"`
+	IsActive    bool       `json:"isActive"`
+}
+
+type AppliedPromotion struct {
+	ID                string  `db:"id"`
+	Sid               int     `db:"sid"`
+	ProductCheckoutID string  `db:"product_checkout_id"`
+	PromotionID       *string `db:"promotion_id"`
+	CouponID          *string `db:"coupon_id"`
+	PromotionName     string  `db:"promotion_name"`
+	Discount          int     `db:"discount"`
+}
+
+type AppliedPromotionResponse struct {
+	PromotionID string `json:"promotionId,omitempty"`
+	CouponID    string `json:"couponId,omitempty"`
+	Name        string `json:"name"`
+	Discount    int    `json:"discount"`
+}
+
+type PromotionReportQueryParams struct {
+	From string `form:"from"`
+	To   string `form:"to"`
+}
+
+type PromotionReport struct {
+	PromotionID   string `json:"promotionId"`
+	Name          string `json:"name"`
+	CheckoutCount int    `json:"checkoutCount"`
+	Quantity      int    `json:"quantity"`
+	Discount      int    `json:"discount"`
+}
+
+func (pr *PromotionRequest) NewPromotion() Promotion {
+	id := uuid.New()
+	rawCreatedAt := time.Now().Format(time.RFC3339)
+	createdAt, _ := time.Parse(time.RFC3339, rawCreatedAt)
+
+	promotion := Promotion{
+		ID:          id.String(),
+		CreatedAt:   createdAt,
+		Name:        pr.Name,
+		Scope:       pr.Scope,
+		Type:        pr.Type,
+		Value:       pr.Value,
+		BuyQuantity: pr.BuyQuantity,
+		GetQuantity: pr.GetQuantity,
+		StartsAt:    pr.StartsAt,
+		EndsAt:      pr.EndsAt,
+		StartHour:   pr.StartHour,
+		EndHour:     pr.EndHour,
+		Priority:    pr.Priority,
+		Stackable:   *pr.Stackable,
+		IsActive:    *pr.IsActive,
+	}
+	if len(pr.ProductID) > 0 {
+		promotion.ProductID = &pr.ProductID
+	}
+	if len(pr.Category) > 0 {
+		promotion.Category = &pr.Category
+	}
+
+	return promotion
+}
+
+func (p *Promotion) NewPromotionResponse() PromotionResponse {
+	response := PromotionResponse{
+		ID:          p.ID,
+		CreatedAt:   p.CreatedAt,
+		Name:        p.Name,
+		Scope:       p.Scope,
+		Type:        p.Type,
+		Value:       p.Value,
+		BuyQuantity: p.BuyQuantity,
+		GetQuantity: p.GetQuantity,
+		StartsAt:    p.StartsAt,
+		EndsAt:      p.EndsAt,
+		StartHour:   p.StartHour,
+		EndHour:     p.EndHour,
+		Priority:    p.Priority,
+		Stackable:   p.Stackable,
+		IsActive:    p.IsActive,
+	}
+	if p.ProductID != nil {
+		response.ProductID = *p.ProductID
+	}
+	if p.Category != nil {
+		response.Category = *p.Category
+	}
+
+	return response
+}
+
+func (p *Promotion) Validate() string {
+	switch p.Scope {
+	case PromotionScopeProduct:
+		if p.ProductID == nil || p.Category != nil {
+			return "product scope requires productId only"
+		}
+	case PromotionScopeCategory:
+		if p.Category == nil || p.ProductID != nil {
+			return "category scope requires category only"
+		}
+	case PromotionScopeBasket:
+		if p.Category != nil || p.ProductID != nil {
+			return "basket scope takes neither productId nor category"
+		}
+		if p.Type != PromotionTypePercentage && p.Type != PromotionTypeFixed {
+			return "basket scope only supports percentage and fixed promotions"
+		}
+	}
+
+	switch p.Type {
+	case PromotionTypePercentage:
+		if p.Value < 1 || p.Value > 100 {
+			return "percentage value should be between 1 and 100"
+		}
+	case PromotionTypeFixed:
+		if p.Value < 1 {
+			return "fixed value should be at least 1"
+		}
+	case PromotionTypeBundlePrice:
+		if p.BuyQuantity < 2 {
+			return "bundle_price requires buyQuantity of at least 2"
+		}
+	case PromotionTypeBuyXGetY:
+		if p.BuyQuantity < 1 || p.GetQuantity < 1 {
+			return "buy_x_get_y requires buyQuantity and getQuantity of at least 1"
+		}
+	}
+
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return "endsAt should be after startsAt"
+	}
+	if (p.StartHour == nil) != (p.EndHour == nil) {
+		return "startHour and endHour should be set together"
+	}
+	if p.StartHour != nil && *p.StartHour >= *p.EndHour {
+		return "startHour should be before endHour"
+	}
+
+	return ""
+}
+
+func (p *Promotion) RunsAt(now time.Time) bool {
+	if !p.IsActive {
+		return false
+	}
+	if p.StartsAt != nil && now.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !now.Before(*p.EndsAt) {
+		return false
+	}
+	// now is in the store timezone
+	if p.StartHour != nil && (now.Hour() < *p.StartHour || now.Hour() >= *p.EndHour) {
+		return false
+	}
+
+	return true
+}
+
+func (p *Promotion) covers(pc ProductCheckout, category string) bool {
+	switch p.Scope {
+	case PromotionScopeProduct:
+		return *p.ProductID == pc.ProductID
+	case PromotionScopeCategory:
+		return *p.Category == category
+	}
+
+	return true
+}
+
+func (p *Promotion) lineDiscount(pc ProductCheckout) int {
+	remaining := pc.Price*pc.Quantity - pc.Discount
+
+	switch p.Type {
+	case PromotionTypePercentage:
+		return int(math.Round(float64(remaining) * float64(p.Value) / 100))
+	case PromotionTypeFixed:
+		return p.Value * pc.Quantity
+	case PromotionTypeBundlePrice:
+		bundles := pc.Quantity / p.BuyQuantity
+		return bundles * (pc.Price*p.BuyQuantity - p.Value)
+	case PromotionTypeBuyXGetY:
+		groups := pc.Quantity / (p.BuyQuantity + p.GetQuantity)
+		return groups * p.GetQuantity * pc.Price
+	}
+
+	return 0
+}
+
+func ApplyPromotions(productCheckouts []ProductCheckout, categories map[string]string, promotions []Promotion, now time.Time) {
+	sort.SliceStable(promotions, func(i, j int) bool {
+		return promotions[i].Priority > promotions[j].Priority
+	})
+
+	locked := make([]bool, len(productCheckouts))
+	for _, p := range promotions {
+		if !p.RunsAt(now) {
+			continue
+		}
+
+		var lineIdxs []int
+		for i, pc := range productCheckouts {
+			if !p.covers(pc, categories[pc.ProductID]) || locked[i] {
+				continue
+			}
+			// a line only takes more than one promotion while all of them stack
+			if len(pc.Promotions) > 0 && !p.Stackable {
+				continue
+			}
+			lineIdxs = append(lineIdxs, i)
+		}
+
+		discounts := map[int]int{}
+		if p.Scope == PromotionScopeBasket && p.Type == PromotionTypeFixed {
+			basketRemaining := 0
+			for _, i := range lineIdxs {
+				basketRemaining += productCheckouts[i].Price*productCheckouts[i].Quantity - productCheckouts[i].Discount
+			}
+			basketDiscount := min(p.Value, basketRemaining)
+
+			spread := 0
+			for n, i := range lineIdxs {
+				pc := productCheckouts[i]
+				discount := Prorate(basketDiscount, pc.Price*pc.Quantity-pc.Discount, basketRemaining)
+				if n == len(lineIdxs)-1 {
+					discount = basketDiscount - spread
+				}
+				spread += discount
+				discounts[i] = discount
+			}
+		} else {
+			for _, i := range lineIdxs {
+				discounts[i] = p.lineDiscount(productCheckouts[i])
+			}
+		}
+
+		for _, i := range lineIdxs {
+			pc := &productCheckouts[i]
+			discount := min(discounts[i], pc.Price*pc.Quantity-pc.Discount)
+			if discount <= 0 {
+				continue
+			}
+
+			appliedPromotion := AppliedPromotion{
+				ID:                uuid.New().String(),
+				ProductCheckoutID: pc.ID,
+				PromotionName:     p.Name,
+				Discount:          discount,
+			}
+			if len(p.couponID) > 0 {
+				appliedPromotion.CouponID = &p.couponID
+			} else {
+				appliedPromotion.PromotionID = &p.ID
+			}
+
+			pc.Discount += discount
+			pc.Promotions = append(pc.Promotions, appliedPromotion)
+			if !p.Stackable {
+				locked[i] = true
+			}
+		}
+	}
+}
+
+func NewAppliedPromotionResponses(appliedPromotions []AppliedPromotion) []AppliedPromotionResponse {
+	responses := []AppliedPromotionResponse{}
+	for _, ap := range appliedPromotions {
+		response := AppliedPromotionResponse{
+			Name:     ap.PromotionName,
+			Discount: ap.Discount,
+		}
+		if ap.PromotionID != nil {
+			response.PromotionID = *ap.PromotionID
+		}
+		if ap.CouponID != nil {
+			response.CouponID = *ap.CouponID
+		}
+
+		responses = append(responses, response)
+	}
+
+	return responses
+}
@@ -0,0 +1,219 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPromotionValidate(t *testing.T) {
+	productID := "a"
+	category := ProductCategoryClothing
+	startHour, endHour := 9, 17
+	startsAt := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	endsAt := startsAt.Add(-time.Hour)
+
+	tests := []struct {
+		name      string
+		promotion Promotion
+		want      string
+	}{
+		{
+			name:      "valid product percentage",
+			promotion: Promotion{Scope: PromotionScopeProduct, ProductID: &productID, Type: PromotionTypePercentage, Value: 10},
+		},
+		{
+			name:      "product scope without product",
+			promotion: Promotion{Scope: PromotionScopeProduct, Type: PromotionTypePercentage, Value: 10},
+			want:      "product scope requires productId only",
+		},
+		{
+			name:      "category scope with product",
+			promotion: Promotion{Scope: PromotionScopeCategory, Category: &category, ProductID: &productID, Type: PromotionTypeFixed, Value: 100},
+			want:      "category scope requires category only",
+		},
+		{
+			name:      "basket bundle",
+			promotion: Promotion{Scope: PromotionScopeBasket, Type: PromotionTypeBundlePrice, BuyQuantity: 2, Value: 100},
+			want:      "basket scope only supports percentage and fixed promotions",
+		},
+		{
+			name:      "percentage over 100",
+			promotion: Promotion{Scope: PromotionScopeBasket, Type: PromotionTypePercentage, Value: 101},
+			want:      "percentage value should be between 1 and 100",
+		},
+		{
+			name:      "bundle of one",
+			promotion: Promotion{Scope: PromotionScopeProduct, ProductID: &productID, Type: PromotionTypeBundlePrice, BuyQuantity: 1},
+			want:      "bundle_price requires buyQuantity of at least 2",
+		},
+		{
+			name:      "buy x get nothing",
+			promotion: Promotion{Scope: PromotionScopeProduct, ProductID: &productID, Type: PromotionTypeBuyXGetY, BuyQuantity: 2},
+			want:      "buy_x_get_y requires buyQuantity and getQuantity of at least 1",
+		},
+		{
+			name:      "ends before it starts",
+			promotion: Promotion{Scope: PromotionScopeBasket, Type: PromotionTypeFixed, Value: 100, StartsAt: &startsAt, EndsAt: &endsAt},
+			want:      "endsAt should be after startsAt",
+		},
+		{
+			name:      "start hour alone",
+			promotion: Promotion{Scope: PromotionScopeBasket, Type: PromotionTypeFixed, Value: 100, StartHour: &startHour},
+			want:      "startHour and endHour should be set together",
+		},
+		{
+			name:      "hours reversed",
+			promotion: Promotion{Scope: PromotionScopeBasket, Type: PromotionTypeFixed, Value: 100, StartHour: &endHour, EndHour: &startHour},
+			want:      "startHour should be before endHour",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.promotion.Validate(); got != tt.want {
+				t.Errorf("Validate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromotionRunsAt(t *testing.T) {
+	now := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	before, after := now.Add(-time.Hour), now.Add(time.Hour)
+	hour := func(h int) *int { return &h }
+
+	tests := []struct {
+		name      string
+		promotion Promotion
+		want      bool
+	}{
+		{name: "always on", promotion: Promotion{IsActive: true}, want: true},
+		{name: "inactive", promotion: Promotion{}, want: false},
+		{name: "not started", promotion: Promotion{IsActive: true, StartsAt: &after}, want: false},
+		{name: "ended", promotion: Promotion{IsActive: true, EndsAt: &now}, want: false},
+		{name: "within dates", promotion: Promotion{IsActive: true, StartsAt: &before, EndsAt: &after}, want: true},
+		{name: "within hours", promotion: Promotion{IsActive: true, StartHour: hour(10), EndHour: hour(11)}, want: true},
+		{name: "before hours", promotion: Promotion{IsActive: true, StartHour: hour(11), EndHour: hour(17)}, want: false},
+		{name: "end hour excluded", promotion: Promotion{IsActive: true, StartHour: hour(8), EndHour: hour(10)}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.promotion.RunsAt(now); got != tt.want {
+				t.Errorf("RunsAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyPromotions(t *testing.T) {
+	now := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	productA, productB := "a", "b"
+	beverages := ProductCategoryBeverages
+	categories := map[string]string{productA: ProductCategoryClothing, productB: ProductCategoryBeverages}
+
+	tests := []struct {
+		name          string
+		promotions    []Promotion
+		wantDiscounts []int
+	}{
+		{
+			name:          "percentage on a product",
+			promotions:    []Promotion{{Scope: PromotionScopeProduct, ProductID: &productA, Type: PromotionTypePercentage, Value: 10, IsActive: true}},
+			wantDiscounts: []int{300, 0},
+		},
+		{
+			name:          "fixed per unit on a category",
+			promotions:    []Promotion{{Scope: PromotionScopeCategory, Category: &beverages, Type: PromotionTypeFixed, Value: 100, IsActive: true}},
+			wantDiscounts: []int{0, 200},
+		},
+		{
+			name:          "fixed capped at the line",
+			promotions:    []Promotion{{Scope: PromotionScopeProduct, ProductID: &productB, Type: PromotionTypeFixed, Value: 600, IsActive: true}},
+			wantDiscounts: []int{0, 1000},
+		},
+		{
+			name:          "bundle price",
+			promotions:    []Promotion{{Scope: PromotionScopeProduct, ProductID: &productA, Type: PromotionTypeBundlePrice, BuyQuantity: 2, Value: 1500, IsActive: true}},
+			wantDiscounts: []int{500, 0},
+		},
+		{
+			name:          "buy two get one",
+			promotions:    []Promotion{{Scope: PromotionScopeProduct, ProductID: &productA, Type: PromotionTypeBuyXGetY, BuyQuantity: 2, GetQuantity: 1, IsActive: true}},
+			wantDiscounts: []int{1000, 0},
+		},
+		{
+			name: "stackable promotions compound",
+			promotions: []Promotion{
+				{Scope: PromotionScopeBasket, Type: PromotionTypePercentage, Value: 10, Priority: 1, Stackable: true, IsActive: true},
+				{Scope: PromotionScopeProduct, ProductID: &productA, Type: PromotionTypePercentage, Value: 10, Priority: 2, Stackable: true, IsActive: true},
+			},
+			wantDiscounts: []int{570, 100},
+		},
+		{
+			name: "non-stackable locks the line",
+			promotions: []Promotion{
+				{Scope: PromotionScopeProduct, ProductID: &productA, Type: PromotionTypePercentage, Value: 10, Priority: 2, IsActive: true},
+				{Scope: PromotionScopeBasket, Type: PromotionTypePercentage, Value: 50, Priority: 1, Stackable: true, IsActive: true},
+			},
+			wantDiscounts: []int{300, 500},
+		},
+		{
+			name: "non-stackable skips discounted lines",
+			promotions: []Promotion{
+				{Scope: PromotionScopeProduct, ProductID: &productA, Type: PromotionTypePercentage, Value: 10, Priority: 2, Stackable: true, IsActive: true},
+				{Scope: PromotionScopeBasket, Type: PromotionTypePercentage, Value: 50, Priority: 1, IsActive: true},
+			},
+			wantDiscounts: []int{300, 500},
+		},
+		{
+			name: "highest priority goes first",
+			promotions: []Promotion{
+				{Scope: PromotionScopeProduct, ProductID: &productA, Type: PromotionTypePercentage, Value: 50, Priority: 1, IsActive: true},
+				{Scope: PromotionScopeProduct, ProductID: &productA, Type: PromotionTypeFixed, Value: 100, Priority: 5, IsActive: true},
+			},
+			wantDiscounts: []int{300, 0},
+		},
+		{
+			name:          "fixed basket spread over the lines",
+			promotions:    []Promotion{{Scope: PromotionScopeBasket, Type: PromotionTypeFixed, Value: 333, IsActive: true}},
+			wantDiscounts: []int{250, 83},
+		},
+		{
+			name:          "fixed basket capped at the basket",
+			promotions:    []Promotion{{Scope: PromotionScopeBasket, Type: PromotionTypeFixed, Value: 10000, IsActive: true}},
+			wantDiscounts: []int{3000, 1000},
+		},
+		{
+			name:          "inactive promotion",
+			promotions:    []Promotion{{Scope: PromotionScopeBasket, Type: PromotionTypePercentage, Value: 10}},
+			wantDiscounts: []int{0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			productCheckouts := []ProductCheckout{
+				{ID: "line-a", ProductID: productA, Price: 1000, Quantity: 3},
+				{ID: "line-b", ProductID: productB, Price: 500, Quantity: 2},
+			}
+			ApplyPromotions(productCheckouts, categories, tt.promotions, now)
+
+			for i, pc := range productCheckouts {
+				if pc.Discount != tt.wantDiscounts[i] {
+					t.Errorf("%s discount = %d, want %d", pc.ID, pc.Discount, tt.wantDiscounts[i])
+				}
+				applied := 0
+				for _, ap := range pc.Promotions {
+					applied += ap.Discount
+					if ap.ProductCheckoutID != pc.ID {
+						t.Errorf("%s has a promotion of line %s", pc.ID, ap.ProductCheckoutID)
+					}
+				}
+				if applied != pc.Discount {
+					t.Errorf("%s applied promotions add up to %d, want %d", pc.ID, applied, pc.Discount)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"time"
+)
+
+const ZReportTopProducts = 10
+
+type ZReportQueryParams struct {
+	Date string `form:"date"`
+}
+
+type ZReportCloseRequest struct {
+	UserAdminID string `json:"-"`
+	Date        string `json:"date" binding:"required"`
+}
+
+// voided checkouts are left out
+type ZReportTotals struct {
+	SaleCount     int
+	ReturnCount   int
+	GrossSales    int
+	Discounts     int
+	SalesTax      int
+	SalesTotal    int
+	ReturnsTax    int
+	ReturnsTotal  int
+	GiftCardSales int
+	Payments      map[string]int
+	Change        int
+	TopProducts   []ZReportProduct
+}
+
+type ZReportProduct struct {
+	ProductID string `json:"productId"`
+	Name      string `json:"name"`
+	Sku       string `json:"sku"`
+	Quantity  int    `json:"quantity"`
+	Total     int    `json:"total"`
+}
+
+type ZReport struct {
+	Date             string           `json:"date"`
+	Timezone         string           `json:"timezone"`
+	From             time.Time        `json:"from"`
+	To               time.Time        `json:"to"`
+	ClosedAt         *time.Time       `json:"closedAt"`
+	ClosedBy         string           `json:"closedBy,omitempty"`
+	GrossSales       int              `json:"grossSales"`
+	Discounts        int              `json:"discounts"`
+	Returns          int              `json:"returns"`
+	Tax              int              `json:"tax"`
+	NetSales         int              `json:"netSales"`
+	GiftCardSales    int              `json:"giftCardSales"`
+	TransactionCount int              `json:"transactionCount"`
+	ReturnCount      int              `json:"returnCount"`
+	AverageBasket    int              `json:"averageBasket"`
+	Tenders          []TenderTotal    `json:"tenders"`
+	TopProducts      []ZReportProduct `json:"topProducts"`
+}
+
+func ParseBusinessDay(date string, location *time.Location) (time.Time, time.Time, bool) {
+	from, err := time.ParseInLocation(time.DateOnly, date, location)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	return from, from.AddDate(0, 0, 1), true
+}
+
+func NewZReport(date string, from time.Time, to time.Time, totals ZReportTotals) ZReport {
+	// net sales are after discounts and returns, without tax
+	report := ZReport{
+		Date:             date,
+		Timezone:         from.Location().String(),
+		From:             from,
+		To:               to,
+		GrossSales:       totals.GrossSales,
+		Discounts:        totals.Discounts,
+		Returns:          -totals.ReturnsTotal,
+		Tax:              totals.SalesTax + totals.ReturnsTax,
+		NetSales:         totals.SalesTotal - totals.SalesTax + totals.ReturnsTotal - totals.ReturnsTax,
+		GiftCardSales:    totals.GiftCardSales,
+		TransactionCount: totals.SaleCount,
+		ReturnCount:      totals.ReturnCount,
+		TopProducts:      totals.TopProducts,
+	}
+	if totals.SaleCount > 0 {
+		report.AverageBasket = totals.SalesTotal / totals.SaleCount
+	}
+	if report.TopProducts == nil {
+		report.TopProducts = []ZReportProduct{}
+	}
+	report.Tenders = NewTenderTotals(totals.Payments, totals.Change)
+
+	return report
+}
+
+func (zr *ZReport) Close(userAdminID string) {
+	rawClosedAt := time.Now().Format(time.RFC3339)
+	closedAt, _ := time.Parse(time.RFC3339, rawClosedAt)
+
+	zr.ClosedAt = &closedAt
+	zr.ClosedBy = userAdminID
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseBusinessDay(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+
+	tests := []struct {
+		name     string
+		date     string
+		location *time.Location
+		wantFrom time.Time
+		wantOk   bool
+	}{
+		{name: "utc", date: "2024-06-01", location: time.UTC, wantFrom: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), wantOk: true},
+		{name: "store timezone", date: "2024-06-01", location: jakarta, wantFrom: time.Date(2024, 5, 31, 17, 0, 0, 0, time.UTC), wantOk: true},
+		{name: "timestamp", date: "2024-06-01T00:00:00Z", location: time.UTC},
+		{name: "not a date", date: "2024-13-01", location: time.UTC},
+		{name: "empty", date: "", location: time.UTC},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, ok := ParseBusinessDay(tt.date, tt.location)
+			if ok != tt.wantOk {
+				t.Fatalf("ParseBusinessDay(%q) ok = %v, want %v", tt.date, ok, tt.wantOk)
+			}
+			if !ok {
+				return
+			}
+			if !from.Equal(tt.wantFrom) {
+				t.Errorf("from = %s, want %s", from.UTC(), tt.wantFrom)
+			}
+			if to.Sub(from) != 24*time.Hour {
+				t.Errorf("business day lasts %s", to.Sub(from))
+			}
+		})
+	}
+}
+
+func TestNewZReport(t *testing.T) {
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+
+	tests := []struct {
+		name              string
+		totals            ZReportTotals
+		wantReturns       int
+		wantTax           int
+		wantNetSales      int
+		wantAverageBasket int
+		wantTenders       []TenderTotal
+	}{
+		{
+			name:        "quiet day",
+			wantTenders: []TenderTotal{{Method: PaymentMethodCash}},
+		},
+		{
+			name: "sales and a return",
+			totals: ZReportTotals{
+				SaleCount:    3,
+				ReturnCount:  1,
+				GrossSales:   10000,
+				Discounts:    1000,
+				SalesTax:     900,
+				SalesTotal:   9900,
+				ReturnsTax:   -90,
+				ReturnsTotal: -990,
+				Payments:     map[string]int{PaymentMethodCash: 11000, PaymentMethodQris: -990},
+				Change:       1100,
+			},
+			wantReturns:       990,
+			wantTax:           810,
+			wantNetSales:      8100,
+			wantAverageBasket: 3300,
+			wantTenders:       []TenderTotal{{Method: PaymentMethodCash, Amount: 9900}, {Method: PaymentMethodQris, Amount: -990}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := NewZReport("2024-06-01", from, to, tt.totals)
+
+			if report.Returns != tt.wantReturns || report.Tax != tt.wantTax || report.NetSales != tt.wantNetSales {
+				t.Errorf("returns %d, tax %d, net sales %d, want %d, %d, %d", report.Returns, report.Tax, report.NetSales, tt.wantReturns, tt.wantTax, tt.wantNetSales)
+			}
+			if report.AverageBasket != tt.wantAverageBasket {
+				t.Errorf("average basket = %d, want %d", report.AverageBasket, tt.wantAverageBasket)
+			}
+			if len(report.Tenders) != len(tt.wantTenders) {
+				t.Fatalf("tenders = %v, want %v", report.Tenders, tt.wantTenders)
+			}
+			for i := range report.Tenders {
+				if report.Tenders[i] != tt.wantTenders[i] {
+					t.Errorf("tenders[%d] = %v, want %v", i, report.Tenders[i], tt.wantTenders[i])
+				}
+			}
+			if report.TopProducts == nil {
+				t.Errorf("top products are null")
+			}
+		})
+	}
+}
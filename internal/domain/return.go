@@ -0,0 +1,186 @@
+package domain
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ReturnReasonDefective   = "defective"
+	ReturnReasonWrongItem   = "wrong_item"
+	ReturnReasonChangedMind = "changed_mind"
+	ReturnReasonExpired     = "expired"
+	ReturnReasonOther       = "other"
+)
+
+var ReturnReason = []string{
+	ReturnReasonDefective,
+	ReturnReasonWrongItem,
+	ReturnReasonChangedMind,
+	ReturnReasonExpired,
+	ReturnReasonOther,
+}
+
+var (
+	RestockStateSellable = "sellable"
+	RestockStateDamaged  = "damaged"
+)
+
+type ReturnProductRequest struct {
+	ProductID    string `json:"productId" binding:"required"`
+	Quantity     int    `json:"quantity" binding:"required,min=1,number"`
+	RestockState string `json:"restockState" binding:"omitempty,oneof=sellable damaged"`
+}
+
+type ReturnRequest struct {
+	UserAdminID    string                 `json:"-"`
+	Reason         string                 `json:"reason" binding:"required,oneof=defective wrong_item changed_mind expired other"`
+	ProductDetails []ReturnProductRequest `json:"productDetails" binding:"dive"`
+}
+
+func (rr *ReturnRequest) NewReturn(sale Checkout, soldProducts map[string]ProductCheckout, returnedQuantities map[string]int) (Checkout, []ProductCheckout) {
+	id := uuid.New()
+	rawCreatedAt := time.Now().Format(time.RFC3339)
+	createdAt, _ := time.Parse(time.RFC3339, rawCreatedAt)
+
+	change := 0
+	returnOf := sale.ID
+	reason := rr.Reason
+	checkout := Checkout{
+		ID:             id.String(),
+		CreatedAt:      createdAt,
+		UserCustomerID: sale.UserCustomerID,
+		UserAdminID:    rr.UserAdminID,
+		Type:           CheckoutTypeReturn,
+		ReturnOf:       &returnOf,
+		ReturnReason:   &reason,
+		Change:         &change,
+	}
+
+	returned := map[string]int{}
+	for productID, quantity := range returnedQuantities {
+		returned[productID] = quantity
+	}
+
+	var productCheckouts []ProductCheckout
+	for _, v := range rr.ProductDetails {
+		soldProduct := soldProducts[v.ProductID]
+		restockState := v.RestockState
+		// shares are taken of the quantity returned so far, so partial returns
+		// add up to exactly what was sold
+		prorate := func(amount int) int {
+			before := returned[v.ProductID]
+			return Prorate(amount, before+v.Quantity, soldProduct.Quantity) - Prorate(amount, before, soldProduct.Quantity)
+		}
+
+		id := uuid.New()
+		productCheckout := ProductCheckout{
+			ID:           id.String(),
+			ProductID:    v.ProductID,
+			Quantity:     -v.Quantity,
+			CheckoutID:   checkout.ID,
+			ProductName:  soldProduct.ProductName,
+			ProductSku:   soldProduct.ProductSku,
+			Price:        soldProduct.Price,
+			Discount:     -prorate(soldProduct.Discount),
+			RestockState: &restockState,
+			Tax:          -prorate(soldProduct.Tax),
+			TaxRate:      soldProduct.TaxRate,
+			TaxInclusive: soldProduct.TaxInclusive,
+			Total:        -prorate(soldProduct.Total),
+		}
+		returned[v.ProductID] += v.Quantity
+
+		productCheckouts = append(productCheckouts, productCheckout)
+	}
+	checkout.Subtotal, checkout.Discount, checkout.Tax, checkout.Total = SumProductCheckouts(productCheckouts)
+	checkout.Paid = checkout.Total
+
+	return checkout, productCheckouts
+}
+
+// refunded to the tender they were paid with, everything else in cash
+var RefundableMethods = []string{
+	PaymentMethodPoints,
+	PaymentMethodStoreCredit,
+}
+
+func NewRefundPayments(sale Checkout, ret Checkout, salePayments []Payment, earlierRefunds []Payment) []Payment {
+	refund := -ret.Total
+
+	type tender struct {
+		method     string
+		giftCardID *string
+		left       int
+	}
+	tenders := []tender{{method: PaymentMethodCash, left: sale.Total}}
+	for _, method := range RefundableMethods {
+		tenders = append(tenders, tender{method: method})
+	}
+	// every gift card gets its own share back
+	tenderOf := func(p Payment) *tender {
+		for i, t := range tenders {
+			if p.Method == PaymentMethodGiftCard && t.giftCardID != nil && *t.giftCardID == *p.GiftCardID {
+				return &tenders[i]
+			}
+			if p.Method != PaymentMethodGiftCard && t.method == p.Method {
+				return &tenders[i]
+			}
+		}
+		if p.Method == PaymentMethodGiftCard {
+			tenders = append(tenders, tender{method: p.Method, giftCardID: p.GiftCardID})
+			return &tenders[len(tenders)-1]
+		}
+
+		return &tenders[0]
+	}
+	for _, p := range salePayments {
+		if p.Method == PaymentMethodGiftCard || slices.Contains(RefundableMethods, p.Method) {
+			tenderOf(p).left += p.Amount
+			tenders[0].left -= p.Amount
+		}
+	}
+	for _, p := range earlierRefunds {
+		tenderOf(p).left += p.Amount
+	}
+
+	// split by what each tender has left to get back, so the last return
+	// settles every tender exactly
+	total := 0
+	for i := range tenders {
+		tenders[i].left = max(tenders[i].left, 0)
+		total += tenders[i].left
+	}
+	amounts := make([]int, len(tenders))
+	rest := refund
+	if total > 0 {
+		for i, t := range tenders {
+			amounts[i] = min(t.left*refund/total, t.left)
+			rest -= amounts[i]
+		}
+		// rounding leftovers go to cash first
+		for i, t := range tenders {
+			if rest > 0 && amounts[i] < t.left {
+				amounts[i]++
+				rest--
+			}
+		}
+	}
+	amounts[0] += rest
+
+	var payments []Payment
+	for i, t := range tenders {
+		if amounts[i] > 0 {
+			payment := NewPayment(ret.ID, t.method, -amounts[i], ret.CreatedAt)
+			payment.GiftCardID = t.giftCardID
+			payments = append(payments, payment)
+		}
+	}
+	if len(payments) == 0 {
+		payments = append(payments, NewPayment(ret.ID, PaymentMethodCash, -refund, ret.CreatedAt))
+	}
+
+	return payments
+}
@@ -0,0 +1,261 @@
+package domain
+
+import "testing"
+
+func TestNewRefundPayments(t *testing.T) {
+	giftCardID := "gift-card"
+
+	tests := []struct {
+		name         string
+		saleTotal    int
+		returnTotal  int
+		salePayments []Payment
+		want         map[string]int
+	}{
+		{
+			name:         "cash sale",
+			saleTotal:    10000,
+			returnTotal:  -4000,
+			salePayments: []Payment{{Method: PaymentMethodCash, Amount: 10000}},
+			want:         map[string]int{PaymentMethodCash: -4000},
+		},
+		{
+			name:        "points and store credit are refunded in proportion",
+			saleTotal:   10000,
+			returnTotal: -5000,
+			salePayments: []Payment{
+				{Method: PaymentMethodPoints, Amount: 2000},
+				{Method: PaymentMethodStoreCredit, Amount: 4000},
+				{Method: PaymentMethodQris, Amount: 4000},
+			},
+			want: map[string]int{PaymentMethodPoints: -1000, PaymentMethodStoreCredit: -2000, PaymentMethodCash: -2000},
+		},
+		{
+			name:        "shares are rounded down and the rest is cash",
+			saleTotal:   3000,
+			returnTotal: -1000,
+			salePayments: []Payment{
+				{Method: PaymentMethodStoreCredit, Amount: 1001},
+				{Method: PaymentMethodCash, Amount: 1999},
+			},
+			want: map[string]int{PaymentMethodStoreCredit: -333, PaymentMethodCash: -667},
+		},
+		{
+			name:        "gift card gets its share back",
+			saleTotal:   10000,
+			returnTotal: -10000,
+			salePayments: []Payment{
+				{Method: PaymentMethodGiftCard, Amount: 10000, GiftCardID: &giftCardID},
+			},
+			want: map[string]int{PaymentMethodGiftCard: -10000},
+		},
+		{
+			name:         "nothing to refund",
+			saleTotal:    0,
+			returnTotal:  0,
+			salePayments: []Payment{{Method: PaymentMethodCash, Amount: 0}},
+			want:         map[string]int{PaymentMethodCash: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sale := Checkout{ID: "sale", Total: tt.saleTotal}
+			ret := Checkout{ID: "return", Total: tt.returnTotal}
+
+			payments := NewRefundPayments(sale, ret, tt.salePayments, nil)
+
+			got := map[string]int{}
+			sum := 0
+			for _, p := range payments {
+				if p.CheckoutID != ret.ID {
+					t.Errorf("payment of checkout %s, want %s", p.CheckoutID, ret.ID)
+				}
+				if p.Method == PaymentMethodGiftCard && (p.GiftCardID == nil || *p.GiftCardID != giftCardID) {
+					t.Errorf("gift card refund is not tied to the gift card")
+				}
+				got[p.Method] += p.Amount
+				sum += p.Amount
+			}
+			if sum != tt.returnTotal {
+				t.Errorf("refunded %d, want %d", sum, tt.returnTotal)
+			}
+			for method, amount := range tt.want {
+				if got[method] != amount {
+					t.Errorf("%s = %d, want %d", method, got[method], amount)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("methods = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRefundPaymentsPartialReturns(t *testing.T) {
+	first, second := "first", "second"
+
+	tests := []struct {
+		name         string
+		salePayments []Payment
+		returns      []int
+		want         map[string]int
+	}{
+		{
+			name:         "store credit and cash",
+			salePayments: []Payment{{Method: PaymentMethodStoreCredit, Amount: 7}, {Method: PaymentMethodCash, Amount: 3}},
+			returns:      []int{3, 3, 4},
+			want:         map[string]int{PaymentMethodStoreCredit: -7, PaymentMethodCash: -3},
+		},
+		{
+			name:         "points and qris",
+			salePayments: []Payment{{Method: PaymentMethodPoints, Amount: 1001}, {Method: PaymentMethodQris, Amount: 1999}},
+			returns:      []int{1000, 1000, 1000},
+			want:         map[string]int{PaymentMethodPoints: -1001, PaymentMethodCash: -1999},
+		},
+		{
+			name: "two gift cards",
+			salePayments: []Payment{
+				{Method: PaymentMethodGiftCard, GiftCardID: &first, Amount: 5},
+				{Method: PaymentMethodGiftCard, GiftCardID: &second, Amount: 5},
+				{Method: PaymentMethodCash, Amount: 1},
+			},
+			returns: []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+			want:    map[string]int{first: -5, second: -5, PaymentMethodCash: -1},
+		},
+		{
+			name: "gift cards only",
+			salePayments: []Payment{
+				{Method: PaymentMethodGiftCard, GiftCardID: &first, Amount: 5},
+				{Method: PaymentMethodGiftCard, GiftCardID: &second, Amount: 5},
+			},
+			returns: []int{1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+			want:    map[string]int{first: -5, second: -5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sale := Checkout{ID: "sale"}
+			for _, p := range tt.salePayments {
+				sale.Total += p.Amount
+			}
+
+			got := map[string]int{}
+			var earlierRefunds []Payment
+			for _, refund := range tt.returns {
+				ret := Checkout{ID: "return", Total: -refund}
+				payments := NewRefundPayments(sale, ret, tt.salePayments, earlierRefunds)
+				sum := 0
+				for _, p := range payments {
+					if p.Amount > 0 {
+						t.Errorf("%s refund of %d is a charge", p.Method, p.Amount)
+					}
+					key := p.Method
+					if p.GiftCardID != nil {
+						key = *p.GiftCardID
+					}
+					got[key] += p.Amount
+					sum += p.Amount
+				}
+				if sum != -refund {
+					t.Errorf("return of %d refunded %d", refund, -sum)
+				}
+				earlierRefunds = append(earlierRefunds, payments...)
+			}
+
+			for key, amount := range tt.want {
+				if got[key] != amount {
+					t.Errorf("%s = %d, want %d", key, got[key], amount)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("refunds = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewReturnProration(t *testing.T) {
+	tests := []struct {
+		name         string
+		sold         ProductCheckout
+		returns      []int
+		wantTotals   []int
+		wantDiscount int
+		wantTax      int
+	}{
+		{
+			name:       "whole line",
+			sold:       ProductCheckout{ProductID: "p", Quantity: 2, Price: 5000, Total: 10000},
+			returns:    []int{2},
+			wantTotals: []int{-10000},
+		},
+		{
+			name:         "one by one adds up to what was sold",
+			sold:         ProductCheckout{ProductID: "p", Quantity: 3, Price: 100, Discount: 100, Tax: 20, Total: 200},
+			returns:      []int{1, 1, 1},
+			wantTotals:   []int{-67, -66, -67},
+			wantDiscount: -100,
+			wantTax:      -20,
+		},
+		{
+			name:         "uneven parts",
+			sold:         ProductCheckout{ProductID: "p", Quantity: 7, Price: 1000, Discount: 1000, Tax: 660, Total: 6660},
+			returns:      []int{2, 4, 1},
+			wantTotals:   []int{-1903, -3806, -951},
+			wantDiscount: -1000,
+			wantTax:      -660,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sale := Checkout{ID: "sale", UserCustomerID: "customer", Type: CheckoutTypeSale}
+			soldProducts := map[string]ProductCheckout{tt.sold.ProductID: tt.sold}
+			returned := map[string]int{}
+
+			var total, discount, tax int
+			for i, quantity := range tt.returns {
+				request := ReturnRequest{
+					Reason:         ReturnReasonDefective,
+					ProductDetails: []ReturnProductRequest{{ProductID: tt.sold.ProductID, Quantity: quantity, RestockState: RestockStateSellable}},
+				}
+				ret, lines := request.NewReturn(sale, soldProducts, returned)
+				returned[tt.sold.ProductID] += quantity
+
+				if ret.Type != CheckoutTypeReturn || ret.ReturnOf == nil || *ret.ReturnOf != sale.ID {
+					t.Errorf("return %d isn't linked to the sale", i)
+				}
+				if lines[0].Quantity != -quantity || lines[0].Price != tt.sold.Price {
+					t.Errorf("return %d line = %+v", i, lines[0])
+				}
+				if ret.Total != tt.wantTotals[i] {
+					t.Errorf("return %d total = %d, want %d", i, ret.Total, tt.wantTotals[i])
+				}
+				total += ret.Total
+				discount += ret.Discount
+				tax += ret.Tax
+			}
+
+			if total != -tt.sold.Total || discount != tt.wantDiscount || tax != tt.wantTax {
+				t.Errorf("returned total %d, discount %d, tax %d, want %d, %d, %d", total, discount, tax, -tt.sold.Total, tt.wantDiscount, tt.wantTax)
+			}
+		})
+	}
+}
+
+func TestNewReturnSameProductTwice(t *testing.T) {
+	sold := ProductCheckout{ProductID: "p", Quantity: 3, Price: 100, Total: 200}
+	request := ReturnRequest{
+		ProductDetails: []ReturnProductRequest{
+			{ProductID: "p", Quantity: 1, RestockState: RestockStateSellable},
+			{ProductID: "p", Quantity: 2, RestockState: RestockStateDamaged},
+		},
+	}
+
+	ret, lines := request.NewReturn(Checkout{ID: "sale"}, map[string]ProductCheckout{"p": sold}, nil)
+	if len(lines) != 2 || ret.Total != -200 {
+		t.Errorf("lines = %d, total = %d, want 2 lines refunding 200", len(lines), ret.Total)
+	}
+}
@@ -0,0 +1,209 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Shift struct {
+	ID            string         `db:"id"`
+	Sid           int            `db:"sid"`
+	UserAdminID   string         `db:"user_admin_id"`
+	Till          string         `db:"till"`
+	OpeningFloat  int            `db:"opening_float"`
+	OpenedAt      time.Time      `db:"opened_at"`
+	ClosedAt      *time.Time     `db:"closed_at"`
+	ExpectedCash  *int           `db:"expected_cash"`
+	CountedCash   *int           `db:"counted_cash"`
+	Denominations []Denomination `db:"denominations"`
+	Tenders       []TenderTotal  `db:"tenders"`
+	Notes         *string        `db:"notes"`
+}
+
+type CashPayout struct {
+	ID          string    `db:"id"`
+	Sid         int       `db:"sid"`
+	CreatedAt   time.Time `db:"created_at"`
+	ShiftID     string    `db:"shift_id"`
+	UserAdminID string    `db:"user_admin_id"`
+	Amount      int       `db:"amount"`
+	Reason      string    `db:"reason"`
+}
+
+type Denomination struct {
+	Value int `json:"value" binding:"required,min=1"`
+	Count int `json:"count" binding:"min=0"`
+}
+
+type TenderTotal struct {
+	Method string `json:"method"`
+	Amount int    `json:"amount"`
+}
+
+// payments exclude voided checkouts
+type ShiftTotals struct {
+	TransactionCount int
+	Payments         map[string]int
+	Change           int
+	Collections      map[string]int
+	Payouts          int
+}
+
+type ShiftOpenRequest struct {
+	UserAdminID  string `json:"-"`
+	Till         string `json:"till" binding:"required,gte=1,lte=50"`
+	OpeningFloat *int   `json:"openingFloat" binding:"required,min=0"`
+}
+
+type CashPayoutRequest struct {
+	UserAdminID string `json:"-"`
+	Amount      int    `json:"amount" binding:"required,min=1"`
+	Reason      string `json:"reason" binding:"required,lte=255"`
+}
+
+type ShiftCloseRequest struct {
+	UserAdminID   string         `json:"-"`
+	Denominations []Denomination `json:"denominations" binding:"required,min=1,dive"`
+	Notes         string         `json:"notes" binding:"omitempty,lte=255"`
+}
+
+type ShiftReport struct {
+	ID               string         `json:"id"`
+	CashierID        string         `json:"cashierId"`
+	Till             string         `json:"till"`
+	OpenedAt         time.Time      `json:"openedAt"`
+	ClosedAt         *time.Time     `json:"closedAt"`
+	OpeningFloat     int            `json:"openingFloat"`
+	TransactionCount int            `json:"transactionCount"`
+	Tenders          []TenderTotal  `json:"tenders"`
+	CashPayouts      int            `json:"cashPayouts"`
+	ExpectedCash     int            `json:"expectedCash"`
+	CountedCash      *int           `json:"countedCash"`
+	OverShort        *int           `json:"overShort"`
+	Denominations    []Denomination `json:"denominations"`
+	Notes            string         `json:"notes,omitempty"`
+}
+
+func (sr *ShiftOpenRequest) NewShift() Shift {
+	id := uuid.New()
+	rawOpenedAt := time.Now().Format(time.RFC3339)
+	openedAt, _ := time.Parse(time.RFC3339, rawOpenedAt)
+
+	return Shift{
+		ID:           id.String(),
+		UserAdminID:  sr.UserAdminID,
+		Till:         sr.Till,
+		OpeningFloat: *sr.OpeningFloat,
+		OpenedAt:     openedAt,
+	}
+}
+
+func (cr *CashPayoutRequest) NewCashPayout(shiftID string) CashPayout {
+	id := uuid.New()
+	rawCreatedAt := time.Now().Format(time.RFC3339)
+	createdAt, _ := time.Parse(time.RFC3339, rawCreatedAt)
+
+	return CashPayout{
+		ID:          id.String(),
+		CreatedAt:   createdAt,
+		ShiftID:     shiftID,
+		UserAdminID: cr.UserAdminID,
+		Amount:      cr.Amount,
+		Reason:      cr.Reason,
+	}
+}
+
+func (st *ShiftTotals) Tenders() []TenderTotal {
+	amounts := map[string]int{}
+	for method, amount := range st.Payments {
+		amounts[method] += amount
+	}
+	for method, amount := range st.Collections {
+		amounts[method] += amount
+	}
+
+	return NewTenderTotals(amounts, st.Change)
+}
+
+func NewTenderTotals(payments map[string]int, change int) []TenderTotal {
+	amounts := map[string]int{}
+	for method, amount := range payments {
+		amounts[method] = amount
+	}
+	// cash is net of the change given back
+	amounts[PaymentMethodCash] -= change
+
+	tenders := []TenderTotal{}
+	for _, method := range PaymentMethod {
+		if amount, ok := amounts[method]; ok {
+			tenders = append(tenders, TenderTotal{
+				Method: method,
+				Amount: amount,
+			})
+		}
+	}
+
+	return tenders
+}
+
+func (s *Shift) Close(body ShiftCloseRequest, totals ShiftTotals) {
+	rawClosedAt := time.Now().Format(time.RFC3339)
+	closedAt, _ := time.Parse(time.RFC3339, rawClosedAt)
+
+	counted := 0
+	for _, d := range body.Denominations {
+		counted += d.Value * d.Count
+	}
+	expected := s.expectedCash(totals)
+
+	s.ClosedAt = &closedAt
+	s.ExpectedCash = &expected
+	s.CountedCash = &counted
+	s.Denominations = body.Denominations
+	// kept so the close can't change afterwards
+	s.Tenders = totals.Tenders()
+	if len(body.Notes) > 0 {
+		s.Notes = &body.Notes
+	}
+}
+
+func (s *Shift) expectedCash(totals ShiftTotals) int {
+	cash := 0
+	for _, t := range totals.Tenders() {
+		if t.Method == PaymentMethodCash {
+			cash = t.Amount
+		}
+	}
+
+	return s.OpeningFloat + cash - totals.Payouts
+}
+
+func (s *Shift) NewShiftReport(totals ShiftTotals) ShiftReport {
+	report := ShiftReport{
+		ID:               s.ID,
+		CashierID:        s.UserAdminID,
+		Till:             s.Till,
+		OpenedAt:         s.OpenedAt,
+		ClosedAt:         s.ClosedAt,
+		OpeningFloat:     s.OpeningFloat,
+		TransactionCount: totals.TransactionCount,
+		Tenders:          totals.Tenders(),
+		CashPayouts:      totals.Payouts,
+		ExpectedCash:     s.expectedCash(totals),
+		Denominations:    []Denomination{},
+	}
+	if s.ClosedAt != nil {
+		overShort := *s.CountedCash - *s.ExpectedCash
+		report.Tenders = s.Tenders
+		report.ExpectedCash = *s.ExpectedCash
+		report.CountedCash = s.CountedCash
+		report.OverShort = &overShort
+		report.Denominations = s.Denominations
+	}
+	if s.Notes != nil {
+		report.Notes = *s.Notes
+	}
+
+	return report
+}
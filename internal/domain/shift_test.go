@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNewTenderTotals(t *testing.T) {
+	tests := []struct {
+		name     string
+		payments map[string]int
+		change   int
+		want     []TenderTotal
+	}{
+		{name: "nothing taken", payments: map[string]int{}, want: []TenderTotal{{Method: PaymentMethodCash}}},
+		{
+			name:     "cash net of change",
+			payments: map[string]int{PaymentMethodCash: 20000},
+			change:   3500,
+			want:     []TenderTotal{{Method: PaymentMethodCash, Amount: 16500}},
+		},
+		{
+			name:     "tenders in payment method order",
+			payments: map[string]int{PaymentMethodGiftCard: 500, PaymentMethodQris: 3000, PaymentMethodCash: 1000},
+			want: []TenderTotal{
+				{Method: PaymentMethodCash, Amount: 1000},
+				{Method: PaymentMethodQris, Amount: 3000},
+				{Method: PaymentMethodGiftCard, Amount: 500},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewTenderTotals(tt.payments, tt.change); !slices.Equal(got, tt.want) {
+				t.Errorf("NewTenderTotals() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShiftClose(t *testing.T) {
+	tests := []struct {
+		name          string
+		openingFloat  int
+		totals        ShiftTotals
+		denominations []Denomination
+		wantExpected  int
+		wantCounted   int
+		wantOverShort int
+	}{
+		{
+			name:          "nothing sold",
+			openingFloat:  100000,
+			denominations: []Denomination{{Value: 50000, Count: 2}},
+			wantExpected:  100000,
+			wantCounted:   100000,
+		},
+		{
+			name:         "cash sales, change and payouts",
+			openingFloat: 100000,
+			totals: ShiftTotals{
+				Payments: map[string]int{PaymentMethodCash: 60000, PaymentMethodQris: 25000},
+				Change:   5000,
+				Payouts:  15000,
+			},
+			denominations: []Denomination{{Value: 100000, Count: 1}, {Value: 20000, Count: 2}},
+			wantExpected:  140000,
+			wantCounted:   140000,
+		},
+		{
+			name:         "cash credit collections go in the drawer",
+			openingFloat: 50000,
+			totals: ShiftTotals{
+				Payments:    map[string]int{PaymentMethodCash: 10000},
+				Collections: map[string]int{PaymentMethodCash: 20000, PaymentMethodDebitCard: 5000},
+			},
+			denominations: []Denomination{{Value: 50000, Count: 1}, {Value: 10000, Count: 2}},
+			wantExpected:  80000,
+			wantCounted:   70000,
+			wantOverShort: -10000,
+		},
+		{
+			name:          "drawer over",
+			openingFloat:  0,
+			totals:        ShiftTotals{Payments: map[string]int{PaymentMethodCash: 10000}},
+			denominations: []Denomination{{Value: 10000, Count: 1}, {Value: 500, Count: 3}},
+			wantExpected:  10000,
+			wantCounted:   11500,
+			wantOverShort: 1500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shift := Shift{OpeningFloat: tt.openingFloat}
+			shift.Close(ShiftCloseRequest{Denominations: tt.denominations}, tt.totals)
+
+			if shift.ClosedAt == nil {
+				t.Fatal("shift isn't closed")
+			}
+			if *shift.ExpectedCash != tt.wantExpected || *shift.CountedCash != tt.wantCounted {
+				t.Errorf("expected %d, counted %d, want %d, %d", *shift.ExpectedCash, *shift.CountedCash, tt.wantExpected, tt.wantCounted)
+			}
+			report := shift.NewShiftReport(tt.totals)
+			if report.OverShort == nil || *report.OverShort != tt.wantOverShort {
+				t.Errorf("overShort = %v, want %d", report.OverShort, tt.wantOverShort)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type StaffInvitation struct {
+	ID        string     `db:"id"`
+	Sid       int        `db:"sid"`
+	CreatedAt time.Time  `db:"created_at"`
+	CreatedBy string     `db:"created_by"`
+	TokenHash string     `db:"token_hash"`
+	Role      string     `db:"role"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	UsedBy    *string    `db:"used_by"`
+}
+
+type StaffInvitationRequest struct {
+	UserAdminID   string `json:"-"`
+	UserAdminRole string `json:"-"`
+	Role          string `json:"role" binding:"required,oneof=owner manager cashier stock_clerk"`
+}
+
+type StaffInvitationResponse struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	CreatedBy string    `json:"createdBy"`
+	Role      string    `json:"role"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	// only returned when the invitation is created
+	Token string `json:"token,omitempty"`
+}
+
+// only the hash of a token is stored
+func NewToken() (string, error) {
+	token := make([]byte, 32)
+	_, err := rand.Read(token)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(token), nil
+}
+
+func HashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(hash[:])
+}
+
+func (sr *StaffInvitationRequest) NewStaffInvitation(ttl time.Duration) (StaffInvitation, string, error) {
+	id := uuid.New()
+	rawCreatedAt := time.Now().Format(time.RFC3339)
+	createdAt, _ := time.Parse(time.RFC3339, rawCreatedAt)
+	token, err := NewToken()
+	if err != nil {
+		return StaffInvitation{}, "", err
+	}
+
+	return StaffInvitation{
+		ID:        id.String(),
+		CreatedAt: createdAt,
+		CreatedBy: sr.UserAdminID,
+		TokenHash: HashToken(token),
+		Role:      sr.Role,
+		ExpiresAt: createdAt.Add(ttl),
+	}, token, nil
+}
+
+func (si *StaffInvitation) Usable(now time.Time) bool {
+	return si.RevokedAt == nil && si.UsedAt == nil && now.Before(si.ExpiresAt)
+}
+
+func (si *StaffInvitation) NewStaffInvitationResponse() StaffInvitationResponse {
+	return StaffInvitationResponse{
+		ID:        si.ID,
+		CreatedAt: si.CreatedAt,
+		CreatedBy: si.CreatedBy,
+		Role:      si.Role,
+		ExpiresAt: si.ExpiresAt,
+	}
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewStaffInvitation(t *testing.T) {
+	request := StaffInvitationRequest{UserAdminID: "owner", Role: UserAdminRoleCashier}
+	invitation, token, err := request.NewStaffInvitation(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(token) != 64 {
+		t.Errorf("token has %d characters, want 64", len(token))
+	}
+	if invitation.TokenHash != HashToken(token) || invitation.TokenHash == token {
+		t.Errorf("invitation doesn't store the token hash")
+	}
+	if invitation.ExpiresAt.Sub(invitation.CreatedAt) != time.Hour {
+		t.Errorf("invitation expires after %s, want 1h", invitation.ExpiresAt.Sub(invitation.CreatedAt))
+	}
+
+	_, other, err := request.NewStaffInvitation(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == token {
+		t.Errorf("two invitations got the same token")
+	}
+}
+
+func TestStaffInvitationUsable(t *testing.T) {
+	now := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	usedBy := "cashier"
+
+	tests := []struct {
+		name       string
+		invitation StaffInvitation
+		want       bool
+	}{
+		{name: "pending", invitation: StaffInvitation{ExpiresAt: now.Add(time.Hour)}, want: true},
+		{name: "expired", invitation: StaffInvitation{ExpiresAt: now}},
+		{name: "revoked", invitation: StaffInvitation{ExpiresAt: now.Add(time.Hour), RevokedAt: &now}},
+		{name: "used", invitation: StaffInvitation{ExpiresAt: now.Add(time.Hour), UsedAt: &now, UsedBy: &usedBy}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.invitation.Usable(now); got != tt.want {
+				t.Errorf("Usable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type StaffSession struct {
+	ID          string     `db:"id"`
+	Sid         int        `db:"sid"`
+	CreatedAt   time.Time  `db:"created_at"`
+	UserAdminID string     `db:"user_admin_id"`
+	RevokedAt   *time.Time `db:"revoked_at"`
+}
+
+// a used token coming back means it was stolen
+type StaffRefreshToken struct {
+	TokenHash string     `db:"token_hash"`
+	SessionID string     `db:"session_id"`
+	CreatedAt time.Time  `db:"created_at"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	UsedAt    *time.Time `db:"used_at"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required,hexadecimal"`
+}
+
+type LogoutRequest struct {
+	UserAdminID string `json:"-"`
+	SessionID   string `json:"-"`
+	// ends every session of the staff member, not just this one
+	AllSessions bool `json:"allSessions"`
+}
+
+func NewStaffSession(userAdminID string) StaffSession {
+	id := uuid.New()
+	rawCreatedAt := time.Now().Format(time.RFC3339)
+	createdAt, _ := time.Parse(time.RFC3339, rawCreatedAt)
+
+	return StaffSession{
+		ID:          id.String(),
+		CreatedAt:   createdAt,
+		UserAdminID: userAdminID,
+	}
+}
+
+func (ss *StaffSession) NewRefreshToken(ttl time.Duration) (StaffRefreshToken, string, error) {
+	rawCreatedAt := time.Now().Format(time.RFC3339)
+	createdAt, _ := time.Parse(time.RFC3339, rawCreatedAt)
+	token, err := NewToken()
+	if err != nil {
+		return StaffRefreshToken{}, "", err
+	}
+
+	return StaffRefreshToken{
+		TokenHash: HashToken(token),
+		SessionID: ss.ID,
+		CreatedAt: createdAt,
+		ExpiresAt: createdAt.Add(ttl),
+	}, token, nil
+}
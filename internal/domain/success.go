@@ -3,6 +3,13 @@ package domain
 type SuccessData struct {
 	Message string `json:"message"`
 	Data    any    `json:"data,omitempty"`
+	Meta    any    `json:"meta,omitempty"`
+}
+
+type PaginationMeta struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
 }
 
 func NewMessageSuccess(msg string, data any) SuccessData {
@@ -11,3 +18,11 @@ func NewMessageSuccess(msg string, data any) SuccessData {
 		Data:    data,
 	}
 }
+
+func NewMessageSuccessWithMeta(msg string, data any, meta any) SuccessData {
+	return SuccessData{
+		Message: msg,
+		Data:    data,
+		Meta:    meta,
+	}
+}
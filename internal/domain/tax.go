@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TaxRate struct {
+	ID        string    `db:"id"`
+	Sid       int       `db:"sid"`
+	CreatedAt time.Time `db:"created_at"`
+	Name      string    `db:"name"`
+	Category  *string   `db:"category"`
+	ProductID *string   `db:"product_id"`
+	Rate      float64   `db:"rate"`
+	Inclusive bool      `db:"inclusive"`
+}
+
+type TaxRateRequest struct {
+	Name      string   `json:"name" binding:"required,gte=1,lte=50"`
+	Category  string   `json:"category" binding:"omitempty,oneof=Clothing Accessories Footwear Beverages"`
+	ProductID string   `json:"productId" binding:"omitempty,uuid"`
+	Rate      *float64 `json:"rate" binding:"required,min=0,max=100"`
+	Inclusive *bool    `json:"inclusive" binding:"required"`
+}
+
+type TaxRateResponse struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	Name      string    `json:"name"`
+	Category  string    `json:"category,omitempty"`
+	ProductID string    `json:"productId,omitempty"`
+	Rate      float64   `json:"rate"`
+	Inclusive bool      `json:"inclusive"`
+}
+
+func (tr *TaxRateRequest) NewTaxRate() TaxRate {
+	id := uuid.New()
+	rawCreatedAt := time.Now().Format(time.RFC3339)
+	createdAt, _ := time.Parse(time.RFC3339, rawCreatedAt)
+
+	taxRate := TaxRate{
+		ID:        id.String(),
+		CreatedAt: createdAt,
+		Name:      tr.Name,
+		Rate:      *tr.Rate,
+		Inclusive: *tr.Inclusive,
+	}
+	if len(tr.Category) > 0 {
+		taxRate.Category = &tr.Category
+	}
+	if len(tr.ProductID) > 0 {
+		taxRate.ProductID = &tr.ProductID
+	}
+
+	return taxRate
+}
+
+func (t *TaxRate) NewTaxRateResponse() TaxRateResponse {
+	response := TaxRateResponse{
+		ID:        t.ID,
+		CreatedAt: t.CreatedAt,
+		Name:      t.Name,
+		Rate:      t.Rate,
+		Inclusive: t.Inclusive,
+	}
+	if t.Category != nil {
+		response.Category = *t.Category
+	}
+	if t.ProductID != nil {
+		response.ProductID = *t.ProductID
+	}
+
+	return response
+}
+
+// an inclusive rate is already part of amount, an exclusive one comes on top
+func (t *TaxRate) Apply(amount int) (int, int) {
+	if t.Inclusive {
+		tax := int(math.Round(float64(amount) * t.Rate / (100 + t.Rate)))
+		return tax, amount
+	}
+
+	tax := int(math.Round(float64(amount) * t.Rate / 100))
+	return tax, amount + tax
+}
+
+func Prorate(amount int, part int, whole int) int {
+	if whole == 0 {
+		return 0
+	}
+
+	return int(math.Round(float64(amount) * float64(part) / float64(whole)))
+}
@@ -0,0 +1,97 @@
+package domain
+
+import "testing"
+
+func TestTaxRateApply(t *testing.T) {
+	tests := []struct {
+		name      string
+		rate      float64
+		inclusive bool
+		amount    int
+		wantTax   int
+		wantTotal int
+	}{
+		{name: "exclusive", rate: 11, amount: 10000, wantTax: 1100, wantTotal: 11100},
+		{name: "exclusive rounds half up", rate: 11, amount: 150, wantTax: 17, wantTotal: 167},
+		{name: "exclusive rounds down", rate: 11, amount: 140, wantTax: 15, wantTotal: 155},
+		{name: "inclusive", rate: 11, inclusive: true, amount: 11100, wantTax: 1100, wantTotal: 11100},
+		{name: "inclusive rounds", rate: 10, inclusive: true, amount: 1000, wantTax: 91, wantTotal: 1000},
+		{name: "zero rate", rate: 0, amount: 5000, wantTax: 0, wantTotal: 5000},
+		{name: "nothing to tax", rate: 11, amount: 0, wantTax: 0, wantTotal: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taxRate := TaxRate{Rate: tt.rate, Inclusive: tt.inclusive}
+			tax, total := taxRate.Apply(tt.amount)
+			if tax != tt.wantTax || total != tt.wantTotal {
+				t.Errorf("Apply(%d) = %d, %d, want %d, %d", tt.amount, tax, total, tt.wantTax, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestProductCheckoutApplyTax(t *testing.T) {
+	tests := []struct {
+		name      string
+		line      ProductCheckout
+		taxRate   *TaxRate
+		wantTax   int
+		wantTotal int
+	}{
+		{
+			name:      "untaxed",
+			line:      ProductCheckout{Price: 1000, Quantity: 3},
+			wantTotal: 3000,
+		},
+		{
+			name:      "taxed after the discount",
+			line:      ProductCheckout{Price: 1000, Quantity: 3, Discount: 500},
+			taxRate:   &TaxRate{Rate: 10},
+			wantTax:   250,
+			wantTotal: 2750,
+		},
+		{
+			name:      "inclusive rate keeps the total",
+			line:      ProductCheckout{Price: 1110, Quantity: 10},
+			taxRate:   &TaxRate{Rate: 11, Inclusive: true},
+			wantTax:   1100,
+			wantTotal: 11100,
+		},
+		{
+			name:      "earlier tax is replaced",
+			line:      ProductCheckout{Price: 1000, Quantity: 1, Tax: 999, TaxRate: 50},
+			wantTotal: 1000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.line.ApplyTax(tt.taxRate)
+			if tt.line.Tax != tt.wantTax || tt.line.Total != tt.wantTotal {
+				t.Errorf("tax %d, total %d, want %d, %d", tt.line.Tax, tt.line.Total, tt.wantTax, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestProrate(t *testing.T) {
+	tests := []struct {
+		amount, part, whole int
+		want                int
+	}{
+		{amount: 1000, part: 1, whole: 4, want: 250},
+		{amount: 200, part: 1, whole: 3, want: 67},
+		{amount: 100, part: 1, whole: 3, want: 33},
+		{amount: -200, part: 2, whole: 3, want: -133},
+		{amount: 500, part: 0, whole: 5, want: 0},
+		{amount: 500, part: 5, whole: 5, want: 500},
+		{amount: 500, part: 1, whole: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := Prorate(tt.amount, tt.part, tt.whole); got != tt.want {
+			t.Errorf("Prorate(%d, %d, %d) = %d, want %d", tt.amount, tt.part, tt.whole, got, tt.want)
+		}
+	}
+}
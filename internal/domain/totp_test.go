@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+// the SHA1 secret of the RFC 6238 test vectors, "12345678901234567890"
+const rfcTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+		{unix: 20000000000, want: "353130"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			code, err := TOTPCode(rfcTOTPSecret, TOTPStep(time.Unix(tt.unix, 0)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if code != tt.want {
+				t.Errorf("TOTPCode() at %d = %s, want %s", tt.unix, code, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	current := TOTPStep(now)
+	codeAt := func(step int64) string {
+		code, err := TOTPCode(rfcTOTPSecret, step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		lastStep int64
+		wantStep int64
+		wantOK   bool
+	}{
+		{name: "current step", secret: rfcTOTPSecret, code: codeAt(current), wantStep: current, wantOK: true},
+		{name: "step before", secret: rfcTOTPSecret, code: codeAt(current - 1), wantStep: current - 1, wantOK: true},
+		{name: "step after", secret: rfcTOTPSecret, code: codeAt(current + 1), wantStep: current + 1, wantOK: true},
+		{name: "two steps before", secret: rfcTOTPSecret, code: codeAt(current - 2)},
+		{name: "two steps after", secret: rfcTOTPSecret, code: codeAt(current + 2)},
+		{name: "replayed code", secret: rfcTOTPSecret, code: codeAt(current), lastStep: current},
+		{name: "code before the last accepted one", secret: rfcTOTPSecret, code: codeAt(current - 1), lastStep: current},
+		{name: "code after the last accepted one", secret: rfcTOTPSecret, code: codeAt(current + 1), lastStep: current, wantStep: current + 1, wantOK: true},
+		{name: "lower case secret", secret: "gezdgnbvgy3tqojqgezdgnbvgy3tqojq", code: codeAt(current), wantStep: current, wantOK: true},
+		{name: "wrong code", secret: rfcTOTPSecret, code: "000000"},
+		{name: "invalid secret", secret: "not base32!", code: codeAt(current)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := VerifyTOTP(tt.secret, tt.code, now, tt.lastStep)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("VerifyTOTP() = %d, %v, want %d, %v", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestNewTOTPSecret(t *testing.T) {
+	secret, err := NewTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secret) != 32 {
+		t.Errorf("secret %q has %d characters, want 32", secret, len(secret))
+	}
+	if _, err := TOTPCode(secret, 1); err != nil {
+		t.Errorf("TOTPCode() with a new secret: %s", err)
+	}
+}
+
+func TestNewRecoveryCodes(t *testing.T) {
+	codes, hashes, err := NewRecoveryCodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != RecoveryCodeCount || len(hashes) != RecoveryCodeCount {
+		t.Fatalf("got %d codes and %d hashes, want %d", len(codes), len(hashes), RecoveryCodeCount)
+	}
+
+	format := regexp.MustCompile(`^[A-Z2-7]{5}-[A-Z2-7]{5}$`)
+	seen := map[string]bool{}
+	for i, code := range codes {
+		if !format.MatchString(code) {
+			t.Errorf("code %q doesn't look like XXXXX-XXXXX", code)
+		}
+		if hashes[i] != HashRecoveryCode(code) {
+			t.Errorf("hash of %q doesn't match", code)
+		}
+		if seen[hashes[i]] {
+			t.Errorf("code %q is repeated", code)
+		}
+		seen[hashes[i]] = true
+	}
+}
+
+func TestHashRecoveryCode(t *testing.T) {
+	want := HashRecoveryCode("ABCDE-FGHIJ")
+
+	tests := []struct {
+		code  string
+		match bool
+	}{
+		{code: "ABCDE-FGHIJ", match: true},
+		{code: "abcde-fghij", match: true},
+		{code: "ABCDEFGHIJ", match: true},
+		{code: " abcde fghij ", match: true},
+		{code: "ABCDE-FGHIK", match: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			if got := HashRecoveryCode(tt.code) == want; got != tt.match {
+				t.Errorf("HashRecoveryCode(%q) matches = %v, want %v", tt.code, got, tt.match)
+			}
+		})
+	}
+}
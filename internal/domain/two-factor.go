@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"crypto/rand"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	LoginChallengeVerify = "verify"
+	// the role needs two-factor authentication but it isn't set up yet
+	LoginChallengeEnrol = "enrol"
+)
+
+const (
+	LoginChallengeTTL = 5 * time.Minute
+	// wrong codes before the password has to be entered again
+	LoginChallengeMaxFailures = 5
+	RecoveryCodeCount         = 10
+)
+
+type StaffTwoFactor struct {
+	UserAdminID string  `db:"user_admin_id"`
+	Secret      *string `db:"secret"`
+	// set by an enrolment until it is confirmed with a code
+	PendingSecret *string    `db:"pending_secret"`
+	EnabledAt     *time.Time `db:"enabled_at"`
+	// the step of the last accepted code, codes of it or before are refused
+	LastStep int64 `db:"last_step"`
+}
+
+type LoginChallenge struct {
+	TokenHash      string     `db:"token_hash"`
+	UserAdminID    string     `db:"user_admin_id"`
+	Type           string     `db:"type"`
+	CreatedAt      time.Time  `db:"created_at"`
+	ExpiresAt      time.Time  `db:"expires_at"`
+	FailedAttempts int        `db:"failed_attempts"`
+	UsedAt         *time.Time `db:"used_at"`
+}
+
+type LoginChallengeRequest struct {
+	ChallengeToken string `json:"challengeToken" binding:"required,hexadecimal"`
+}
+
+type LoginVerifyRequest struct {
+	ChallengeToken string `json:"challengeToken" binding:"required,hexadecimal"`
+	Code           string `json:"code" binding:"omitempty,numeric,len=6"`
+	// instead of a code, when the authenticator is lost
+	RecoveryCode string `json:"recoveryCode" binding:"omitempty,lte=20"`
+	IP           string `json:"-"`
+	UserAgent    string `json:"-"`
+}
+
+type TwoFactorCodeRequest struct {
+	UserAdmin UserAdmin `json:"-"`
+	Code      string    `json:"code" binding:"required,numeric,len=6"`
+	IP        string    `json:"-"`
+	UserAgent string    `json:"-"`
+}
+
+type TwoFactorStatusResponse struct {
+	Enabled           bool       `json:"enabled"`
+	EnabledAt         *time.Time `json:"enabledAt"`
+	Required          bool       `json:"required"`
+	RecoveryCodesLeft int        `json:"recoveryCodesLeft"`
+}
+
+// answers a right password when a second factor is still needed, nothing
+// about the staff member is shown before it
+type LoginChallengeResponse struct {
+	TwoFactor      string `json:"twoFactor"`
+	ChallengeToken string `json:"challengeToken"`
+}
+
+type TwoFactorEnrolmentResponse struct {
+	Secret string `json:"secret"`
+	// otpauth URI to show as a QR code
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// a comma separated list of roles, a misspelled one would silently turn it off
+func ParseTwoFactorRoles(raw string) ([]string, error) {
+	var roles []string
+	for _, role := range strings.Split(raw, ",") {
+		role = strings.TrimSpace(role)
+		if len(role) == 0 {
+			continue
+		}
+		if _, ok := RolePermissions[role]; !ok {
+			return nil, fmt.Errorf("unknown role %q", role)
+		}
+		roles = append(roles, role)
+	}
+
+	return roles, nil
+}
+
+func TwoFactorRequired(role string, requiredRoles []string) bool {
+	return slices.Contains(requiredRoles, role)
+}
+
+func (tf *StaffTwoFactor) Enabled() bool {
+	return tf != nil && tf.EnabledAt != nil && tf.Secret != nil
+}
+
+func NewLoginChallenge(userAdminID string, challengeType string) (LoginChallenge, string, error) {
+	rawCreatedAt := time.Now().Format(time.RFC3339)
+	createdAt, _ := time.Parse(time.RFC3339, rawCreatedAt)
+	token, err := NewToken()
+	if err != nil {
+		return LoginChallenge{}, "", err
+	}
+
+	return LoginChallenge{
+		TokenHash:   HashToken(token),
+		UserAdminID: userAdminID,
+		Type:        challengeType,
+		CreatedAt:   createdAt,
+		ExpiresAt:   createdAt.Add(LoginChallengeTTL),
+	}, token, nil
+}
+
+func (lc *LoginChallenge) Usable(now time.Time) bool {
+	return lc.UsedAt == nil && lc.FailedAttempts < LoginChallengeMaxFailures && now.Before(lc.ExpiresAt)
+}
+
+func NewRecoveryCodes() ([]string, []string, error) {
+	codes := []string{}
+	hashes := []string{}
+	for range RecoveryCodeCount {
+		raw := make([]byte, 10)
+		_, err := rand.Read(raw)
+		if err != nil {
+			return nil, nil, err
+		}
+		code := totpEncoding.EncodeToString(raw)[:10]
+
+		codes = append(codes, code[:5]+"-"+code[5:])
+		hashes = append(hashes, HashRecoveryCode(code))
+	}
+
+	return codes, hashes, nil
+}
+
+// codes are typed in by hand, so case, dashes and spaces don't matter
+func HashRecoveryCode(code string) string {
+	code = strings.ToUpper(code)
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+
+	return HashToken(code)
+}
+
+func (tr *TwoFactorCodeRequest) LoginUserAdmin() LoginUserAdmin {
+	return LoginUserAdmin{
+		PhoneNumber: tr.UserAdmin.PhoneNumber,
+		IP:          tr.IP,
+		UserAgent:   tr.UserAgent,
+	}
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestStaffTwoFactorEnabled(t *testing.T) {
+	secret := rfcTOTPSecret
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		twoFactor *StaffTwoFactor
+		want      bool
+	}{
+		{name: "never set up", twoFactor: nil, want: false},
+		{name: "enrolment pending", twoFactor: &StaffTwoFactor{PendingSecret: &secret}, want: false},
+		{name: "enabled", twoFactor: &StaffTwoFactor{Secret: &secret, EnabledAt: &now}, want: true},
+		{name: "turned off", twoFactor: &StaffTwoFactor{EnabledAt: &now}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.twoFactor.Enabled(); got != tt.want {
+				t.Errorf("Enabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTwoFactorRoles(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    []string
+		wantErr bool
+	}{
+		{name: "roles", raw: "owner,manager", want: []string{UserAdminRoleOwner, UserAdminRoleManager}},
+		{name: "spaces around roles", raw: " owner , stock_clerk ", want: []string{UserAdminRoleOwner, UserAdminRoleStockClerk}},
+		{name: "empty", raw: "", want: nil},
+		{name: "only spaces and commas", raw: " , ", want: nil},
+		{name: "unknown role", raw: "owner,managr", wantErr: true},
+		{name: "wrong case", raw: "Owner", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTwoFactorRoles(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTwoFactorRoles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ParseTwoFactorRoles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTwoFactorRequired(t *testing.T) {
+	tests := []struct {
+		name          string
+		role          string
+		requiredRoles []string
+		want          bool
+	}{
+		{name: "role required", role: UserAdminRoleOwner, requiredRoles: []string{UserAdminRoleOwner, UserAdminRoleManager}, want: true},
+		{name: "role not required", role: UserAdminRoleCashier, requiredRoles: []string{UserAdminRoleOwner}, want: false},
+		{name: "requirement turned off", role: UserAdminRoleOwner, requiredRoles: []string{}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TwoFactorRequired(tt.role, tt.requiredRoles); got != tt.want {
+				t.Errorf("TwoFactorRequired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
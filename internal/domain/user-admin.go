@@ -10,7 +10,12 @@ import (
 // Vars
 ////
 
-var UserAdminRoleStaff = "staff"
+var (
+	UserAdminRoleOwner      = "owner"
+	UserAdminRoleManager    = "manager"
+	UserAdminRoleCashier    = "cashier"
+	UserAdminRoleStockClerk = "stock_clerk"
+)
 
 ////
 // Structs
@@ -24,11 +29,19 @@ type RegisterUserAdminRequest struct {
 	Name        string `json:"name" binding:"required,gte=5,lte=50"`
 	PhoneNumber string `json:"phoneNumber" binding:"required,gte=10,lte=17,phonenumber"`
 	Password    string `json:"password" binding:"required,gte=5,lte=15"`
+	// not needed by the first staff member
+	InvitationToken string `json:"invitationToken" binding:"omitempty,hexadecimal"`
 }
 
 type LoginUserAdmin struct {
 	PhoneNumber string `json:"phoneNumber" binding:"required,gte=10,lte=17,phonenumber"`
 	Password    string `json:"password" binding:"required,gte=5,lte=15"`
+	IP          string `json:"-"`
+	UserAgent   string `json:"-"`
+}
+
+type UserAdminRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=owner manager cashier stock_clerk"`
 }
 
 type UserAdmin struct {
@@ -39,6 +52,8 @@ type UserAdmin struct {
 	PhoneNumber string    `db:"phone_number"`
 	Role        string    `db:"role"`
 	Password    string    `db:"password"`
+	// the session of the access token the request came with
+	SessionID string `db:"-"`
 }
 
 ////
@@ -46,10 +61,22 @@ type UserAdmin struct {
 ////
 
 type UserAdminResponseWithAccessToken struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	PhoneNumber string `json:"phoneNumber"`
-	AccessToken string `json:"accessToken"`
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	PhoneNumber  string `json:"phoneNumber"`
+	Role         string `json:"role"`
+	AccessToken  string `json:"accessToken,omitempty"`
+	RefreshToken string `json:"refreshToken,omitempty"`
+	// only returned when two-factor authentication is set up during login
+	RecoveryCodes []string `json:"recoveryCodes,omitempty"`
+}
+
+type UserAdminResponse struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	PhoneNumber string    `json:"phoneNumber"`
+	Role        string    `json:"role"`
+	CreatedAt   time.Time `json:"createdAt"`
 }
 
 func (rua *RegisterUserAdminRequest) NewUserAdminFromDTO() UserAdmin {
@@ -63,6 +90,15 @@ func (rua *RegisterUserAdminRequest) NewUserAdminFromDTO() UserAdmin {
 		Name:        rua.Name,
 		PhoneNumber: rua.PhoneNumber,
 		Password:    rua.Password,
-		Role:        UserAdminRoleStaff,
+	}
+}
+
+func (ua *UserAdmin) NewUserAdminResponse() UserAdminResponse {
+	return UserAdminResponse{
+		ID:          ua.ID,
+		Name:        ua.Name,
+		PhoneNumber: ua.PhoneNumber,
+		Role:        ua.Role,
+		CreatedAt:   ua.CreatedAt,
 	}
 }
@@ -0,0 +1,7 @@
+package domain
+
+type VoidRequest struct {
+	UserAdminID   string `json:"-"`
+	UserAdminRole string `json:"-"`
+	Reason        string `json:"reason" binding:"required,gte=1,lte=200"`
+}
@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"eniqilo-store/internal/domain"
+	"eniqilo-store/internal/helper"
+	"eniqilo-store/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CartHandler interface {
+	CreateCart() gin.HandlerFunc
+	GetCarts() gin.HandlerFunc
+	GetCartByID() gin.HandlerFunc
+	SetCartItem() gin.HandlerFunc
+	RemoveCartItem() gin.HandlerFunc
+	SetCartCustomer() gin.HandlerFunc
+	ParkCart() gin.HandlerFunc
+	ResumeCart() gin.HandlerFunc
+	CheckoutCart() gin.HandlerFunc
+	DeleteCartByID() gin.HandlerFunc
+}
+
+type cartHandler struct {
+	cartService service.CartService
+}
+
+func NewCartHandler(cartService service.CartService) CartHandler {
+	return &cartHandler{
+		cartService: cartService,
+	}
+}
+
+func (ch *cartHandler) CreateCart() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var body domain.CartRequest
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			errMsg := helper.ValidateRequest(err)
+			ctx.JSON(errMsg.Status(), errMsg)
+			return
+		}
+
+		userData := ctx.MustGet("userData").(domain.UserAdmin)
+		body.UserAdminID = userData.ID
+
+		cart, errMsg := ch.cartService.CreateCart(ctx, body)
+		if errMsg != nil {
+			ctx.JSON(errMsg.Status(), errMsg)
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, domain.NewMessageSuccess("success create cart", cart))
+	}
+}
+
+func (ch *cartHandler) GetCarts() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var queryParams domain.CartQueryParams
+		ctx.ShouldBindQuery(&queryParams)
+
+		carts, err := ch.cartService.GetCarts(ctx, queryParams)
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success get carts", carts))
+	}
+}
+
+func (ch *cartHandler) GetCartByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		cart, err := ch.cartService.GetCartByID(ctx, ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success get cart", cart))
+	}
+}
+
+func (ch *cartHandler) SetCartItem() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var body domain.CartItemRequest
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			errMsg := helper.ValidateRequest(err)
+			ctx.JSON(errMsg.Status(), errMsg)
+			return
+		}
+
+		cart, errMsg := ch.cartService.SetCartItem(ctx, ctx.Param("id"), body)
+		if errMsg != nil {
+			ctx.JSON(errMsg.Status(), errMsg)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success update cart item", cart))
+	}
+}
+
+func (ch *cartHandler) RemoveCartItem() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		cart, err := ch.cartService.RemoveCartItem(ctx, ctx.Param("id"), ctx.Param("productId"))
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success remove cart item", cart))
+	}
+}
+
+func (ch *cartHandler) SetCartCustomer() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var body domain.CartCustomerRequest
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			errMsg := helper.ValidateRequest(err)
+			ctx.JSON(errMsg.Status(), errMsg)
+			return
+		}
+
+		cart, errMsg := ch.cartService.SetCartCustomer(ctx, ctx.Param("id"), body)
+		if errMsg != nil {
+			ctx.JSON(errMsg.Status(), errMsg)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success attach cart customer", cart))
+	}
+}
+
+func (ch *cartHandler) ParkCart() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var body domain.CartParkRequest
+		if ctx.Request.ContentLength > 0 {
+			if err := ctx.ShouldBindJSON(&body); err != nil {
+				errMsg := helper.ValidateRequest(err)
+				ctx.JSON(errMsg.Status(), errMsg)
+				return
+			}
+		}
+
+		cart, errMsg := ch.cartService.ParkCart(ctx, ctx.Param("id"), body)
+		if errMsg != nil {
+			ctx.JSON(errMsg.Status(), errMsg)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success park cart", cart))
+	}
+}
+
+func (ch *cartHandler) ResumeCart() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var body domain.CartResumeRequest
+		if ctx.Request.ContentLength > 0 {
+			if err := ctx.ShouldBindJSON(&body); err != nil {
+				errMsg := helper.ValidateRequest(err)
+				ctx.JSON(errMsg.Status(), errMsg)
+				return
+			}
+		}
+
+		cart, errMsg := ch.cartService.ResumeCart(ctx, ctx.Param("id"), body)
+		if errMsg != nil {
+			ctx.JSON(errMsg.Status(), errMsg)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success resume cart", cart))
+	}
+}
+
+func (ch *cartHandler) CheckoutCart() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var body domain.CartCheckoutRequest
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			errMsg := helper.ValidateRequest(err)
+			ctx.JSON(errMsg.Status(), errMsg)
+			return
+		}
+
+		userData := ctx.MustGet("userData").(domain.UserAdmin)
+		body.UserAdminID = userData.ID
+
+		response, errMsg := ch.cartService.CheckoutCart(ctx.Request.Context(), ctx.Param("id"), body)
+		if errMsg != nil {
+			ctx.JSON(errMsg.Status(), errMsg)
+			return
+		}
+
+		if response.Replayed {
+			ctx.Header("Idempotent-Replayed", "true")
+		}
+		ctx.Data(response.Status, "application/json; charset=utf-8", response.Body)
+	}
+}
+
+func (ch *cartHandler) DeleteCartByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		err := ch.cartService.DeleteCartByID(ctx, ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success delete cart", nil))
+	}
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"eniqilo-store/internal/domain"
+	"eniqilo-store/internal/helper"
+	"eniqilo-store/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CouponHandler interface {
+	CreateCoupon() gin.HandlerFunc
+	GetCoupons() gin.HandlerFunc
+	UpdateCouponByID() gin.HandlerFunc
+	DeleteCouponByID() gin.HandlerFunc
+	GetCouponReport() gin.HandlerFunc
+}
+
+type couponHandler struct {
+	couponService service.CouponService
+}
+
+func NewCouponHandler(couponService service.CouponService) CouponHandler {
+	return &couponHandler{
+		couponService: couponService,
+	}
+}
+
+func (ch *couponHandler) CreateCoupon() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		couponBody := domain.CouponRequest{}
+		if err := ctx.ShouldBindJSON(&couponBody); err != nil {
+			err := helper.ValidateRequest(err)
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		coupon := couponBody.NewCoupon()
+		err := ch.couponService.CreateCoupon(ctx, coupon)
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, domain.NewMessageSuccess("success create coupon", coupon.NewCouponResponse()))
+	}
+}
+
+func (ch *couponHandler) GetCoupons() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		coupons, err := ch.couponService.GetCoupons(ctx)
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success get coupons", coupons))
+	}
+}
+
+func (ch *couponHandler) UpdateCouponByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		couponBody := domain.CouponRequest{}
+		if err := ctx.ShouldBindJSON(&couponBody); err != nil {
+			err := helper.ValidateRequest(err)
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		coupon := couponBody.NewCoupon()
+		coupon.ID = ctx.Param("id")
+		err := ch.couponService.UpdateCouponByID(ctx, coupon)
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success update coupon", coupon.NewCouponResponse()))
+	}
+}
+
+func (ch *couponHandler) DeleteCouponByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		err := ch.couponService.DeleteCouponByID(ctx, ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success delete coupon", nil))
+	}
+}
+
+func (ch *couponHandler) GetCouponReport() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var queryParams domain.CouponReportQueryParams
+		ctx.ShouldBindQuery(&queryParams)
+
+		reports, err := ch.couponService.GetCouponReport(ctx, queryParams)
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success get coupon report", reports))
+	}
+}
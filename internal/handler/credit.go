@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"eniqilo-store/internal/domain"
+	"eniqilo-store/internal/helper"
+	"eniqilo-store/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CreditHandler interface {
+	GetCreditAccount() gin.HandlerFunc
+	UpdateCreditLimit() gin.HandlerFunc
+	CreateCreditPayment() gin.HandlerFunc
+	GetCreditStatement() gin.HandlerFunc
+	GetCreditAging() gin.HandlerFunc
+}
+
+type creditHandler struct {
+	creditService service.CreditService
+}
+
+func NewCreditHandler(creditService service.CreditService) CreditHandler {
+	return &creditHandler{
+		creditService: creditService,
+	}
+}
+
+func (ch *creditHandler) GetCreditAccount() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		account, err := ch.creditService.GetCreditAccount(ctx, ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success get credit account", account))
+	}
+}
+
+func (ch *creditHandler) UpdateCreditLimit() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var body domain.CreditLimitRequest
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			errMsg := helper.ValidateRequest(err)
+			ctx.JSON(errMsg.Status(), errMsg)
+			return
+		}
+
+		account, errMsg := ch.creditService.UpdateCreditLimit(ctx, ctx.Param("id"), body)
+		if errMsg != nil {
+			ctx.JSON(errMsg.Status(), errMsg)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success update credit limit", account))
+	}
+}
+
+func (ch *creditHandler) CreateCreditPayment() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var body domain.CreditPaymentRequest
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			errMsg := helper.ValidateRequest(err)
+			ctx.JSON(errMsg.Status(), errMsg)
+			return
+		}
+
+		userData := ctx.MustGet("userData").(domain.UserAdmin)
+		body.UserAdminID = userData.ID
+
+		account, errMsg := ch.creditService.CreateCreditPayment(ctx, ctx.Param("id"), body)
+		if errMsg != nil {
+			ctx.JSON(errMsg.Status(), errMsg)
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, domain.NewMessageSuccess("success create credit payment", account))
+	}
+}
+
+func (ch *creditHandler) GetCreditStatement() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var queryParams domain.CreditStatementQueryParams
+		ctx.ShouldBindQuery(&queryParams)
+
+		statement, err := ch.creditService.GetCreditStatement(ctx, ctx.Param("id"), queryParams)
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success get credit statement", statement))
+	}
+}
+
+func (ch *creditHandler) GetCreditAging() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		agings, err := ch.creditService.GetCreditAging(ctx)
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success get credit aging", agings))
+	}
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"eniqilo-store/internal/domain"
+	"eniqilo-store/internal/helper"
+	"eniqilo-store/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GiftCardHandler interface {
+	IssueGiftCard() gin.HandlerFunc
+	GetGiftCardByCode() gin.HandlerFunc
+	UpdateGiftCardStatus() gin.HandlerFunc
+}
+
+type giftCardHandler struct {
+	giftCardService service.GiftCardService
+}
+
+func NewGiftCardHandler(giftCardService service.GiftCardService) GiftCardHandler {
+	return &giftCardHandler{
+		giftCardService: giftCardService,
+	}
+}
+
+func (gh *giftCardHandler) IssueGiftCard() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var body domain.GiftCardRequest
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			errMsg := helper.ValidateRequest(err)
+			ctx.JSON(errMsg.Status(), errMsg)
+			return
+		}
+
+		userData := ctx.MustGet("userData").(domain.UserAdmin)
+		body.UserAdminID = userData.ID
+
+		giftCard, errMsg := gh.giftCardService.IssueGiftCard(ctx, body)
+		if errMsg != nil {
+			ctx.JSON(errMsg.Status(), errMsg)
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, domain.NewMessageSuccess("success issue gift card", giftCard))
+	}
+}
+
+func (gh *giftCardHandler) GetGiftCardByCode() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		giftCard, err := gh.giftCardService.GetGiftCardByCode(ctx, ctx.Param("code"))
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success get gift card", giftCard))
+	}
+}
+
+func (gh *giftCardHandler) UpdateGiftCardStatus() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var body domain.GiftCardStatusRequest
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			errMsg := helper.ValidateRequest(err)
+			ctx.JSON(errMsg.Status(), errMsg)
+			return
+		}
+
+		giftCard, errMsg := gh.giftCardService.UpdateGiftCardStatus(ctx, ctx.Param("code"), body)
+		if errMsg != nil {
+			ctx.JSON(errMsg.Status(), errMsg)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success update gift card", giftCard))
+	}
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"eniqilo-store/internal/domain"
+	"eniqilo-store/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PointsHandler interface {
+	GetPointsBalance() gin.HandlerFunc
+	GetPointsLedger() gin.HandlerFunc
+}
+
+type pointsHandler struct {
+	pointsService service.PointsService
+}
+
+func NewPointsHandler(pointsService service.PointsService) PointsHandler {
+	return &pointsHandler{
+		pointsService: pointsService,
+	}
+}
+
+func (ph *pointsHandler) GetPointsBalance() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		balance, err := ph.pointsService.GetPointsBalance(ctx, ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success get points balance", balance))
+	}
+}
+
+func (ph *pointsHandler) GetPointsLedger() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var queryParams domain.PointsLedgerQueryParams
+		ctx.ShouldBindQuery(&queryParams)
+
+		entries, meta, err := ph.pointsService.GetPointsLedger(ctx, ctx.Param("id"), queryParams)
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccessWithMeta("success get points ledger", entries, meta))
+	}
+}
@@ -11,7 +11,12 @@ import (
 
 type CheckoutHandler interface {
 	CreateCheckout() gin.HandlerFunc
+	QuoteCheckout() gin.HandlerFunc
+	CreateReturn() gin.HandlerFunc
+	VoidCheckout() gin.HandlerFunc
 	GetCheckoutHistory() gin.HandlerFunc
+	GetCheckoutByID() gin.HandlerFunc
+	GetCashierSalesSummary() gin.HandlerFunc
 }
 
 type checkoutHandler struct {
@@ -33,13 +38,95 @@ func (ch *checkoutHandler) CreateCheckout() gin.HandlerFunc {
 			return
 		}
 
-		err := ch.checkoutSerivce.CreateCheckout(ctx.Request.Context(), body)
+		userData := ctx.MustGet("userData").(domain.UserAdmin)
+		body.UserAdminID = userData.ID
+
+		idempotencyKey := ctx.GetHeader(domain.IdempotencyKeyHeader)
+		if len(idempotencyKey) > 255 {
+			err := domain.NewBadRequestError("maximum Idempotency-Key is 255 characters")
+			ctx.JSON(err.Status(), err)
+			return
+		}
+		if len(idempotencyKey) > 0 {
+			idempotencyKey = domain.ClientIdempotencyKeyPrefix + idempotencyKey
+		}
+
+		response, err := ch.checkoutSerivce.CreateCheckout(ctx.Request.Context(), body, idempotencyKey)
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		if response.Replayed {
+			ctx.Header("Idempotent-Replayed", "true")
+		}
+		ctx.Data(response.Status, "application/json; charset=utf-8", response.Body)
+	}
+}
+
+func (ch *checkoutHandler) QuoteCheckout() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var body domain.CheckoutQuoteRequest
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			err := helper.ValidateRequest(err)
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		quote, err := ch.checkoutSerivce.QuoteCheckout(ctx.Request.Context(), body)
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success quote checkout", quote))
+	}
+}
+
+func (ch *checkoutHandler) CreateReturn() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var body domain.ReturnRequest
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			err := helper.ValidateRequest(err)
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		userData := ctx.MustGet("userData").(domain.UserAdmin)
+		body.UserAdminID = userData.ID
+
+		checkoutId := ctx.Param("id")
+		response, err := ch.checkoutSerivce.CreateReturn(ctx.Request.Context(), checkoutId, body)
 		if err != nil {
 			ctx.JSON(err.Status(), err)
 			return
 		}
 
-		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success checkout", nil))
+		ctx.JSON(http.StatusCreated, domain.NewMessageSuccess("success return checkout", response))
+	}
+}
+
+func (ch *checkoutHandler) VoidCheckout() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var body domain.VoidRequest
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			err := helper.ValidateRequest(err)
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		userData := ctx.MustGet("userData").(domain.UserAdmin)
+		body.UserAdminID = userData.ID
+		body.UserAdminRole = userData.Role
+
+		checkoutId := ctx.Param("id")
+		response, err := ch.checkoutSerivce.VoidCheckout(ctx.Request.Context(), checkoutId, body)
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success void checkout", response))
 	}
 }
 
@@ -48,12 +135,41 @@ func (ch *checkoutHandler) GetCheckoutHistory() gin.HandlerFunc {
 		var queryParams domain.CheckoutHistoryQueryParams
 		ctx.ShouldBindQuery(&queryParams)
 
-		checkouts, err := ch.checkoutSerivce.GetCheckoutHistory(ctx, queryParams)
+		checkouts, meta, err := ch.checkoutSerivce.GetCheckoutHistory(ctx, queryParams)
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccessWithMeta("success get checkout history", checkouts, meta))
+	}
+}
+
+func (ch *checkoutHandler) GetCheckoutByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		checkoutId := ctx.Param("id")
+
+		checkout, err := ch.checkoutSerivce.GetCheckoutByID(ctx, checkoutId)
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success get checkout", checkout))
+	}
+}
+
+func (ch *checkoutHandler) GetCashierSalesSummary() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var queryParams domain.CheckoutSummaryQueryParams
+		ctx.ShouldBindQuery(&queryParams)
+
+		summaries, err := ch.checkoutSerivce.GetCashierSalesSummary(ctx, queryParams)
 		if err != nil {
 			ctx.JSON(err.Status(), err)
 			return
 		}
 
-		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success get checkout history", checkouts))
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success get cashier sales summary", summaries))
 	}
 }
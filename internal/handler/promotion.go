@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"eniqilo-store/internal/domain"
+	"eniqilo-store/internal/helper"
+	"eniqilo-store/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PromotionHandler interface {
+	CreatePromotion() gin.HandlerFunc
+	GetPromotions() gin.HandlerFunc
+	UpdatePromotionByID() gin.HandlerFunc
+	DeletePromotionByID() gin.HandlerFunc
+	GetPromotionReport() gin.HandlerFunc
+}
+
+type promotionHandler struct {
+	promotionService service.PromotionService
+}
+
+func NewPromotionHandler(promotionService service.PromotionService) PromotionHandler {
+	return &promotionHandler{
+		promotionService: promotionService,
+	}
+}
+
+func (ph *promotionHandler) CreatePromotion() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		promotionBody := domain.PromotionRequest{}
+		if err := ctx.ShouldBindJSON(&promotionBody); err != nil {
+			err := helper.ValidateRequest(err)
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		promotion := promotionBody.NewPromotion()
+		err := ph.promotionService.CreatePromotion(ctx, promotion)
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, domain.NewMessageSuccess("success create promotion", promotion.NewPromotionResponse()))
+	}
+}
+
+func (ph *promotionHandler) GetPromotions() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		promotions, err := ph.promotionService.GetPromotions(ctx)
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success get promotions", promotions))
+	}
+}
+
+func (ph *promotionHandler) UpdatePromotionByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		promotionBody := domain.PromotionRequest{}
+		if err := ctx.ShouldBindJSON(&promotionBody); err != nil {
+			err := helper.ValidateRequest(err)
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		promotion := promotionBody.NewPromotion()
+		promotion.ID = ctx.Param("id")
+		err := ph.promotionService.UpdatePromotionByID(ctx, promotion)
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success update promotion", promotion.NewPromotionResponse()))
+	}
+}
+
+func (ph *promotionHandler) DeletePromotionByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		err := ph.promotionService.DeletePromotionByID(ctx, ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success delete promotion", nil))
+	}
+}
+
+func (ph *promotionHandler) GetPromotionReport() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var queryParams domain.PromotionReportQueryParams
+		ctx.ShouldBindQuery(&queryParams)
+
+		reports, err := ph.promotionService.GetPromotionReport(ctx, queryParams)
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success get promotion report", reports))
+	}
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"eniqilo-store/internal/domain"
+	"eniqilo-store/internal/helper"
+	"eniqilo-store/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler interface {
+	GetZReport() gin.HandlerFunc
+	CloseZReport() gin.HandlerFunc
+}
+
+type reportHandler struct {
+	reportService service.ReportService
+}
+
+func NewReportHandler(reportService service.ReportService) ReportHandler {
+	return &reportHandler{
+		reportService: reportService,
+	}
+}
+
+func (rh *reportHandler) GetZReport() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var queryParams domain.ZReportQueryParams
+		ctx.ShouldBindQuery(&queryParams)
+
+		report, err := rh.reportService.GetZReport(ctx, queryParams)
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success get z report", report))
+	}
+}
+
+func (rh *reportHandler) CloseZReport() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var body domain.ZReportCloseRequest
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			errMsg := helper.ValidateRequest(err)
+			ctx.JSON(errMsg.Status(), errMsg)
+			return
+		}
+
+		userData := ctx.MustGet("userData").(domain.UserAdmin)
+		body.UserAdminID = userData.ID
+
+		report, errMsg := rh.reportService.CloseZReport(ctx, body)
+		if errMsg != nil {
+			ctx.JSON(errMsg.Status(), errMsg)
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, domain.NewMessageSuccess("success close day", report))
+	}
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"eniqilo-store/internal/domain"
+	"eniqilo-store/internal/helper"
+	"eniqilo-store/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ShiftHandler interface {
+	OpenShift() gin.HandlerFunc
+	GetCurrentShift() gin.HandlerFunc
+	CreateCashPayout() gin.HandlerFunc
+	CloseShift() gin.HandlerFunc
+	GetShiftByID() gin.HandlerFunc
+}
+
+type shiftHandler struct {
+	shiftService service.ShiftService
+}
+
+func NewShiftHandler(shiftService service.ShiftService) ShiftHandler {
+	return &shiftHandler{
+		shiftService: shiftService,
+	}
+}
+
+func (sh *shiftHandler) OpenShift() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var body domain.ShiftOpenRequest
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			errMsg := helper.ValidateRequest(err)
+			ctx.JSON(errMsg.Status(), errMsg)
+			return
+		}
+
+		userData := ctx.MustGet("userData").(domain.UserAdmin)
+		body.UserAdminID = userData.ID
+
+		shift, errMsg := sh.shiftService.OpenShift(ctx, body)
+		if errMsg != nil {
+			ctx.JSON(errMsg.Status(), errMsg)
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, domain.NewMessageSuccess("success open shift", shift))
+	}
+}
+
+func (sh *shiftHandler) GetCurrentShift() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userData := ctx.MustGet("userData").(domain.UserAdmin)
+
+		shift, err := sh.shiftService.GetCurrentShift(ctx, userData.ID)
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success get current shift", shift))
+	}
+}
+
+func (sh *shiftHandler) CreateCashPayout() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var body domain.CashPayoutRequest
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			errMsg := helper.ValidateRequest(err)
+			ctx.JSON(errMsg.Status(), errMsg)
+			return
+		}
+
+		userData := ctx.MustGet("userData").(domain.UserAdmin)
+		body.UserAdminID = userData.ID
+
+		shift, errMsg := sh.shiftService.CreateCashPayout(ctx, body)
+		if errMsg != nil {
+			ctx.JSON(errMsg.Status(), errMsg)
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, domain.NewMessageSuccess("success create cash payout", shift))
+	}
+}
+
+func (sh *shiftHandler) CloseShift() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var body domain.ShiftCloseRequest
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			errMsg := helper.ValidateRequest(err)
+			ctx.JSON(errMsg.Status(), errMsg)
+			return
+		}
+
+		userData := ctx.MustGet("userData").(domain.UserAdmin)
+		body.UserAdminID = userData.ID
+
+		shift, errMsg := sh.shiftService.CloseShift(ctx, body)
+		if errMsg != nil {
+			ctx.JSON(errMsg.Status(), errMsg)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success close shift", shift))
+	}
+}
+
+func (sh *shiftHandler) GetShiftByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userData := ctx.MustGet("userData").(domain.UserAdmin)
+
+		shift, err := sh.shiftService.GetShiftByID(ctx, ctx.Param("id"), userData)
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success get shift", shift))
+	}
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"eniqilo-store/internal/domain"
+	"eniqilo-store/internal/helper"
+	"eniqilo-store/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TaxRateHandler interface {
+	CreateTaxRate() gin.HandlerFunc
+	GetTaxRates() gin.HandlerFunc
+	UpdateTaxRateByID() gin.HandlerFunc
+	DeleteTaxRateByID() gin.HandlerFunc
+}
+
+type taxRateHandler struct {
+	taxRateService service.TaxRateService
+}
+
+func NewTaxRateHandler(taxRateService service.TaxRateService) TaxRateHandler {
+	return &taxRateHandler{
+		taxRateService: taxRateService,
+	}
+}
+
+func (th *taxRateHandler) CreateTaxRate() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		taxRateBody := domain.TaxRateRequest{}
+		if err := ctx.ShouldBindJSON(&taxRateBody); err != nil {
+			err := helper.ValidateRequest(err)
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		taxRate := taxRateBody.NewTaxRate()
+		err := th.taxRateService.CreateTaxRate(ctx, taxRate)
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusCreated, domain.NewMessageSuccess("success create tax rate", taxRate.NewTaxRateResponse()))
+	}
+}
+
+func (th *taxRateHandler) GetTaxRates() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		taxRates, err := th.taxRateService.GetTaxRates(ctx)
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success get tax rates", taxRates))
+	}
+}
+
+func (th *taxRateHandler) UpdateTaxRateByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		taxRateBody := domain.TaxRateRequest{}
+		if err := ctx.ShouldBindJSON(&taxRateBody); err != nil {
+			err := helper.ValidateRequest(err)
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		taxRate := taxRateBody.NewTaxRate()
+		taxRate.ID = ctx.Param("id")
+		err := th.taxRateService.UpdateTaxRateByID(ctx, taxRate)
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success update tax rate", taxRate.NewTaxRateResponse()))
+	}
+}
+
+func (th *taxRateHandler) DeleteTaxRateByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		err := th.taxRateService.DeleteTaxRateByID(ctx, ctx.Param("id"))
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success delete tax rate", nil))
+	}
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"eniqilo-store/internal/domain"
+	"eniqilo-store/internal/helper"
+	"eniqilo-store/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TwoFactorHandler interface {
+	GetTwoFactor() gin.HandlerFunc
+	EnrolTwoFactor() gin.HandlerFunc
+	ConfirmTwoFactor() gin.HandlerFunc
+	RegenerateRecoveryCodes() gin.HandlerFunc
+	DisableTwoFactor() gin.HandlerFunc
+}
+
+type twoFactorHandler struct {
+	twoFactorService service.TwoFactorService
+}
+
+func NewTwoFactorHandler(twoFactorService service.TwoFactorService) TwoFactorHandler {
+	return &twoFactorHandler{
+		twoFactorService: twoFactorService,
+	}
+}
+
+func (t *twoFactorHandler) GetTwoFactor() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userData := ctx.MustGet("userData").(domain.UserAdmin)
+
+		response, err := t.twoFactorService.GetTwoFactor(ctx.Request.Context(), userData)
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success get two-factor authentication", response))
+	}
+}
+
+func (t *twoFactorHandler) EnrolTwoFactor() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userData := ctx.MustGet("userData").(domain.UserAdmin)
+
+		response, err := t.twoFactorService.EnrolTwoFactor(ctx.Request.Context(), userData)
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success enrol two-factor authentication", response))
+	}
+}
+
+func (t *twoFactorHandler) ConfirmTwoFactor() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		body := domain.TwoFactorCodeRequest{}
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			err := helper.ValidateRequest(err)
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		body.UserAdmin = ctx.MustGet("userData").(domain.UserAdmin)
+		body.IP = ctx.ClientIP()
+		body.UserAgent = ctx.Request.UserAgent()
+
+		response, err := t.twoFactorService.ConfirmTwoFactor(ctx.Request.Context(), body)
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success enable two-factor authentication", response))
+	}
+}
+
+func (t *twoFactorHandler) RegenerateRecoveryCodes() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		body := domain.TwoFactorCodeRequest{}
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			err := helper.ValidateRequest(err)
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		body.UserAdmin = ctx.MustGet("userData").(domain.UserAdmin)
+		body.IP = ctx.ClientIP()
+		body.UserAgent = ctx.Request.UserAgent()
+
+		response, err := t.twoFactorService.RegenerateRecoveryCodes(ctx.Request.Context(), body)
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success regenerate recovery codes", response))
+	}
+}
+
+func (t *twoFactorHandler) DisableTwoFactor() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		body := domain.TwoFactorCodeRequest{}
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			err := helper.ValidateRequest(err)
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		body.UserAdmin = ctx.MustGet("userData").(domain.UserAdmin)
+		body.IP = ctx.ClientIP()
+		body.UserAgent = ctx.Request.UserAgent()
+
+		err := t.twoFactorService.DisableTwoFactor(ctx.Request.Context(), body)
+		if err != nil {
+			ctx.JSON(err.Status(), err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, domain.NewMessageSuccess("success disable two-factor authentication", nil))
+	}
+}
@@ -12,6 +12,17 @@ import (
 type UserAdminHandler interface {
 	RegisterUserAdminHandler() gin.HandlerFunc
 	LoginUserAdminHandler() gin.HandlerFunc
+	EnrolLoginHandler() gin.HandlerFunc
+	VerifyLoginHandler() gin.HandlerFunc
+	GetUserAdmins() gin.HandlerFunc
+	UpdateUserAdminRole() gin.HandlerFunc
+	CreateStaffInvitation() gin.HandlerFunc
+	GetStaffInvitations() gin.HandlerFunc
+	RevokeStaffInvitation() gin.HandlerFunc
+	RefreshTokenHandler() gin.HandlerFunc
+	LogoutUserAdminHandler() gin.HandlerFunc
+	RevokeUserAdminSessions() gin.HandlerFunc
+	GetLoginAttempts() gin.HandlerFunc
 }
 
 type userAdminHandler struct {
@@ -55,7 +66,55 @@ func (u *userAdminHandler) LoginUserAdminHandler() gin.HandlerFunc {
 			return
 		}
 
-		response, err := u.userAdminService.LoginUserAdminService(c.Request.Context(), userAdmin)
+		userAdmin.IP = c.ClientIP()
+		userAdmin.UserAgent = c.Request.UserAgent()
+
+		response, challenge, err := u.userAdminService.LoginUserAdminService(c.Request.Context(), userAdmin)
+		if err != nil {
+			c.JSON(err.Status(), err)
+			return
+		}
+		if challenge != nil {
+			c.JSON(http.StatusOK, domain.NewMessageSuccess("second factor required", challenge))
+			return
+		}
+
+		c.JSON(http.StatusOK, domain.NewMessageSuccess("success login", response))
+	}
+}
+
+func (u *userAdminHandler) EnrolLoginHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body := domain.LoginChallengeRequest{}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			err := helper.ValidateRequest(err)
+			c.JSON(err.Status(), err)
+			return
+		}
+
+		response, err := u.userAdminService.EnrolLoginService(c.Request.Context(), body)
+		if err != nil {
+			c.JSON(err.Status(), err)
+			return
+		}
+
+		c.JSON(http.StatusOK, domain.NewMessageSuccess("success enrol two-factor authentication", response))
+	}
+}
+
+func (u *userAdminHandler) VerifyLoginHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body := domain.LoginVerifyRequest{}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			err := helper.ValidateRequest(err)
+			c.JSON(err.Status(), err)
+			return
+		}
+
+		body.IP = c.ClientIP()
+		body.UserAgent = c.Request.UserAgent()
+
+		response, err := u.userAdminService.VerifyLoginService(c.Request.Context(), body)
 		if err != nil {
 			c.JSON(err.Status(), err)
 			return
@@ -64,3 +123,154 @@ func (u *userAdminHandler) LoginUserAdminHandler() gin.HandlerFunc {
 		c.JSON(http.StatusOK, domain.NewMessageSuccess("success login", response))
 	}
 }
+
+func (u *userAdminHandler) GetUserAdmins() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		response, err := u.userAdminService.GetUserAdmins(c.Request.Context())
+		if err != nil {
+			c.JSON(err.Status(), err)
+			return
+		}
+
+		c.JSON(http.StatusOK, domain.NewMessageSuccess("success get staff", response))
+	}
+}
+
+func (u *userAdminHandler) UpdateUserAdminRole() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body := domain.UserAdminRoleRequest{}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			err := helper.ValidateRequest(err)
+			c.JSON(err.Status(), err)
+			return
+		}
+
+		userData := c.MustGet("userData").(domain.UserAdmin)
+
+		response, err := u.userAdminService.UpdateUserAdminRole(c.Request.Context(), c.Param("id"), userData.ID, body)
+		if err != nil {
+			c.JSON(err.Status(), err)
+			return
+		}
+
+		c.JSON(http.StatusOK, domain.NewMessageSuccess("success update staff role", response))
+	}
+}
+
+func (u *userAdminHandler) CreateStaffInvitation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body := domain.StaffInvitationRequest{}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			err := helper.ValidateRequest(err)
+			c.JSON(err.Status(), err)
+			return
+		}
+
+		userData := c.MustGet("userData").(domain.UserAdmin)
+		body.UserAdminID = userData.ID
+		body.UserAdminRole = userData.Role
+
+		response, err := u.userAdminService.CreateStaffInvitation(c.Request.Context(), body)
+		if err != nil {
+			c.JSON(err.Status(), err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, domain.NewMessageSuccess("success create staff invitation", response))
+	}
+}
+
+func (u *userAdminHandler) GetStaffInvitations() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		response, err := u.userAdminService.GetStaffInvitations(c.Request.Context())
+		if err != nil {
+			c.JSON(err.Status(), err)
+			return
+		}
+
+		c.JSON(http.StatusOK, domain.NewMessageSuccess("success get staff invitations", response))
+	}
+}
+
+func (u *userAdminHandler) RevokeStaffInvitation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := u.userAdminService.RevokeStaffInvitation(c.Request.Context(), c.Param("id"))
+		if err != nil {
+			c.JSON(err.Status(), err)
+			return
+		}
+
+		c.JSON(http.StatusOK, domain.NewMessageSuccess("success revoke staff invitation", nil))
+	}
+}
+
+func (u *userAdminHandler) RefreshTokenHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body := domain.RefreshTokenRequest{}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			err := helper.ValidateRequest(err)
+			c.JSON(err.Status(), err)
+			return
+		}
+
+		response, err := u.userAdminService.RefreshTokenService(c.Request.Context(), body)
+		if err != nil {
+			c.JSON(err.Status(), err)
+			return
+		}
+
+		c.JSON(http.StatusOK, domain.NewMessageSuccess("success refresh token", response))
+	}
+}
+
+func (u *userAdminHandler) LogoutUserAdminHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body := domain.LogoutRequest{}
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&body); err != nil {
+				err := helper.ValidateRequest(err)
+				c.JSON(err.Status(), err)
+				return
+			}
+		}
+
+		userData := c.MustGet("userData").(domain.UserAdmin)
+		body.UserAdminID = userData.ID
+		body.SessionID = userData.SessionID
+
+		err := u.userAdminService.LogoutUserAdminService(c.Request.Context(), body)
+		if err != nil {
+			c.JSON(err.Status(), err)
+			return
+		}
+
+		c.JSON(http.StatusOK, domain.NewMessageSuccess("success logout", nil))
+	}
+}
+
+func (u *userAdminHandler) RevokeUserAdminSessions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := u.userAdminService.RevokeUserAdminSessions(c.Request.Context(), c.Param("id"))
+		if err != nil {
+			c.JSON(err.Status(), err)
+			return
+		}
+
+		c.JSON(http.StatusOK, domain.NewMessageSuccess("success revoke staff sessions", nil))
+	}
+}
+
+func (u *userAdminHandler) GetLoginAttempts() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var queryParams domain.LoginAttemptQueryParams
+		c.ShouldBindQuery(&queryParams)
+
+		response, err := u.userAdminService.GetLoginAttempts(c.Request.Context(), queryParams)
+		if err != nil {
+			c.JSON(err.Status(), err)
+			return
+		}
+
+		c.JSON(http.StatusOK, domain.NewMessageSuccess("success get login attempts", response))
+	}
+}
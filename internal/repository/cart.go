@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"eniqilo-store/internal/domain"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type CartRepository interface {
+	CreateCart(ctx context.Context, db *sql.DB, cart domain.Cart) error
+	GetCarts(ctx context.Context, db *sql.DB, queryParams domain.CartQueryParams) ([]domain.Cart, error)
+	GetCartByID(ctx context.Context, db *sql.DB, id string) (*domain.Cart, error)
+	GetCartForUpdate(ctx context.Context, tx *sql.Tx, id string) (*domain.Cart, error)
+	UpdateCart(ctx context.Context, tx *sql.Tx, cart domain.Cart) error
+	CheckOutCart(ctx context.Context, tx *sql.Tx, id string, checkoutID string) error
+	DeleteCartByID(ctx context.Context, db *sql.DB, id string) (int64, error)
+	DeleteExpiredCarts(ctx context.Context, db *sql.DB, before time.Time) (int64, error)
+	UpsertCartItem(ctx context.Context, tx *sql.Tx, item domain.CartItem) error
+	DeleteCartItem(ctx context.Context, tx *sql.Tx, cartID string, productID string) (int64, error)
+	GetCartItemsByCartIDs(ctx context.Context, db *sql.DB, cartIDs []string) (map[string][]domain.CartItem, error)
+}
+
+type cartRepository struct{}
+
+func NewCartRepository() CartRepository {
+	return &cartRepository{}
+}
+
+func (cr *cartRepository) CreateCart(ctx context.Context, db *sql.DB, cart domain.Cart) error {
+	query := `
+		INSERT INTO carts (id, created_at, updated_at, till, label, user_customer_id, user_admin_id, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := db.ExecContext(ctx, query,
+		cart.ID, cart.CreatedAt, cart.UpdatedAt, cart.Till, cart.Label, cart.UserCustomerID,
+		cart.UserAdminID, cart.Status,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (cr *cartRepository) GetCarts(ctx context.Context, db *sql.DB, queryParams domain.CartQueryParams) ([]domain.Cart, error) {
+	var whereClause []string
+	var args []any
+
+	if len(queryParams.Till) > 0 {
+		args = append(args, queryParams.Till)
+		whereClause = append(whereClause, fmt.Sprintf("till = $%d", len(args)))
+	}
+	switch queryParams.Status {
+	case domain.CartStatusOpen, domain.CartStatusParked, domain.CartStatusCheckedOut:
+		args = append(args, queryParams.Status)
+	default:
+		args = append(args, domain.CartStatusParked)
+	}
+	whereClause = append(whereClause, fmt.Sprintf("status = $%d", len(args)))
+
+	query := `
+		SELECT id, created_at, updated_at, till, label, user_customer_id, user_admin_id, status, checkout_id
+		FROM carts
+		WHERE ` + strings.Join(whereClause, " AND ") + `
+		ORDER BY updated_at desc, sid desc
+	`
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	carts := []domain.Cart{}
+	for rows.Next() {
+		cart := domain.Cart{}
+
+		err := rows.Scan(
+			&cart.ID, &cart.CreatedAt, &cart.UpdatedAt, &cart.Till, &cart.Label,
+			&cart.UserCustomerID, &cart.UserAdminID, &cart.Status, &cart.CheckoutID,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		carts = append(carts, cart)
+	}
+
+	return carts, nil
+}
+
+func (cr *cartRepository) GetCartByID(ctx context.Context, db *sql.DB, id string) (*domain.Cart, error) {
+	query := `
+		SELECT id, created_at, updated_at, till, label, user_customer_id, user_admin_id, status, checkout_id
+		FROM carts
+		WHERE id = $1
+	`
+	return scanCart(db.QueryRowContext(ctx, query, id))
+}
+
+func (cr *cartRepository) GetCartForUpdate(ctx context.Context, tx *sql.Tx, id string) (*domain.Cart, error) {
+	query := `
+		SELECT id, created_at, updated_at, till, label, user_customer_id, user_admin_id, status, checkout_id
+		FROM carts
+		WHERE id = $1
+		FOR UPDATE
+	`
+	return scanCart(tx.QueryRowContext(ctx, query, id))
+}
+
+func (cr *cartRepository) UpdateCart(ctx context.Context, tx *sql.Tx, cart domain.Cart) error {
+	query := `
+		UPDATE carts
+		SET updated_at = $2,
+			till = $3,
+			label = $4,
+			user_customer_id = $5,
+			status = $6
+		WHERE id = $1
+	`
+	_, err := tx.ExecContext(ctx, query, cart.ID, cart.UpdatedAt, cart.Till, cart.Label, cart.UserCustomerID, cart.Status)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (cr *cartRepository) CheckOutCart(ctx context.Context, tx *sql.Tx, id string, checkoutID string) error {
+	query := `
+		UPDATE carts
+		SET status = $2, checkout_id = $3, updated_at = now()
+		WHERE id = $1
+	`
+	_, err := tx.ExecContext(ctx, query, id, domain.CartStatusCheckedOut, checkoutID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (cr *cartRepository) DeleteCartByID(ctx context.Context, db *sql.DB, id string) (int64, error) {
+	query := `
+		DELETE FROM carts
+		WHERE id = $1 AND status <> $2
+	`
+	res, err := db.ExecContext(ctx, query, id, domain.CartStatusCheckedOut)
+	if err != nil {
+		if err, ok := err.(*pgconn.PgError); ok {
+			if err.Code == "22P02" {
+				return 0, nil
+			}
+		}
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// checked out carts are kept as the link to their checkout
+func (cr *cartRepository) DeleteExpiredCarts(ctx context.Context, db *sql.DB, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM carts
+		WHERE updated_at < $1 AND status <> $2
+	`
+	res, err := db.ExecContext(ctx, query, before, domain.CartStatusCheckedOut)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+func (cr *cartRepository) UpsertCartItem(ctx context.Context, tx *sql.Tx, item domain.CartItem) error {
+	query := `
+		INSERT INTO cart_items (id, created_at, cart_id, product_id, quantity)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (cart_id, product_id) DO UPDATE
+		SET quantity = EXCLUDED.quantity
+	`
+	_, err := tx.ExecContext(ctx, query, item.ID, item.CreatedAt, item.CartID, item.ProductID, item.Quantity)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (cr *cartRepository) DeleteCartItem(ctx context.Context, tx *sql.Tx, cartID string, productID string) (int64, error) {
+	query := `
+		DELETE FROM cart_items
+		WHERE cart_id = $1 AND product_id::text = $2
+	`
+	res, err := tx.ExecContext(ctx, query, cartID, productID)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+func (cr *cartRepository) GetCartItemsByCartIDs(ctx context.Context, db *sql.DB, cartIDs []string) (map[string][]domain.CartItem, error) {
+	query := `
+		SELECT ci.id, ci.created_at, ci.cart_id, ci.product_id, ci.quantity, p.name, p.sku, p.price
+		FROM cart_items ci
+		INNER JOIN products p ON p.id = ci.product_id
+		WHERE ci.cart_id = any ($1)
+		ORDER BY ci.sid
+	`
+	rows, err := db.QueryContext(ctx, query, cartIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := map[string][]domain.CartItem{}
+	for rows.Next() {
+		item := domain.CartItem{}
+
+		err := rows.Scan(
+			&item.ID, &item.CreatedAt, &item.CartID, &item.ProductID, &item.Quantity,
+			&item.ProductName, &item.ProductSku, &item.Price,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		items[item.CartID] = append(items[item.CartID], item)
+	}
+
+	return items, nil
+}
+
+func scanCart(row *sql.Row) (*domain.Cart, error) {
+	cart := domain.Cart{}
+	err := row.Scan(
+		&cart.ID, &cart.CreatedAt, &cart.UpdatedAt, &cart.Till, &cart.Label,
+		&cart.UserCustomerID, &cart.UserAdminID, &cart.Status, &cart.CheckoutID,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		if err, ok := err.(*pgconn.PgError); ok {
+			if err.Code == "22P02" {
+				return nil, nil
+			}
+		}
+		return nil, err
+	}
+
+	return &cart, nil
+}
@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"eniqilo-store/internal/domain"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type CouponRepository interface {
+	CreateCoupon(ctx context.Context, db *sql.DB, coupon domain.Coupon) error
+	GetCoupons(ctx context.Context, db *sql.DB) ([]domain.Coupon, error)
+	GetCouponByCode(ctx context.Context, tx *sql.Tx, code string) (*domain.Coupon, error)
+	GetCouponByCodeForUpdate(ctx context.Context, tx *sql.Tx, code string) (*domain.Coupon, error)
+	UpdateCouponByID(ctx context.Context, db *sql.DB, coupon domain.Coupon) (int64, error)
+	DeleteCouponByID(ctx context.Context, db *sql.DB, id string) (int64, error)
+	CountCustomerRedemptions(ctx context.Context, tx *sql.Tx, couponID string, customerID string) (int, error)
+	CreateCouponRedemption(ctx context.Context, tx *sql.Tx, redemption domain.CouponRedemption) error
+	DeleteCouponRedemptionByCheckoutID(ctx context.Context, tx *sql.Tx, checkoutID string) error
+	GetCouponReport(ctx context.Context, db *sql.DB, queryParams domain.CouponReportQueryParams, storeLocation *time.Location) ([]domain.CouponReport, error)
+}
+
+type couponRepository struct{}
+
+func NewCouponRepository() CouponRepository {
+	return &couponRepository{}
+}
+
+func (cr *couponRepository) CreateCoupon(ctx context.Context, db *sql.DB, coupon domain.Coupon) error {
+	query := `
+		INSERT INTO coupons (id, created_at, code, type, value, starts_at, ends_at, max_redemptions,
+			max_redemptions_per_customer, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+	_, err := db.ExecContext(ctx, query,
+		coupon.ID, coupon.CreatedAt, coupon.Code, coupon.Type, coupon.Value, coupon.StartsAt,
+		coupon.EndsAt, coupon.MaxRedemptions, coupon.MaxRedemptionsPerCustomer, coupon.IsActive,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (cr *couponRepository) GetCoupons(ctx context.Context, db *sql.DB) ([]domain.Coupon, error) {
+	query := `
+		SELECT id, created_at, code, type, value, starts_at, ends_at, max_redemptions,
+			max_redemptions_per_customer, redemption_count, is_active
+		FROM coupons
+		ORDER BY created_at desc, sid desc
+	`
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	coupons := []domain.Coupon{}
+	for rows.Next() {
+		coupon := domain.Coupon{}
+
+		err := rows.Scan(
+			&coupon.ID, &coupon.CreatedAt, &coupon.Code, &coupon.Type, &coupon.Value,
+			&coupon.StartsAt, &coupon.EndsAt, &coupon.MaxRedemptions,
+			&coupon.MaxRedemptionsPerCustomer, &coupon.RedemptionCount, &coupon.IsActive,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		coupons = append(coupons, coupon)
+	}
+
+	return coupons, nil
+}
+
+func (cr *couponRepository) GetCouponByCode(ctx context.Context, tx *sql.Tx, code string) (*domain.Coupon, error) {
+	query := `
+		SELECT id, created_at, code, type, value, starts_at, ends_at, max_redemptions,
+			max_redemptions_per_customer, redemption_count, is_active
+		FROM coupons
+		WHERE code = $1
+	`
+	return scanCoupon(tx.QueryRowContext(ctx, query, code))
+}
+
+func (cr *couponRepository) GetCouponByCodeForUpdate(ctx context.Context, tx *sql.Tx, code string) (*domain.Coupon, error) {
+	query := `
+		SELECT id, created_at, code, type, value, starts_at, ends_at, max_redemptions,
+			max_redemptions_per_customer, redemption_count, is_active
+		FROM coupons
+		WHERE code = $1
+		FOR UPDATE
+	`
+	return scanCoupon(tx.QueryRowContext(ctx, query, code))
+}
+
+func scanCoupon(row *sql.Row) (*domain.Coupon, error) {
+	coupon := domain.Coupon{}
+	err := row.Scan(
+		&coupon.ID, &coupon.CreatedAt, &coupon.Code, &coupon.Type, &coupon.Value,
+		&coupon.StartsAt, &coupon.EndsAt, &coupon.MaxRedemptions,
+		&coupon.MaxRedemptionsPerCustomer, &coupon.RedemptionCount, &coupon.IsActive,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &coupon, nil
+}
+
+func (cr *couponRepository) UpdateCouponByID(ctx context.Context, db *sql.DB, coupon domain.Coupon) (int64, error) {
+	query := `
+		UPDATE coupons
+		SET code = $2,
+			type = $3,
+			value = $4,
+			starts_at = $5,
+			ends_at = $6,
+			max_redemptions = $7,
+			max_redemptions_per_customer = $8,
+			is_active = $9
+		WHERE id = $1
+	`
+	res, err := db.ExecContext(ctx, query,
+		coupon.ID, coupon.Code, coupon.Type, coupon.Value, coupon.StartsAt, coupon.EndsAt,
+		coupon.MaxRedemptions, coupon.MaxRedemptionsPerCustomer, coupon.IsActive,
+	)
+	if err != nil {
+		if err, ok := err.(*pgconn.PgError); ok {
+			if err.Code == "22P02" {
+				return 0, nil
+			}
+		}
+		return 0, err
+	}
+
+	affRow, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return affRow, nil
+}
+
+func (cr *couponRepository) DeleteCouponByID(ctx context.Context, db *sql.DB, id string) (int64, error) {
+	query := `
+		DELETE FROM coupons
+		WHERE id = $1
+	`
+	res, err := db.ExecContext(ctx, query, id)
+	if err != nil {
+		if err, ok := err.(*pgconn.PgError); ok {
+			if err.Code == "22P02" {
+				return 0, nil
+			}
+		}
+		return 0, err
+	}
+
+	affRow, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return affRow, nil
+}
+
+func (cr *couponRepository) CountCustomerRedemptions(ctx context.Context, tx *sql.Tx, couponID string, customerID string) (int, error) {
+	query := `
+		SELECT COUNT(id)
+		FROM coupon_redemptions
+		WHERE coupon_id = $1
+			AND user_customer_id = $2
+	`
+	var count int
+	err := tx.QueryRowContext(ctx, query, couponID, customerID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (cr *couponRepository) CreateCouponRedemption(ctx context.Context, tx *sql.Tx, redemption domain.CouponRedemption) error {
+	query := `
+		INSERT INTO coupon_redemptions (id, created_at, coupon_id, checkout_id, user_customer_id, discount)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := tx.ExecContext(ctx, query,
+		redemption.ID, redemption.CreatedAt, redemption.CouponID, redemption.CheckoutID,
+		redemption.UserCustomerID, redemption.Discount,
+	)
+	if err != nil {
+		return err
+	}
+
+	query = `
+		UPDATE coupons
+		SET redemption_count = redemption_count + 1
+		WHERE id = $1
+	`
+	_, err = tx.ExecContext(ctx, query, redemption.CouponID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (cr *couponRepository) DeleteCouponRedemptionByCheckoutID(ctx context.Context, tx *sql.Tx, checkoutID string) error {
+	query := `
+		WITH redemption AS (
+			DELETE FROM coupon_redemptions
+			WHERE checkout_id = $1
+			RETURNING coupon_id
+		)
+		UPDATE coupons
+		SET redemption_count = redemption_count - 1
+		WHERE id IN (SELECT coupon_id FROM redemption)
+	`
+	_, err := tx.ExecContext(ctx, query, checkoutID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (cr *couponRepository) GetCouponReport(ctx context.Context, db *sql.DB, queryParams domain.CouponReportQueryParams, storeLocation *time.Location) ([]domain.CouponReport, error) {
+	queryCondition, args := checkoutHistoryCondition(domain.CheckoutHistoryQueryParams{
+		From: queryParams.From,
+		To:   queryParams.To,
+	}, storeLocation)
+
+	query := `
+		SELECT co.id, co.code, COUNT(cr.id), COUNT(DISTINCT cr.user_customer_id), SUM(cr.discount)
+		FROM coupon_redemptions cr
+		INNER JOIN coupons co ON co.id = cr.coupon_id
+		INNER JOIN checkouts c ON c.id = cr.checkout_id
+	`
+	query += queryCondition
+	query += `
+		GROUP BY co.id, co.code
+		ORDER BY co.code
+	`
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := []domain.CouponReport{}
+	for rows.Next() {
+		report := domain.CouponReport{}
+
+		err := rows.Scan(
+			&report.CouponID, &report.Code, &report.RedemptionCount,
+			&report.CustomerCount, &report.Discount,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
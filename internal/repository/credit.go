@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"eniqilo-store/internal/domain"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type CreditRepository interface {
+	BulkCreateCreditEntry(ctx context.Context, tx *sql.Tx, entries []domain.CreditEntry) error
+	GetCreditLimit(ctx context.Context, tx *sql.Tx, customerID string) (int, error)
+	UpdateCreditLimit(ctx context.Context, db *sql.DB, customerID string, creditLimit int) (int64, error)
+	GetCreditBalance(ctx context.Context, tx *sql.Tx, customerID string) (int, error)
+	GetCreditAccount(ctx context.Context, db *sql.DB, customerID string) (*domain.CreditAccountResponse, error)
+	GetCreditEntriesByCheckoutID(ctx context.Context, tx *sql.Tx, checkoutID string) ([]domain.CreditEntry, error)
+	GetCreditStatement(ctx context.Context, db *sql.DB, customerID string, queryParams domain.CreditStatementQueryParams, storeLocation *time.Location) (int, []domain.CreditEntry, error)
+	GetOutstandingCreditEntries(ctx context.Context, db *sql.DB) ([]domain.CreditAccountEntries, error)
+}
+
+type creditRepository struct{}
+
+func NewCreditRepository() CreditRepository {
+	return &creditRepository{}
+}
+
+func (cr *creditRepository) BulkCreateCreditEntry(ctx context.Context, tx *sql.Tx, entries []domain.CreditEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	inserts := []string{}
+	args := []any{}
+
+	for _, e := range entries {
+		argsPos := len(args) + 1
+		inserts = append(inserts, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)", argsPos, argsPos+1, argsPos+2, argsPos+3, argsPos+4, argsPos+5, argsPos+6, argsPos+7, argsPos+8))
+		args = append(args, e.ID, e.CreatedAt, e.UserCustomerID, e.CheckoutID, e.UserAdminID, e.ShiftID, e.Type, e.Method, e.Amount)
+	}
+
+	query := `
+		INSERT INTO credit_entries (id, created_at, user_customer_id, checkout_id, user_admin_id, shift_id, type, method, amount)
+		VALUES `
+	query += strings.Join(inserts, ", ")
+
+	_, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (cr *creditRepository) GetCreditLimit(ctx context.Context, tx *sql.Tx, customerID string) (int, error) {
+	query := `SELECT credit_limit FROM user_customers WHERE id = $1`
+	var creditLimit int
+	err := tx.QueryRowContext(ctx, query, customerID).Scan(&creditLimit)
+	if err != nil {
+		return 0, err
+	}
+
+	return creditLimit, nil
+}
+
+func (cr *creditRepository) UpdateCreditLimit(ctx context.Context, db *sql.DB, customerID string, creditLimit int) (int64, error) {
+	query := `UPDATE user_customers SET credit_limit = $2 WHERE id = $1`
+	res, err := db.ExecContext(ctx, query, customerID, creditLimit)
+	if err != nil {
+		if err, ok := err.(*pgconn.PgError); ok {
+			if err.Code == "22P02" {
+				return 0, nil
+			}
+		}
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+func (cr *creditRepository) GetCreditBalance(ctx context.Context, tx *sql.Tx, customerID string) (int, error) {
+	query := `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM credit_entries
+		WHERE user_customer_id = $1
+	`
+	var balance int
+	err := tx.QueryRowContext(ctx, query, customerID).Scan(&balance)
+	if err != nil {
+		return 0, err
+	}
+
+	return balance, nil
+}
+
+func (cr *creditRepository) GetCreditAccount(ctx context.Context, db *sql.DB, customerID string) (*domain.CreditAccountResponse, error) {
+	query := `
+		SELECT uc.id, uc.credit_limit, COALESCE(SUM(ce.amount), 0)
+		FROM user_customers uc
+		LEFT JOIN credit_entries ce ON ce.user_customer_id = uc.id
+		WHERE uc.id = $1
+		GROUP BY uc.id
+	`
+	account := domain.CreditAccountResponse{}
+	err := db.QueryRowContext(ctx, query, customerID).Scan(&account.CustomerID, &account.CreditLimit, &account.Balance)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		if err, ok := err.(*pgconn.PgError); ok {
+			if err.Code == "22P02" {
+				return nil, nil
+			}
+		}
+		return nil, err
+	}
+	account.Available = domain.CreditAvailable(account.CreditLimit, account.Balance)
+
+	return &account, nil
+}
+
+func (cr *creditRepository) GetCreditEntriesByCheckoutID(ctx context.Context, tx *sql.Tx, checkoutID string) ([]domain.CreditEntry, error) {
+	query := `
+		SELECT id, created_at, user_customer_id, checkout_id, COALESCE(user_admin_id::text, ''), type, method, amount
+		FROM credit_entries
+		WHERE checkout_id = $1
+		ORDER BY sid
+	`
+	rows, err := tx.QueryContext(ctx, query, checkoutID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanCreditEntries(rows)
+}
+
+func (cr *creditRepository) GetCreditStatement(ctx context.Context, db *sql.DB, customerID string, queryParams domain.CreditStatementQueryParams, storeLocation *time.Location) (int, []domain.CreditEntry, error) {
+	var openingBalance int
+	whereClause := []string{"user_customer_id = $1"}
+	args := []any{customerID}
+
+	if from, _, ok := parseHistoryDate(queryParams.From, storeLocation); ok {
+		openingQuery := `
+			SELECT COALESCE(SUM(amount), 0)
+			FROM credit_entries
+			WHERE user_customer_id = $1 AND created_at < $2
+		`
+		err := db.QueryRowContext(ctx, openingQuery, customerID, from).Scan(&openingBalance)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		whereClause = append(whereClause, fmt.Sprintf("created_at >= $%d", len(args)+1))
+		args = append(args, from)
+	}
+	if to, dateOnly, ok := parseHistoryDate(queryParams.To, storeLocation); ok {
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+			whereClause = append(whereClause, fmt.Sprintf("created_at < $%d", len(args)+1))
+		} else {
+			whereClause = append(whereClause, fmt.Sprintf("created_at <= $%d", len(args)+1))
+		}
+		args = append(args, to)
+	}
+
+	query := `
+		SELECT id, created_at, user_customer_id, checkout_id, COALESCE(user_admin_id::text, ''), type, method, amount
+		FROM credit_entries
+		WHERE ` + strings.Join(whereClause, " AND ") + `
+		ORDER BY created_at, sid
+	`
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer rows.Close()
+
+	entries, err := scanCreditEntries(rows)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return openingBalance, entries, nil
+}
+
+func (cr *creditRepository) GetOutstandingCreditEntries(ctx context.Context, db *sql.DB) ([]domain.CreditAccountEntries, error) {
+	query := `
+		SELECT uc.name, ce.id, ce.created_at, ce.user_customer_id, ce.checkout_id,
+			COALESCE(ce.user_admin_id::text, ''), ce.type, ce.method, ce.amount
+		FROM credit_entries ce
+		INNER JOIN user_customers uc ON uc.id = ce.user_customer_id
+		WHERE ce.user_customer_id IN (
+			SELECT user_customer_id
+			FROM credit_entries
+			GROUP BY user_customer_id
+			HAVING SUM(amount) > 0
+		)
+		ORDER BY uc.name, ce.user_customer_id, ce.created_at, ce.sid
+	`
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := []domain.CreditAccountEntries{}
+	for rows.Next() {
+		var customerName string
+		entry := domain.CreditEntry{}
+
+		err := rows.Scan(
+			&customerName, &entry.ID, &entry.CreatedAt, &entry.UserCustomerID, &entry.CheckoutID,
+			&entry.UserAdminID, &entry.Type, &entry.Method, &entry.Amount,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(accounts) == 0 || accounts[len(accounts)-1].CustomerID != entry.UserCustomerID {
+			accounts = append(accounts, domain.CreditAccountEntries{
+				CustomerID:   entry.UserCustomerID,
+				CustomerName: customerName,
+			})
+		}
+		account := &accounts[len(accounts)-1]
+		account.Entries = append(account.Entries, entry)
+	}
+
+	return accounts, nil
+}
+
+func scanCreditEntries(rows *sql.Rows) ([]domain.CreditEntry, error) {
+	entries := []domain.CreditEntry{}
+	for rows.Next() {
+		entry := domain.CreditEntry{}
+
+		err := rows.Scan(
+			&entry.ID, &entry.CreatedAt, &entry.UserCustomerID, &entry.CheckoutID,
+			&entry.UserAdminID, &entry.Type, &entry.Method, &entry.Amount,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"eniqilo-store/internal/domain"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type GiftCardRepository interface {
+	CreateGiftCard(ctx context.Context, tx *sql.Tx, giftCard domain.GiftCard) error
+	GetGiftCardByCode(ctx context.Context, db *sql.DB, code string) (*domain.GiftCard, error)
+	GetGiftCardsByCodesForUpdate(ctx context.Context, tx *sql.Tx, codes []string) ([]domain.GiftCard, error)
+	UpdateGiftCardStatusByCode(ctx context.Context, db *sql.DB, code string, status string, expiresAt *time.Time) (int64, error)
+	GetGiftCardBalance(ctx context.Context, db *sql.DB, giftCardID string) (int, error)
+	GetGiftCardBalances(ctx context.Context, tx *sql.Tx, giftCardIDs []string) (map[string]int, error)
+	BulkCreateGiftCardEntry(ctx context.Context, tx *sql.Tx, entries []domain.GiftCardEntry) error
+	GetGiftCardEntriesByCheckoutID(ctx context.Context, tx *sql.Tx, checkoutID string) ([]domain.GiftCardEntry, error)
+}
+
+type giftCardRepository struct{}
+
+func NewGiftCardRepository() GiftCardRepository {
+	return &giftCardRepository{}
+}
+
+func (gr *giftCardRepository) CreateGiftCard(ctx context.Context, tx *sql.Tx, giftCard domain.GiftCard) error {
+	query := `
+		INSERT INTO gift_cards (id, created_at, code, checkout_id, initial_balance, status, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := tx.ExecContext(ctx, query,
+		giftCard.ID, giftCard.CreatedAt, giftCard.Code, giftCard.CheckoutID,
+		giftCard.InitialBalance, giftCard.Status, giftCard.ExpiresAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (gr *giftCardRepository) GetGiftCardByCode(ctx context.Context, db *sql.DB, code string) (*domain.GiftCard, error) {
+	query := `
+		SELECT id, created_at, code, checkout_id, initial_balance, status, expires_at
+		FROM gift_cards
+		WHERE code = $1
+	`
+	giftCard := domain.GiftCard{}
+	err := db.QueryRowContext(ctx, query, code).Scan(
+		&giftCard.ID, &giftCard.CreatedAt, &giftCard.Code, &giftCard.CheckoutID,
+		&giftCard.InitialBalance, &giftCard.Status, &giftCard.ExpiresAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &giftCard, nil
+}
+
+func (gr *giftCardRepository) GetGiftCardsByCodesForUpdate(ctx context.Context, tx *sql.Tx, codes []string) ([]domain.GiftCard, error) {
+	query := `
+		SELECT id, created_at, code, checkout_id, initial_balance, status, expires_at
+		FROM gift_cards
+		WHERE code = any ($1)
+		ORDER BY code
+		FOR UPDATE
+	`
+	rows, err := tx.QueryContext(ctx, query, codes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	giftCards := []domain.GiftCard{}
+	for rows.Next() {
+		giftCard := domain.GiftCard{}
+
+		err := rows.Scan(
+			&giftCard.ID, &giftCard.CreatedAt, &giftCard.Code, &giftCard.CheckoutID,
+			&giftCard.InitialBalance, &giftCard.Status, &giftCard.ExpiresAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		giftCards = append(giftCards, giftCard)
+	}
+
+	return giftCards, nil
+}
+
+func (gr *giftCardRepository) UpdateGiftCardStatusByCode(ctx context.Context, db *sql.DB, code string, status string, expiresAt *time.Time) (int64, error) {
+	query := `
+		UPDATE gift_cards
+		SET status = $2, expires_at = $3
+		WHERE code = $1
+	`
+	res, err := db.ExecContext(ctx, query, code, status, expiresAt)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+func (gr *giftCardRepository) GetGiftCardBalance(ctx context.Context, db *sql.DB, giftCardID string) (int, error) {
+	query := `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM gift_card_entries
+		WHERE gift_card_id = $1
+	`
+	var balance int
+	err := db.QueryRowContext(ctx, query, giftCardID).Scan(&balance)
+	if err != nil {
+		return 0, err
+	}
+
+	return balance, nil
+}
+
+func (gr *giftCardRepository) GetGiftCardBalances(ctx context.Context, tx *sql.Tx, giftCardIDs []string) (map[string]int, error) {
+	query := `
+		SELECT gift_card_id, SUM(amount)
+		FROM gift_card_entries
+		WHERE gift_card_id = any ($1)
+		GROUP BY gift_card_id
+	`
+	rows, err := tx.QueryContext(ctx, query, giftCardIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	balances := map[string]int{}
+	for rows.Next() {
+		var giftCardID string
+		var balance int
+
+		err := rows.Scan(&giftCardID, &balance)
+		if err != nil {
+			return nil, err
+		}
+
+		balances[giftCardID] = balance
+	}
+
+	return balances, nil
+}
+
+func (gr *giftCardRepository) BulkCreateGiftCardEntry(ctx context.Context, tx *sql.Tx, entries []domain.GiftCardEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	inserts := []string{}
+	args := []any{}
+
+	for _, e := range entries {
+		argsPos := len(args) + 1
+		inserts = append(inserts, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d)", argsPos, argsPos+1, argsPos+2, argsPos+3, argsPos+4, argsPos+5))
+		args = append(args, e.ID, e.CreatedAt, e.GiftCardID, e.CheckoutID, e.Type, e.Amount)
+	}
+
+	query := `
+		INSERT INTO gift_card_entries (id, created_at, gift_card_id, checkout_id, type, amount)
+		VALUES `
+	query += strings.Join(inserts, ", ")
+
+	_, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (gr *giftCardRepository) GetGiftCardEntriesByCheckoutID(ctx context.Context, tx *sql.Tx, checkoutID string) ([]domain.GiftCardEntry, error) {
+	query := `
+		SELECT id, created_at, gift_card_id, checkout_id, type, amount
+		FROM gift_card_entries
+		WHERE checkout_id = $1
+		ORDER BY sid
+	`
+	rows, err := tx.QueryContext(ctx, query, checkoutID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []domain.GiftCardEntry{}
+	for rows.Next() {
+		entry := domain.GiftCardEntry{}
+
+		err := rows.Scan(&entry.ID, &entry.CreatedAt, &entry.GiftCardID, &entry.CheckoutID, &entry.Type, &entry.Amount)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"eniqilo-store/internal/domain"
+	"time"
+)
+
+type IdempotencyKeyRepository interface {
+	GetIdempotencyKey(ctx context.Context, db *sql.DB, userAdminID string, key string) (*domain.IdempotencyKey, error)
+	CreateIdempotencyKey(ctx context.Context, tx *sql.Tx, idempotencyKey domain.IdempotencyKey) (int64, error)
+	DeleteExpiredIdempotencyKeys(ctx context.Context, db *sql.DB, now time.Time) (int64, error)
+}
+
+type idempotencyKeyRepository struct{}
+
+func NewIdempotencyKeyRepository() IdempotencyKeyRepository {
+	return &idempotencyKeyRepository{}
+}
+
+func (ikr *idempotencyKeyRepository) GetIdempotencyKey(ctx context.Context, db *sql.DB, userAdminID string, key string) (*domain.IdempotencyKey, error) {
+	query := `
+		SELECT id, user_admin_id, key, request_hash, response_status, response_body, COALESCE(checkout_id::text, ''),
+			created_at, expires_at
+		FROM idempotency_keys
+		WHERE user_admin_id = $1
+			AND key = $2
+			AND expires_at > now()
+	`
+	idempotencyKey := domain.IdempotencyKey{}
+	var responseBody []byte
+	err := db.QueryRowContext(ctx, query, userAdminID, key).Scan(
+		&idempotencyKey.ID, &idempotencyKey.UserAdminID, &idempotencyKey.Key, &idempotencyKey.RequestHash,
+		&idempotencyKey.ResponseStatus, &responseBody, &idempotencyKey.CheckoutID,
+		&idempotencyKey.CreatedAt, &idempotencyKey.ExpiresAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	idempotencyKey.ResponseBody = responseBody
+
+	return &idempotencyKey, nil
+}
+
+// an expired key is taken over, zero affected rows means another request owns it
+func (ikr *idempotencyKeyRepository) CreateIdempotencyKey(ctx context.Context, tx *sql.Tx, idempotencyKey domain.IdempotencyKey) (int64, error) {
+	query := `
+		INSERT INTO idempotency_keys (id, user_admin_id, key, request_hash, response_status, response_body, checkout_id, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, '')::uuid, $8, $9)
+		ON CONFLICT (user_admin_id, key) DO UPDATE
+		SET id = EXCLUDED.id,
+			request_hash = EXCLUDED.request_hash,
+			response_status = EXCLUDED.response_status,
+			response_body = EXCLUDED.response_body,
+			checkout_id = EXCLUDED.checkout_id,
+			created_at = EXCLUDED.created_at,
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+	`
+	res, err := tx.ExecContext(ctx, query,
+		idempotencyKey.ID, idempotencyKey.UserAdminID, idempotencyKey.Key, idempotencyKey.RequestHash,
+		idempotencyKey.ResponseStatus, string(idempotencyKey.ResponseBody),
+		idempotencyKey.CheckoutID, idempotencyKey.CreatedAt, idempotencyKey.ExpiresAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	affRow, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return affRow, nil
+}
+
+func (ikr *idempotencyKeyRepository) DeleteExpiredIdempotencyKeys(ctx context.Context, db *sql.DB, now time.Time) (int64, error) {
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE expires_at <= $1
+	`
+	res, err := db.ExecContext(ctx, query, now)
+	if err != nil {
+		return 0, err
+	}
+
+	affRow, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return affRow, nil
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"eniqilo-store/internal/domain"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type LoginAttemptRepository interface {
+	LockLoginAttempts(ctx context.Context, tx *sql.Tx, phoneNumber string) error
+	CreateLoginAttempt(ctx context.Context, tx *sql.Tx, attempt domain.LoginAttempt) error
+	GetAccountLoginFailures(ctx context.Context, tx *sql.Tx, phoneNumber string, since time.Time) (domain.LoginFailures, error)
+	GetIPLoginFailures(ctx context.Context, tx *sql.Tx, ip string, since time.Time) (domain.LoginFailures, error)
+	GetLoginAttempts(ctx context.Context, db *sql.DB, queryParams domain.LoginAttemptQueryParams, limit int, offset int) ([]domain.LoginAttempt, error)
+}
+
+type loginAttemptRepository struct{}
+
+func NewLoginAttemptRepository() LoginAttemptRepository {
+	return &loginAttemptRepository{}
+}
+
+// parallel guesses at one account can't slip past the failure count
+func (lr *loginAttemptRepository) LockLoginAttempts(ctx context.Context, tx *sql.Tx, phoneNumber string) error {
+	_, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('login:' || $1))`, phoneNumber)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (lr *loginAttemptRepository) CreateLoginAttempt(ctx context.Context, tx *sql.Tx, attempt domain.LoginAttempt) error {
+	query := `
+		INSERT INTO login_attempts (id, created_at, phone_number, ip, user_agent, user_admin_id, result)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := tx.ExecContext(ctx, query,
+		attempt.ID, attempt.CreatedAt, attempt.PhoneNumber, attempt.IP, attempt.UserAgent,
+		attempt.UserAdminID, attempt.Result,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (lr *loginAttemptRepository) GetAccountLoginFailures(ctx context.Context, tx *sql.Tx, phoneNumber string, since time.Time) (domain.LoginFailures, error) {
+	query := `
+		SELECT COUNT(*), MAX(created_at)
+		FROM login_attempts
+		WHERE phone_number = $1 AND result IN ($2, $3) AND created_at >= $4
+			AND created_at > COALESCE((
+				SELECT MAX(created_at)
+				FROM login_attempts
+				WHERE phone_number = $1 AND result = $5
+			), '-infinity')
+	`
+	failures := domain.LoginFailures{}
+	err := tx.QueryRowContext(ctx, query,
+		phoneNumber, domain.LoginAttemptInvalidCredentials, domain.LoginAttemptInvalidSecondFactor, since,
+		domain.LoginAttemptSuccess,
+	).Scan(&failures.Count, &failures.LastFailure)
+
+	return failures, err
+}
+
+func (lr *loginAttemptRepository) GetIPLoginFailures(ctx context.Context, tx *sql.Tx, ip string, since time.Time) (domain.LoginFailures, error) {
+	query := `
+		SELECT COUNT(*), MAX(created_at)
+		FROM login_attempts
+		WHERE ip = $1 AND result IN ($2, $3) AND created_at >= $4
+	`
+	failures := domain.LoginFailures{}
+	err := tx.QueryRowContext(ctx, query,
+		ip, domain.LoginAttemptInvalidCredentials, domain.LoginAttemptInvalidSecondFactor, since,
+	).Scan(&failures.Count, &failures.LastFailure)
+
+	return failures, err
+}
+
+func (lr *loginAttemptRepository) GetLoginAttempts(ctx context.Context, db *sql.DB, queryParams domain.LoginAttemptQueryParams, limit int, offset int) ([]domain.LoginAttempt, error) {
+	var whereClause []string
+	var args []interface{}
+	if len(queryParams.PhoneNumber) > 0 {
+		args = append(args, queryParams.PhoneNumber)
+		whereClause = append(whereClause, fmt.Sprintf("phone_number = $%d", len(args)))
+	}
+	if len(queryParams.IP) > 0 {
+		args = append(args, queryParams.IP)
+		whereClause = append(whereClause, fmt.Sprintf("ip = $%d", len(args)))
+	}
+	if len(queryParams.Result) > 0 {
+		args = append(args, queryParams.Result)
+		whereClause = append(whereClause, fmt.Sprintf("result = $%d", len(args)))
+	}
+
+	query := `
+		SELECT id, created_at, phone_number, ip, user_agent, user_admin_id, result
+		FROM login_attempts
+	`
+	if len(whereClause) > 0 {
+		query += "WHERE " + strings.Join(whereClause, " AND ")
+	}
+	args = append(args, limit, offset)
+	query += fmt.Sprintf("\nORDER BY created_at desc, sid desc\nLIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attempts := []domain.LoginAttempt{}
+	for rows.Next() {
+		attempt := domain.LoginAttempt{}
+
+		err := rows.Scan(
+			&attempt.ID, &attempt.CreatedAt, &attempt.PhoneNumber, &attempt.IP, &attempt.UserAgent,
+			&attempt.UserAdminID, &attempt.Result,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		attempts = append(attempts, attempt)
+	}
+
+	return attempts, nil
+}
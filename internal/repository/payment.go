@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"eniqilo-store/internal/domain"
+	"fmt"
+	"strings"
+)
+
+type PaymentRepository interface {
+	BulkCreatePayment(ctx context.Context, tx *sql.Tx, payments []domain.Payment) error
+	GetPaymentsByCheckoutIDs(ctx context.Context, db *sql.DB, checkoutIDs []string) (map[string][]domain.Payment, error)
+	GetPaymentsByCheckoutID(ctx context.Context, tx *sql.Tx, checkoutID string) ([]domain.Payment, error)
+	GetReturnPaymentsByCheckoutID(ctx context.Context, tx *sql.Tx, checkoutID string) ([]domain.Payment, error)
+}
+
+type paymentRepository struct{}
+
+func NewPaymentRepository() PaymentRepository {
+	return &paymentRepository{}
+}
+
+func (pr *paymentRepository) BulkCreatePayment(ctx context.Context, tx *sql.Tx, payments []domain.Payment) error {
+	inserts := []string{}
+	args := []any{}
+
+	for _, p := range payments {
+		argsPos := len(args) + 1
+		inserts = append(inserts, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d)", argsPos, argsPos+1, argsPos+2, argsPos+3, argsPos+4, argsPos+5))
+		args = append(args, p.ID, p.CheckoutID, p.Method, p.Amount, p.GiftCardID, p.CreatedAt)
+	}
+
+	query := `
+		INSERT INTO payments (id, checkout_id, method, amount, gift_card_id, created_at)
+		VALUES `
+	query += strings.Join(inserts, ", ")
+
+	_, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (pr *paymentRepository) GetPaymentsByCheckoutIDs(ctx context.Context, db *sql.DB, checkoutIDs []string) (map[string][]domain.Payment, error) {
+	query := `
+		SELECT id, checkout_id, method, amount, gift_card_id, created_at
+		FROM payments
+		WHERE checkout_id = any ($1)
+		ORDER BY sid
+	`
+	rows, err := db.QueryContext(ctx, query, checkoutIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payments, err := scanPayments(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	paymentsByCheckoutID := map[string][]domain.Payment{}
+	for _, payment := range payments {
+		paymentsByCheckoutID[payment.CheckoutID] = append(paymentsByCheckoutID[payment.CheckoutID], payment)
+	}
+
+	return paymentsByCheckoutID, nil
+}
+
+func (pr *paymentRepository) GetPaymentsByCheckoutID(ctx context.Context, tx *sql.Tx, checkoutID string) ([]domain.Payment, error) {
+	query := `
+		SELECT id, checkout_id, method, amount, gift_card_id, created_at
+		FROM payments
+		WHERE checkout_id = $1
+		ORDER BY sid
+	`
+	rows, err := tx.QueryContext(ctx, query, checkoutID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPayments(rows)
+}
+
+func (pr *paymentRepository) GetReturnPaymentsByCheckoutID(ctx context.Context, tx *sql.Tx, checkoutID string) ([]domain.Payment, error) {
+	query := `
+		SELECT p.id, p.checkout_id, p.method, p.amount, p.gift_card_id, p.created_at
+		FROM payments p
+		INNER JOIN checkouts c ON c.id = p.checkout_id
+		WHERE c.return_of = $1
+		ORDER BY p.sid
+	`
+	rows, err := tx.QueryContext(ctx, query, checkoutID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPayments(rows)
+}
+
+func scanPayments(rows *sql.Rows) ([]domain.Payment, error) {
+	payments := []domain.Payment{}
+	for rows.Next() {
+		payment := domain.Payment{}
+
+		err := rows.Scan(&payment.ID, &payment.CheckoutID, &payment.Method, &payment.Amount, &payment.GiftCardID, &payment.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		payments = append(payments, payment)
+	}
+
+	return payments, nil
+}
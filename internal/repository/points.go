@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"eniqilo-store/internal/domain"
+	"fmt"
+	"strings"
+)
+
+type PointsRepository interface {
+	BulkCreatePointsEntry(ctx context.Context, tx *sql.Tx, entries []domain.PointsEntry) error
+	GetPointsBalance(ctx context.Context, tx *sql.Tx, customerID string) (int, error)
+	GetPointsEntriesByCheckoutID(ctx context.Context, tx *sql.Tx, checkoutID string) ([]domain.PointsEntry, error)
+	GetReturnPointsEntriesByCheckoutID(ctx context.Context, tx *sql.Tx, checkoutID string) ([]domain.PointsEntry, error)
+	GetPointsLedger(ctx context.Context, db *sql.DB, customerID string, limit int, offset int) ([]domain.PointsEntry, error)
+	CountPointsLedger(ctx context.Context, db *sql.DB, customerID string) (int, int, error)
+}
+
+type pointsRepository struct{}
+
+func NewPointsRepository() PointsRepository {
+	return &pointsRepository{}
+}
+
+func (pr *pointsRepository) BulkCreatePointsEntry(ctx context.Context, tx *sql.Tx, entries []domain.PointsEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	inserts := []string{}
+	args := []any{}
+
+	for _, e := range entries {
+		argsPos := len(args) + 1
+		inserts = append(inserts, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d)", argsPos, argsPos+1, argsPos+2, argsPos+3, argsPos+4, argsPos+5))
+		args = append(args, e.ID, e.CreatedAt, e.UserCustomerID, e.CheckoutID, e.Type, e.Points)
+	}
+
+	query := `
+		INSERT INTO points_entries (id, created_at, user_customer_id, checkout_id, type, points)
+		VALUES `
+	query += strings.Join(inserts, ", ")
+
+	_, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (pr *pointsRepository) GetPointsBalance(ctx context.Context, tx *sql.Tx, customerID string) (int, error) {
+	query := `
+		SELECT COALESCE(SUM(points), 0)
+		FROM points_entries
+		WHERE user_customer_id = $1
+	`
+	var balance int
+	err := tx.QueryRowContext(ctx, query, customerID).Scan(&balance)
+	if err != nil {
+		return 0, err
+	}
+
+	return balance, nil
+}
+
+func (pr *pointsRepository) GetPointsEntriesByCheckoutID(ctx context.Context, tx *sql.Tx, checkoutID string) ([]domain.PointsEntry, error) {
+	query := `
+		SELECT id, created_at, user_customer_id, checkout_id, type, points
+		FROM points_entries
+		WHERE checkout_id = $1
+		ORDER BY sid
+	`
+	rows, err := tx.QueryContext(ctx, query, checkoutID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPointsEntries(rows)
+}
+
+func (pr *pointsRepository) GetReturnPointsEntriesByCheckoutID(ctx context.Context, tx *sql.Tx, checkoutID string) ([]domain.PointsEntry, error) {
+	query := `
+		SELECT pe.id, pe.created_at, pe.user_customer_id, pe.checkout_id, pe.type, pe.points
+		FROM points_entries pe
+		INNER JOIN checkouts c ON c.id = pe.checkout_id
+		WHERE c.return_of = $1
+		ORDER BY pe.sid
+	`
+	rows, err := tx.QueryContext(ctx, query, checkoutID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPointsEntries(rows)
+}
+
+func (pr *pointsRepository) GetPointsLedger(ctx context.Context, db *sql.DB, customerID string, limit int, offset int) ([]domain.PointsEntry, error) {
+	query := `
+		SELECT id, created_at, user_customer_id, checkout_id, type, points
+		FROM points_entries
+		WHERE user_customer_id = $1
+		ORDER BY created_at desc, sid desc
+		limit $2 offset $3
+	`
+	rows, err := db.QueryContext(ctx, query, customerID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPointsEntries(rows)
+}
+
+func (pr *pointsRepository) CountPointsLedger(ctx context.Context, db *sql.DB, customerID string) (int, int, error) {
+	query := `
+		SELECT COUNT(id), COALESCE(SUM(points), 0)
+		FROM points_entries
+		WHERE user_customer_id = $1
+	`
+	var count, balance int
+	err := db.QueryRowContext(ctx, query, customerID).Scan(&count, &balance)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return count, balance, nil
+}
+
+func scanPointsEntries(rows *sql.Rows) ([]domain.PointsEntry, error) {
+	entries := []domain.PointsEntry{}
+	for rows.Next() {
+		entry := domain.PointsEntry{}
+
+		err := rows.Scan(
+			&entry.ID, &entry.CreatedAt, &entry.UserCustomerID, &entry.CheckoutID,
+			&entry.Type, &entry.Points,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
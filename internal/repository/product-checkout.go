@@ -6,12 +6,25 @@ import (
 	"eniqilo-store/internal/domain"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 type CheckoutRepository interface {
 	CreateCheckout(ctx context.Context, tx *sql.Tx, checkout domain.Checkout, productCheckouts []domain.ProductCheckout) error
-	GetCheckoutHistory(ctx context.Context, db *sql.DB, queryParams domain.CheckoutHistoryQueryParams) ([]domain.GetCheckoutHistory, error)
+	GetCheckoutHistory(ctx context.Context, db *sql.DB, queryParams domain.CheckoutHistoryQueryParams, storeLocation *time.Location) ([]domain.GetCheckoutHistory, error)
+	CountCheckoutHistory(ctx context.Context, db *sql.DB, queryParams domain.CheckoutHistoryQueryParams, storeLocation *time.Location) (int, error)
+	GetCheckoutByID(ctx context.Context, db *sql.DB, id string) ([]domain.GetCheckoutHistory, error)
+	GetCashierSalesSummary(ctx context.Context, db *sql.DB, queryParams domain.CheckoutSummaryQueryParams, storeLocation *time.Location) ([]domain.CashierSalesSummary, error)
+	GetCheckoutForUpdate(ctx context.Context, tx *sql.Tx, id string) (*domain.Checkout, error)
+	GetProductCheckoutsByCheckoutID(ctx context.Context, tx *sql.Tx, checkoutID string) ([]domain.ProductCheckout, error)
+	GetReturnedQuantities(ctx context.Context, tx *sql.Tx, checkoutID string) (map[string]int, error)
+	GetReturnedTotal(ctx context.Context, tx *sql.Tx, checkoutID string) (int, error)
+	VoidCheckoutByID(ctx context.Context, tx *sql.Tx, checkout domain.Checkout) error
 	CreateProductCheckout(ctx context.Context, tx *sql.Tx, productCheckout domain.ProductCheckout) error
 	BulkCreateProductCheckout(ctx context.Context, tx *sql.Tx, productCheckout []domain.ProductCheckout) error
 }
@@ -24,10 +37,15 @@ func NewCheckoutRepository() CheckoutRepository {
 
 func (cr *checkoutRepository) CreateCheckout(ctx context.Context, tx *sql.Tx, checkout domain.Checkout, productCheckouts []domain.ProductCheckout) error {
 	query := `
-		INSERT INTO checkouts (id, created_at, user_customer_id, paid, change)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO checkouts (id, created_at, user_customer_id, user_admin_id, shift_id, type, return_of, return_reason, paid,
+			change, subtotal, discount, tax, total)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 	`
-	_, err := tx.ExecContext(ctx, query, checkout.ID, checkout.CreatedAt, checkout.UserCustomerID, checkout.Paid, checkout.Change)
+	_, err := tx.ExecContext(ctx, query,
+		checkout.ID, checkout.CreatedAt, checkout.UserCustomerID, checkout.UserAdminID, checkout.ShiftID,
+		checkout.Type, checkout.ReturnOf, checkout.ReturnReason, checkout.Paid, checkout.Change,
+		checkout.Subtotal, checkout.Discount, checkout.Tax, checkout.Total,
+	)
 	if err != nil {
 		return err
 	}
@@ -35,74 +53,265 @@ func (cr *checkoutRepository) CreateCheckout(ctx context.Context, tx *sql.Tx, ch
 	return nil
 }
 
-func (cr *checkoutRepository) GetCheckoutHistory(ctx context.Context, db *sql.DB, queryParams domain.CheckoutHistoryQueryParams) ([]domain.GetCheckoutHistory, error) {
-	var queryCondition string
-	var limitOffsetClause []string
-	var whereClause []string
-	var orderClause []string
-	var args []any
+func (cr *checkoutRepository) GetCheckoutHistory(ctx context.Context, db *sql.DB, queryParams domain.CheckoutHistoryQueryParams, storeLocation *time.Location) ([]domain.GetCheckoutHistory, error) {
+	queryCondition, args := checkoutHistoryCondition(queryParams, storeLocation)
 
-	if len(queryParams.Limit) == 0 {
-		limitOffsetClause = append(limitOffsetClause, "limit 5")
+	var orderClause []string
+	if queryParams.CreatedAt == "asc" || queryParams.CreatedAt == "desc" {
+		orderClause = append(orderClause, fmt.Sprintf("c.created_at %s", queryParams.CreatedAt))
 	} else {
-		limitOffsetClause = append(limitOffsetClause, fmt.Sprintf("limit $%d", len(args)+1))
-		args = append(args, queryParams.Limit)
+		orderClause = append(orderClause, "c.created_at desc")
 	}
-	if len(queryParams.Offset) == 0 {
-		limitOffsetClause = append(limitOffsetClause, "offset 0")
-	} else {
-		limitOffsetClause = append(limitOffsetClause, fmt.Sprintf("offset $%d", len(args)+1))
-		args = append(args, queryParams.Offset)
+	orderQuery := "\nORDER BY " + strings.Join(orderClause, ", ") + ", c.sid desc"
+
+	limit, offset := queryParams.LimitOffset()
+	limitOffsetClause := fmt.Sprintf("\nlimit $%d offset $%d", len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	subqueryCheckout := `WITH pageCheckouts AS (
+		SELECT c.id, c.created_at, c.user_customer_id, c.user_admin_id, c.shift_id, c.type, c.return_of,
+			c.return_reason, c.paid, c.change, c.subtotal, c.discount, c.tax, c.total, c.voided_at,
+			c.voided_by, c.void_reason, c.sid
+		FROM checkouts c
+	`
+	subqueryCheckout += queryCondition + orderQuery + limitOffsetClause + ")"
+
+	query := `
+		SELECT c.id, COALESCE(pc.id::text, ''), c.created_at, c.user_customer_id, COALESCE(c.user_admin_id::text, ''),
+			COALESCE(c.shift_id::text, ''), c.type, COALESCE(c.return_of::text, ''), COALESCE(c.return_reason, ''),
+			COALESCE(pc.product_id::text, ''), COALESCE(pc.product_name, ''), COALESCE(pc.product_sku, ''),
+			COALESCE(pc.quantity, 0), COALESCE(pc.price, 0), COALESCE(pc.restock_state, ''),
+			COALESCE(pc.discount, 0), COALESCE(pc.tax, 0), COALESCE(pc.tax_rate, 0),
+			COALESCE(pc.tax_inclusive, false), COALESCE(pc.total, 0), c.subtotal, c.discount, c.tax,
+			c.total, c.paid, c.change, c.voided_at, COALESCE(c.voided_by::text, ''),
+			COALESCE(c.void_reason, '')
+		FROM pageCheckouts c
+		LEFT JOIN product_checkouts pc ON pc.checkout_id = c.id
+	`
+	query = subqueryCheckout + query
+	query += orderQuery + ", pc.sid"
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	checkouts := []domain.GetCheckoutHistory{}
+	for rows.Next() {
+		checkout := domain.GetCheckoutHistory{}
+
+		err := rows.Scan(
+			&checkout.TransactionID, &checkout.ProductCheckoutID, &checkout.CreatedAt, &checkout.CustomerID,
+			&checkout.CashierID, &checkout.ShiftID, &checkout.Type, &checkout.ReturnOf, &checkout.ReturnReason,
+			&checkout.ProductID, &checkout.ProductName, &checkout.ProductSku, &checkout.Quantity,
+			&checkout.Price, &checkout.RestockState, &checkout.LineDiscount, &checkout.LineTax,
+			&checkout.TaxRate, &checkout.TaxInclusive, &checkout.LineTotal, &checkout.Subtotal,
+			&checkout.Discount, &checkout.Tax, &checkout.Total, &checkout.Paid, &checkout.Change,
+			&checkout.VoidedAt, &checkout.VoidedBy, &checkout.VoidReason,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		checkouts = append(checkouts, checkout)
+	}
+
+	return checkouts, nil
+}
+
+func (cr *checkoutRepository) CountCheckoutHistory(ctx context.Context, db *sql.DB, queryParams domain.CheckoutHistoryQueryParams, storeLocation *time.Location) (int, error) {
+	queryCondition, args := checkoutHistoryCondition(queryParams, storeLocation)
+
+	query := `
+		SELECT COUNT(c.id)
+		FROM checkouts c
+	`
+	query += queryCondition
+
+	var total int
+	err := db.QueryRowContext(ctx, query, args...).Scan(&total)
+	if err != nil {
+		return 0, err
 	}
 
+	return total, nil
+}
+
+func checkoutHistoryCondition(queryParams domain.CheckoutHistoryQueryParams, storeLocation *time.Location) (string, []any) {
+	var queryCondition string
+	var whereClause []string
+	var args []any
+
 	val := reflect.ValueOf(queryParams)
 	typ := val.Type()
 
 	for i := 0; i < val.NumField(); i++ {
 		key := strings.ToLower(typ.Field(i).Name)
 		value := val.Field(i).String()
+		argPos := len(args) + 1
 
-		if key == "limit" || key == "offset" {
+		if len(value) < 1 {
 			continue
 		}
 
-		if len(value) < 1 {
-			if key == "createdat" {
-				orderClause = append(orderClause, "c.created_at desc")
+		switch key {
+		case "customerid":
+			if _, err := uuid.Parse(value); err != nil {
+				continue
 			}
-			continue
-		}
 
-		if key == "createdat" {
-			if value != "asc" && value != "desc" {
+			whereClause = append(whereClause, fmt.Sprintf("c.user_customer_id = $%d", argPos))
+			args = append(args, value)
+		case "cashierid":
+			if _, err := uuid.Parse(value); err != nil {
 				continue
 			}
-			key = "created_at"
 
-			orderClause = append(orderClause, fmt.Sprintf("%s %s", key, value))
-			continue
+			whereClause = append(whereClause, fmt.Sprintf("c.user_admin_id = $%d", argPos))
+			args = append(args, value)
+		case "type":
+			if value != domain.CheckoutTypeSale && value != domain.CheckoutTypeReturn && value != domain.CheckoutTypeGiftCard {
+				continue
+			}
+
+			whereClause = append(whereClause, fmt.Sprintf("c.type = $%d", argPos))
+			args = append(args, value)
+		case "productid":
+			if _, err := uuid.Parse(value); err != nil {
+				continue
+			}
+
+			whereClause = append(whereClause, fmt.Sprintf(`EXISTS (
+				SELECT 1
+				FROM product_checkouts fpc
+				WHERE fpc.checkout_id = c.id
+					AND fpc.product_id = $%d
+			)`, argPos))
+			args = append(args, value)
+		case "from", "to":
+			date, dateOnly, ok := parseHistoryDate(value, storeLocation)
+			if !ok {
+				continue
+			}
+
+			if key == "from" {
+				whereClause = append(whereClause, fmt.Sprintf("c.created_at >= $%d", argPos))
+			} else {
+				// a bare date includes the whole day
+				if dateOnly {
+					date = date.AddDate(0, 0, 1)
+					whereClause = append(whereClause, fmt.Sprintf("c.created_at < $%d", argPos))
+				} else {
+					whereClause = append(whereClause, fmt.Sprintf("c.created_at <= $%d", argPos))
+				}
+			}
+			args = append(args, date)
+		case "mintotal", "maxtotal":
+			total, err := strconv.Atoi(value)
+			if err != nil {
+				continue
+			}
+
+			if key == "mintotal" {
+				whereClause = append(whereClause, fmt.Sprintf("c.total >= $%d", argPos))
+			} else {
+				whereClause = append(whereClause, fmt.Sprintf("c.total <= $%d", argPos))
+			}
+			args = append(args, total)
 		}
 	}
+
 	if len(whereClause) > 0 {
-		queryCondition += "\nAND " + strings.Join(whereClause, " AND ")
+		queryCondition += "\nWHERE " + strings.Join(whereClause, " AND ")
+	}
+
+	return queryCondition, args
+}
+
+func parseHistoryDate(value string, storeLocation *time.Location) (time.Time, bool, bool) {
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, false, true
 	}
-	if len(orderClause) > 0 {
-		queryCondition += "\nORDER BY " + strings.Join(orderClause, ", ") + ", c.sid desc"
+	if date, err := time.ParseInLocation(time.DateOnly, value, storeLocation); err == nil {
+		return date, true, true
 	}
 
-	subqueryCheckout := `WITH pageCheckouts AS (
-		SELECT c.id, c.created_at, c.user_customer_id, c.paid, c.change, c.sid
+	return time.Time{}, false, false
+}
+
+func (cr *checkoutRepository) GetCheckoutByID(ctx context.Context, db *sql.DB, id string) ([]domain.GetCheckoutHistory, error) {
+	query := `
+		SELECT c.id, COALESCE(pc.id::text, ''), c.created_at, c.user_customer_id, COALESCE(c.user_admin_id::text, ''),
+			COALESCE(c.shift_id::text, ''), c.type, COALESCE(c.return_of::text, ''), COALESCE(c.return_reason, ''),
+			COALESCE(pc.product_id::text, ''), COALESCE(pc.product_name, ''), COALESCE(pc.product_sku, ''),
+			COALESCE(pc.quantity, 0), COALESCE(pc.price, 0), COALESCE(pc.restock_state, ''),
+			COALESCE(pc.discount, 0), COALESCE(pc.tax, 0), COALESCE(pc.tax_rate, 0),
+			COALESCE(pc.tax_inclusive, false), COALESCE(pc.total, 0), c.subtotal, c.discount, c.tax,
+			c.total, c.paid, c.change, c.voided_at, COALESCE(c.voided_by::text, ''),
+			COALESCE(c.void_reason, '')
 		FROM checkouts c
+		LEFT JOIN product_checkouts pc ON pc.checkout_id = c.id
+		WHERE c.id = $1
+		ORDER BY pc.sid
 	`
-	subqueryCheckout += strings.Join(limitOffsetClause, " ") + ")"
+	rows, err := db.QueryContext(ctx, query, id)
+	if err != nil {
+		if err, ok := err.(*pgconn.PgError); ok {
+			if err.Code == "22P02" {
+				return []domain.GetCheckoutHistory{}, nil
+			}
+		}
+		return nil, err
+	}
+	defer rows.Close()
+
+	checkouts := []domain.GetCheckoutHistory{}
+	for rows.Next() {
+		checkout := domain.GetCheckoutHistory{}
+
+		err := rows.Scan(
+			&checkout.TransactionID, &checkout.ProductCheckoutID, &checkout.CreatedAt, &checkout.CustomerID,
+			&checkout.CashierID, &checkout.ShiftID, &checkout.Type, &checkout.ReturnOf, &checkout.ReturnReason,
+			&checkout.ProductID, &checkout.ProductName, &checkout.ProductSku, &checkout.Quantity,
+			&checkout.Price, &checkout.RestockState, &checkout.LineDiscount, &checkout.LineTax,
+			&checkout.TaxRate, &checkout.TaxInclusive, &checkout.LineTotal, &checkout.Subtotal,
+			&checkout.Discount, &checkout.Tax, &checkout.Total, &checkout.Paid, &checkout.Change,
+			&checkout.VoidedAt, &checkout.VoidedBy, &checkout.VoidReason,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		checkouts = append(checkouts, checkout)
+	}
+
+	return checkouts, nil
+}
+
+func (cr *checkoutRepository) GetCashierSalesSummary(ctx context.Context, db *sql.DB, queryParams domain.CheckoutSummaryQueryParams, storeLocation *time.Location) ([]domain.CashierSalesSummary, error) {
+	queryCondition, args := checkoutHistoryCondition(domain.CheckoutHistoryQueryParams{
+		CashierId: queryParams.CashierId,
+		From:      queryParams.From,
+		To:        queryParams.To,
+	}, storeLocation)
+	if len(queryCondition) > 0 {
+		queryCondition += "\nAND c.user_admin_id IS NOT NULL AND c.voided_at IS NULL"
+	} else {
+		queryCondition += "\nWHERE c.user_admin_id IS NOT NULL AND c.voided_at IS NULL"
+	}
 
 	query := `
-		SELECT c.id, c.created_at, c.user_customer_id, pc.product_id, pc.quantity, c.paid, c.change
-		FROM pageCheckouts c
-		INNER JOIN product_checkouts pc ON pc.checkout_id = c.id
+		SELECT ua.id, ua.name, COUNT(c.id), COALESCE(SUM(c.total), 0),
+			COALESCE(SUM(c.paid), 0), COALESCE(SUM(c.change), 0)
+		FROM checkouts c
+		INNER JOIN user_admins ua ON ua.id = c.user_admin_id
 	`
-	query = subqueryCheckout + query
 	query += queryCondition
+	query += `
+		GROUP BY ua.id, ua.name
+		ORDER BY ua.name
+	`
 
 	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -110,19 +319,148 @@ func (cr *checkoutRepository) GetCheckoutHistory(ctx context.Context, db *sql.DB
 	}
 	defer rows.Close()
 
-	checkouts := []domain.GetCheckoutHistory{}
+	summaries := []domain.CashierSalesSummary{}
 	for rows.Next() {
-		checkout := domain.GetCheckoutHistory{}
+		summary := domain.CashierSalesSummary{}
 
-		err := rows.Scan(&checkout.TransactionID, &checkout.CreatedAt, &checkout.CustomerID, &checkout.ProductID, &checkout.Quantity, &checkout.Paid, &checkout.Change)
+		err := rows.Scan(
+			&summary.CashierID, &summary.CashierName, &summary.TransactionCount,
+			&summary.Total, &summary.Paid, &summary.Change,
+		)
 		if err != nil {
 			return nil, err
 		}
 
-		checkouts = append(checkouts, checkout)
+		summaries = append(summaries, summary)
 	}
 
-	return checkouts, nil
+	return summaries, nil
+}
+
+func (cr *checkoutRepository) GetCheckoutForUpdate(ctx context.Context, tx *sql.Tx, id string) (*domain.Checkout, error) {
+	query := `
+		SELECT id, created_at, user_customer_id, COALESCE(user_admin_id::text, ''), type, return_of,
+			return_reason, paid, change, subtotal, discount, tax, total, voided_at, voided_by, void_reason
+		FROM checkouts
+		WHERE id = $1
+		FOR UPDATE
+	`
+	checkout := domain.Checkout{}
+	err := tx.QueryRowContext(ctx, query, id).Scan(
+		&checkout.ID, &checkout.CreatedAt, &checkout.UserCustomerID, &checkout.UserAdminID,
+		&checkout.Type, &checkout.ReturnOf, &checkout.ReturnReason, &checkout.Paid,
+		&checkout.Change, &checkout.Subtotal, &checkout.Discount, &checkout.Tax, &checkout.Total,
+		&checkout.VoidedAt, &checkout.VoidedBy, &checkout.VoidReason,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		if err, ok := err.(*pgconn.PgError); ok {
+			if err.Code == "22P02" {
+				return nil, nil
+			}
+		}
+		return nil, err
+	}
+
+	return &checkout, nil
+}
+
+func (cr *checkoutRepository) GetProductCheckoutsByCheckoutID(ctx context.Context, tx *sql.Tx, checkoutID string) ([]domain.ProductCheckout, error) {
+	query := `
+		SELECT id, product_id, quantity, checkout_id, product_name, product_sku, price, discount,
+			restock_state, tax, tax_rate, tax_inclusive, total
+		FROM product_checkouts
+		WHERE checkout_id = $1
+		ORDER BY sid
+	`
+	rows, err := tx.QueryContext(ctx, query, checkoutID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	productCheckouts := []domain.ProductCheckout{}
+	for rows.Next() {
+		productCheckout := domain.ProductCheckout{}
+
+		err := rows.Scan(
+			&productCheckout.ID, &productCheckout.ProductID, &productCheckout.Quantity,
+			&productCheckout.CheckoutID, &productCheckout.ProductName, &productCheckout.ProductSku,
+			&productCheckout.Price, &productCheckout.Discount, &productCheckout.RestockState,
+			&productCheckout.Tax, &productCheckout.TaxRate, &productCheckout.TaxInclusive,
+			&productCheckout.Total,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		productCheckouts = append(productCheckouts, productCheckout)
+	}
+
+	return productCheckouts, nil
+}
+
+func (cr *checkoutRepository) GetReturnedQuantities(ctx context.Context, tx *sql.Tx, checkoutID string) (map[string]int, error) {
+	query := `
+		SELECT pc.product_id, -SUM(pc.quantity)
+		FROM checkouts c
+		INNER JOIN product_checkouts pc ON pc.checkout_id = c.id
+		WHERE c.return_of = $1
+		GROUP BY pc.product_id
+	`
+	rows, err := tx.QueryContext(ctx, query, checkoutID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	returnedQuantities := map[string]int{}
+	for rows.Next() {
+		var productID string
+		var quantity int
+
+		err := rows.Scan(&productID, &quantity)
+		if err != nil {
+			return nil, err
+		}
+
+		returnedQuantities[productID] = quantity
+	}
+
+	return returnedQuantities, nil
+}
+
+func (cr *checkoutRepository) GetReturnedTotal(ctx context.Context, tx *sql.Tx, checkoutID string) (int, error) {
+	query := `
+		SELECT COALESCE(-SUM(total), 0)
+		FROM checkouts
+		WHERE return_of = $1
+	`
+	var total int
+	err := tx.QueryRowContext(ctx, query, checkoutID).Scan(&total)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func (cr *checkoutRepository) VoidCheckoutByID(ctx context.Context, tx *sql.Tx, checkout domain.Checkout) error {
+	query := `
+		UPDATE checkouts
+		SET voided_at = $2,
+			voided_by = $3,
+			void_reason = $4
+		WHERE id = $1
+	`
+	_, err := tx.ExecContext(ctx, query, checkout.ID, checkout.VoidedAt, checkout.VoidedBy, checkout.VoidReason)
+	if err != nil {
+		return err
+	}
+
+	return nil
 }
 
 func (cr *checkoutRepository) BulkCreateProductCheckout(ctx context.Context, tx *sql.Tx, productCheckouts []domain.ProductCheckout) error {
@@ -140,7 +478,8 @@ func (cr *checkoutRepository) BulkCreateProductCheckout(ctx context.Context, tx
 			value := val.Field(i).Interface()
 			argsPos := len(args) + 1
 
-			if key == "sid" {
+			// applied promotions are stored in their own table
+			if key == "sid" || key == "promotions" {
 				continue
 			}
 
@@ -151,7 +490,8 @@ func (cr *checkoutRepository) BulkCreateProductCheckout(ctx context.Context, tx
 		inserts = append(inserts, placeholder)
 	}
 	query = `
-		INSERT INTO product_checkouts (id, product_id, quantity, checkout_id)
+		INSERT INTO product_checkouts (id, product_id, quantity, checkout_id, product_name, product_sku, price, discount,
+			restock_state, tax, tax_rate, tax_inclusive, total)
 		VALUES `
 	query += strings.Join(inserts, ", ")
 
@@ -165,10 +505,16 @@ func (cr *checkoutRepository) BulkCreateProductCheckout(ctx context.Context, tx
 
 func (cr *checkoutRepository) CreateProductCheckout(ctx context.Context, tx *sql.Tx, productCheckout domain.ProductCheckout) error {
 	query := `
-		INSERT INTO product_checkouts (id, product_id, quantity, checkout_id)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO product_checkouts (id, product_id, quantity, checkout_id, product_name, product_sku, price, discount,
+			restock_state, tax, tax_rate, tax_inclusive, total)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 	`
-	_, err := tx.ExecContext(ctx, query, productCheckout.ID, productCheckout.ProductID, productCheckout.Quantity, productCheckout.CheckoutID)
+	_, err := tx.ExecContext(ctx, query,
+		productCheckout.ID, productCheckout.ProductID, productCheckout.Quantity, productCheckout.CheckoutID,
+		productCheckout.ProductName, productCheckout.ProductSku, productCheckout.Price, productCheckout.Discount,
+		productCheckout.RestockState,
+		productCheckout.Tax, productCheckout.TaxRate, productCheckout.TaxInclusive, productCheckout.Total,
+	)
 	if err != nil {
 		return err
 	}
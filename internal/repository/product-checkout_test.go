@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"eniqilo-store/internal/domain"
+	"testing"
+	"time"
+)
+
+func TestCheckoutHistoryConditionDates(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		queryParams   domain.CheckoutHistoryQueryParams
+		wantCondition string
+		wantArgs      []time.Time
+	}{
+		{
+			name:          "date only from starts at the store's midnight",
+			queryParams:   domain.CheckoutHistoryQueryParams{From: "2024-05-01"},
+			wantCondition: "\nWHERE c.created_at >= $1",
+			wantArgs:      []time.Time{time.Date(2024, 4, 30, 17, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:          "date only to ends before the next day",
+			queryParams:   domain.CheckoutHistoryQueryParams{To: "2024-05-01"},
+			wantCondition: "\nWHERE c.created_at < $1",
+			wantArgs:      []time.Time{time.Date(2024, 5, 1, 17, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:          "timestamps are kept as sent",
+			queryParams:   domain.CheckoutHistoryQueryParams{From: "2024-05-01T10:00:00Z", To: "2024-05-01T12:00:00Z"},
+			wantCondition: "\nWHERE c.created_at >= $1 AND c.created_at <= $2",
+			wantArgs:      []time.Time{time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:          "invalid dates are ignored",
+			queryParams:   domain.CheckoutHistoryQueryParams{From: "yesterday", To: "2024-13-01"},
+			wantCondition: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, args := checkoutHistoryCondition(tt.queryParams, jakarta)
+			if condition != tt.wantCondition {
+				t.Errorf("condition = %q, want %q", condition, tt.wantCondition)
+			}
+			if len(args) != len(tt.wantArgs) {
+				t.Fatalf("args = %v, want %v", args, tt.wantArgs)
+			}
+			for i, arg := range args {
+				if !arg.(time.Time).Equal(tt.wantArgs[i]) {
+					t.Errorf("args[%d] = %v, want %v", i, arg, tt.wantArgs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckoutHistoryConditionFilters(t *testing.T) {
+	customerID := "6f1c1c1e-2f6b-4a57-9d0e-3f4b8f3c1a10"
+	cashierID := "0b8e4f0e-6a52-4c8e-b0a5-1d0f1c2e3a4b"
+
+	tests := []struct {
+		name          string
+		queryParams   domain.CheckoutHistoryQueryParams
+		wantCondition string
+		wantArgs      []any
+	}{
+		{
+			name:          "customer",
+			queryParams:   domain.CheckoutHistoryQueryParams{CustomerId: customerID},
+			wantCondition: "\nWHERE c.user_customer_id = $1",
+			wantArgs:      []any{customerID},
+		},
+		{
+			name:          "customer and cashier",
+			queryParams:   domain.CheckoutHistoryQueryParams{CustomerId: customerID, CashierId: cashierID},
+			wantCondition: "\nWHERE c.user_customer_id = $1 AND c.user_admin_id = $2",
+			wantArgs:      []any{customerID, cashierID},
+		},
+		{
+			name:          "malformed ids are ignored",
+			queryParams:   domain.CheckoutHistoryQueryParams{CustomerId: "123", CashierId: "abc"},
+			wantCondition: "",
+		},
+		{
+			name:          "type and total range",
+			queryParams:   domain.CheckoutHistoryQueryParams{Type: domain.CheckoutTypeReturn, MinTotal: "-5000", MaxTotal: "0"},
+			wantCondition: "\nWHERE c.type = $1 AND c.total >= $2 AND c.total <= $3",
+			wantArgs:      []any{domain.CheckoutTypeReturn, -5000, 0},
+		},
+		{
+			name:          "unknown type is ignored",
+			queryParams:   domain.CheckoutHistoryQueryParams{Type: "refund"},
+			wantCondition: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, args := checkoutHistoryCondition(tt.queryParams, time.UTC)
+			if condition != tt.wantCondition {
+				t.Errorf("condition = %q, want %q", condition, tt.wantCondition)
+			}
+			if len(args) != len(tt.wantArgs) {
+				t.Fatalf("args = %v, want %v", args, tt.wantArgs)
+			}
+			for i := range args {
+				if args[i] != tt.wantArgs[i] {
+					t.Errorf("args[%d] = %v, want %v", i, args[i], tt.wantArgs[i])
+				}
+			}
+		})
+	}
+}
@@ -16,15 +16,17 @@ type ProductRepository interface {
 	CreateProduct(ctx context.Context, db *sql.DB, product domain.Product) error
 	GetProducts(ctx context.Context, db *sql.DB, queryParams string, args []any) ([]domain.ProductResponse, error)
 	GetProductsForCustomer(ctx context.Context, db *sql.DB, queryParams domain.ProductForCustomerQueryParams) ([]domain.ProductForCustomerResponse, error)
-	GetProductStockByIDs(ctx context.Context, db *sql.DB, productIds []string) ([]domain.ProductResponse, error)
-	GetProductPriceByIDs(ctx context.Context, db *sql.DB, productIds []string) ([]domain.ProductResponse, error)
+	GetProductStockByIDs(ctx context.Context, tx *sql.Tx, productIds []string) ([]domain.ProductResponse, error)
+	GetProductStockByIDsForUpdate(ctx context.Context, tx *sql.Tx, productIds []string) ([]domain.ProductResponse, error)
+	GetProductPriceByIDs(ctx context.Context, tx *sql.Tx, productIds []string) ([]domain.ProductResponse, error)
 	UpdateProductByID(ctx context.Context, db *sql.DB, product domain.Product) (int64, error)
 	DeleteProductByID(ctx context.Context, db *sql.DB, productId string) (int64, error)
 	CheckProductExistsByID(ctx context.Context, db *sql.DB, productId string) (bool, error)
-	CheckProductExistsByIDs(ctx context.Context, db *sql.DB, IDs []string) (bool, error)
-	CheckProductAvailabilities(ctx context.Context, db *sql.DB, productIDs []string) (bool, error)
+	CheckProductExistsByIDs(ctx context.Context, tx *sql.Tx, IDs []string) (bool, error)
+	CheckProductAvailabilities(ctx context.Context, tx *sql.Tx, productIDs []string) (bool, error)
 	CheckProductPrice(ctx context.Context, db *sql.DB, productCheckouts []domain.ProductCheckoutRequest) (int, error)
-	UpdateProductStockByID(ctx context.Context, tx *sql.Tx, product string, quantity int) error
+	UpdateProductStockByID(ctx context.Context, tx *sql.Tx, product string, quantity int) (int64, error)
+	RestockProductByID(ctx context.Context, tx *sql.Tx, id string, quantity int, restockState string) error
 }
 
 type productRepository struct{}
@@ -52,7 +54,7 @@ func (pr *productRepository) CreateProduct(ctx context.Context, db *sql.DB, prod
 
 func (pr *productRepository) GetProducts(ctx context.Context, db *sql.DB, queryParams string, args []any) ([]domain.ProductResponse, error) {
 	query := `
-		SELECT id, created_at, name, sku, category, image_url, stock, notes, 
+		SELECT id, created_at, name, sku, category, image_url, stock, damaged_stock, notes, 
 				price, location, is_available
 		FROM products
 	`
@@ -70,8 +72,8 @@ func (pr *productRepository) GetProducts(ctx context.Context, db *sql.DB, queryP
 
 		err := rows.Scan(
 			&product.ID, &product.CreatedAt, &product.Name, &product.Sku,
-			&product.Category, &product.ImageUrl, &product.Stock, &product.Notes,
-			&product.Price, &product.Location, &product.IsAvailable,
+			&product.Category, &product.ImageUrl, &product.Stock, &product.DamagedStock,
+			&product.Notes, &product.Price, &product.Location, &product.IsAvailable,
 		)
 		if err != nil {
 			return nil, err
@@ -199,17 +201,40 @@ func (pr *productRepository) GetProductsForCustomer(ctx context.Context, db *sql
 	return products, nil
 }
 
-func (pr *productRepository) GetProductStockByIDs(ctx context.Context, db *sql.DB, productIds []string) ([]domain.ProductResponse, error) {
+func (pr *productRepository) GetProductStockByIDs(ctx context.Context, tx *sql.Tx, productIds []string) ([]domain.ProductResponse, error) {
+	query := `
+		SELECT id, name, stock
+		FROM products
+		WHERE id = any ($1)
+	`
+	rows, err := tx.QueryContext(ctx, query, productIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanProductsStock(rows)
+}
+
+// rows are locked in id order so concurrent checkouts can't deadlock
+func (pr *productRepository) GetProductStockByIDsForUpdate(ctx context.Context, tx *sql.Tx, productIds []string) ([]domain.ProductResponse, error) {
 	query := `
 		SELECT id, name, stock
 		FROM products
 		WHERE id = any ($1)
+		ORDER BY id
+		FOR UPDATE
 	`
-	rows, err := db.QueryContext(ctx, query, productIds)
+	rows, err := tx.QueryContext(ctx, query, productIds)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
+	return scanProductsStock(rows)
+}
+
+func scanProductsStock(rows *sql.Rows) ([]domain.ProductResponse, error) {
 	productsStock := []domain.ProductResponse{}
 	for rows.Next() {
 		productStock := domain.ProductResponse{}
@@ -225,22 +250,26 @@ func (pr *productRepository) GetProductStockByIDs(ctx context.Context, db *sql.D
 	return productsStock, nil
 }
 
-func (pr *productRepository) GetProductPriceByIDs(ctx context.Context, db *sql.DB, productIds []string) ([]domain.ProductResponse, error) {
+func (pr *productRepository) GetProductPriceByIDs(ctx context.Context, tx *sql.Tx, productIds []string) ([]domain.ProductResponse, error) {
 	query := `
-		SELECT id, price
+		SELECT id, name, sku, category, price
 		FROM products
 		WHERE id = any ($1)
 	`
-	rows, err := db.QueryContext(ctx, query, productIds)
+	rows, err := tx.QueryContext(ctx, query, productIds)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	productPrices := []domain.ProductResponse{}
 	for rows.Next() {
 		productPrice := domain.ProductResponse{}
 
-		err := rows.Scan(&productPrice.ID, &productPrice.Price)
+		err := rows.Scan(
+			&productPrice.ID, &productPrice.Name, &productPrice.Sku,
+			&productPrice.Category, &productPrice.Price,
+		)
 		if err != nil {
 			return nil, err
 		}
@@ -331,14 +360,14 @@ func (pr *productRepository) DeleteProductByID(ctx context.Context, db *sql.DB,
 	return affRow, nil
 }
 
-func (pr *productRepository) CheckProductExistsByIDs(ctx context.Context, db *sql.DB, IDs []string) (bool, error) {
+func (pr *productRepository) CheckProductExistsByIDs(ctx context.Context, tx *sql.Tx, IDs []string) (bool, error) {
 	query := `
 		SELECT COUNT(id) = $1
 		FROM products
 		WHERE id = any ($2)
 	`
 	var exists bool
-	err := db.QueryRowContext(ctx, query, len(IDs), IDs).Scan(&exists)
+	err := tx.QueryRowContext(ctx, query, len(IDs), IDs).Scan(&exists)
 	if err != nil {
 		if err, ok := err.(*pgconn.PgError); ok {
 			if err.Code == "22P02" {
@@ -366,7 +395,7 @@ func (cr *productRepository) CheckProductPrice(ctx context.Context, db *sql.DB,
 	return totalPrice, nil
 }
 
-func (pr *productRepository) CheckProductAvailabilities(ctx context.Context, db *sql.DB, productIDs []string) (bool, error) {
+func (pr *productRepository) CheckProductAvailabilities(ctx context.Context, tx *sql.Tx, productIDs []string) (bool, error) {
 	query := `
 		SELECT COUNT(id) = $1
 		FROM products
@@ -374,7 +403,7 @@ func (pr *productRepository) CheckProductAvailabilities(ctx context.Context, db
 			AND is_available = true
 	`
 	var exists bool
-	err := db.QueryRowContext(ctx, query, len(productIDs), productIDs).Scan(&exists)
+	err := tx.QueryRowContext(ctx, query, len(productIDs), productIDs).Scan(&exists)
 	if err != nil {
 		return false, err
 	}
@@ -382,12 +411,41 @@ func (pr *productRepository) CheckProductAvailabilities(ctx context.Context, db
 	return exists, nil
 }
 
-func (pr *productRepository) UpdateProductStockByID(ctx context.Context, tx *sql.Tx, id string, quantity int) error {
+// zero affected rows means the product ran out before the update
+func (pr *productRepository) UpdateProductStockByID(ctx context.Context, tx *sql.Tx, id string, quantity int) (int64, error) {
 	query := `
 		UPDATE products
 		SET stock = stock - $2
 		WHERE id = $1
+			AND stock >= $2
 	`
+	res, err := tx.ExecContext(ctx, query, id, quantity)
+	if err != nil {
+		return 0, err
+	}
+
+	affRow, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return affRow, nil
+}
+
+func (pr *productRepository) RestockProductByID(ctx context.Context, tx *sql.Tx, id string, quantity int, restockState string) error {
+	query := `
+		UPDATE products
+		SET stock = stock + $2
+		WHERE id = $1
+	`
+	if restockState == domain.RestockStateDamaged {
+		query = `
+			UPDATE products
+			SET damaged_stock = damaged_stock + $2
+			WHERE id = $1
+		`
+	}
+
 	_, err := tx.ExecContext(ctx, query, id, quantity)
 	if err != nil {
 		return err
@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"eniqilo-store/internal/domain"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type PromotionRepository interface {
+	CreatePromotion(ctx context.Context, db *sql.DB, promotion domain.Promotion) error
+	GetPromotions(ctx context.Context, db *sql.DB) ([]domain.Promotion, error)
+	GetActivePromotions(ctx context.Context, tx *sql.Tx) ([]domain.Promotion, error)
+	UpdatePromotionByID(ctx context.Context, db *sql.DB, promotion domain.Promotion) (int64, error)
+	DeletePromotionByID(ctx context.Context, db *sql.DB, id string) (int64, error)
+	BulkCreateAppliedPromotion(ctx context.Context, tx *sql.Tx, appliedPromotions []domain.AppliedPromotion) error
+	GetAppliedPromotionsByProductCheckoutIDs(ctx context.Context, db *sql.DB, productCheckoutIDs []string) (map[string][]domain.AppliedPromotion, error)
+	GetPromotionReport(ctx context.Context, db *sql.DB, queryParams domain.PromotionReportQueryParams, storeLocation *time.Location) ([]domain.PromotionReport, error)
+}
+
+type promotionRepository struct{}
+
+func NewPromotionRepository() PromotionRepository {
+	return &promotionRepository{}
+}
+
+func (pr *promotionRepository) CreatePromotion(ctx context.Context, db *sql.DB, promotion domain.Promotion) error {
+	query := `
+		INSERT INTO promotions (id, created_at, name, scope, product_id, category, type, value, buy_quantity,
+			get_quantity, starts_at, ends_at, start_hour, end_hour, priority, stackable, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+	`
+	_, err := db.ExecContext(ctx, query,
+		promotion.ID, promotion.CreatedAt, promotion.Name, promotion.Scope, promotion.ProductID,
+		promotion.Category, promotion.Type, promotion.Value, promotion.BuyQuantity,
+		promotion.GetQuantity, promotion.StartsAt, promotion.EndsAt, promotion.StartHour,
+		promotion.EndHour, promotion.Priority, promotion.Stackable, promotion.IsActive,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (pr *promotionRepository) GetPromotions(ctx context.Context, db *sql.DB) ([]domain.Promotion, error) {
+	query := `
+		SELECT id, created_at, name, scope, product_id, category, type, value, buy_quantity,
+			get_quantity, starts_at, ends_at, start_hour, end_hour, priority, stackable, is_active
+		FROM promotions
+		ORDER BY created_at desc, sid desc
+	`
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPromotions(rows)
+}
+
+func (pr *promotionRepository) GetActivePromotions(ctx context.Context, tx *sql.Tx) ([]domain.Promotion, error) {
+	query := `
+		SELECT id, created_at, name, scope, product_id, category, type, value, buy_quantity,
+			get_quantity, starts_at, ends_at, start_hour, end_hour, priority, stackable, is_active
+		FROM promotions
+		WHERE is_active = true
+		ORDER BY priority desc, sid
+	`
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPromotions(rows)
+}
+
+func (pr *promotionRepository) UpdatePromotionByID(ctx context.Context, db *sql.DB, promotion domain.Promotion) (int64, error) {
+	query := `
+		UPDATE promotions
+		SET name = $2,
+			scope = $3,
+			product_id = $4,
+			category = $5,
+			type = $6,
+			value = $7,
+			buy_quantity = $8,
+			get_quantity = $9,
+			starts_at = $10,
+			ends_at = $11,
+			start_hour = $12,
+			end_hour = $13,
+			priority = $14,
+			stackable = $15,
+			is_active = $16
+		WHERE id = $1
+	`
+	res, err := db.ExecContext(ctx, query,
+		promotion.ID, promotion.Name, promotion.Scope, promotion.ProductID, promotion.Category,
+		promotion.Type, promotion.Value, promotion.BuyQuantity, promotion.GetQuantity,
+		promotion.StartsAt, promotion.EndsAt, promotion.StartHour, promotion.EndHour,
+		promotion.Priority, promotion.Stackable, promotion.IsActive,
+	)
+	if err != nil {
+		if err, ok := err.(*pgconn.PgError); ok {
+			if err.Code == "22P02" {
+				return 0, nil
+			}
+		}
+		return 0, err
+	}
+
+	affRow, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return affRow, nil
+}
+
+func (pr *promotionRepository) DeletePromotionByID(ctx context.Context, db *sql.DB, id string) (int64, error) {
+	query := `
+		DELETE FROM promotions
+		WHERE id = $1
+	`
+	res, err := db.ExecContext(ctx, query, id)
+	if err != nil {
+		if err, ok := err.(*pgconn.PgError); ok {
+			if err.Code == "22P02" {
+				return 0, nil
+			}
+		}
+		return 0, err
+	}
+
+	affRow, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return affRow, nil
+}
+
+func (pr *promotionRepository) BulkCreateAppliedPromotion(ctx context.Context, tx *sql.Tx, appliedPromotions []domain.AppliedPromotion) error {
+	if len(appliedPromotions) == 0 {
+		return nil
+	}
+
+	inserts := []string{}
+	args := []any{}
+
+	for _, ap := range appliedPromotions {
+		argsPos := len(args) + 1
+		inserts = append(inserts, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d)", argsPos, argsPos+1, argsPos+2, argsPos+3, argsPos+4, argsPos+5))
+		args = append(args, ap.ID, ap.ProductCheckoutID, ap.PromotionID, ap.CouponID, ap.PromotionName, ap.Discount)
+	}
+
+	query := `
+		INSERT INTO product_checkout_promotions (id, product_checkout_id, promotion_id, coupon_id, promotion_name, discount)
+		VALUES `
+	query += strings.Join(inserts, ", ")
+
+	_, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (pr *promotionRepository) GetAppliedPromotionsByProductCheckoutIDs(ctx context.Context, db *sql.DB, productCheckoutIDs []string) (map[string][]domain.AppliedPromotion, error) {
+	query := `
+		SELECT id, product_checkout_id, promotion_id, coupon_id, promotion_name, discount
+		FROM product_checkout_promotions
+		WHERE product_checkout_id = any ($1)
+		ORDER BY sid
+	`
+	rows, err := db.QueryContext(ctx, query, productCheckoutIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	appliedPromotions := map[string][]domain.AppliedPromotion{}
+	for rows.Next() {
+		appliedPromotion := domain.AppliedPromotion{}
+
+		err := rows.Scan(
+			&appliedPromotion.ID, &appliedPromotion.ProductCheckoutID, &appliedPromotion.PromotionID,
+			&appliedPromotion.CouponID, &appliedPromotion.PromotionName, &appliedPromotion.Discount,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		appliedPromotions[appliedPromotion.ProductCheckoutID] = append(appliedPromotions[appliedPromotion.ProductCheckoutID], appliedPromotion)
+	}
+
+	return appliedPromotions, nil
+}
+
+func (pr *promotionRepository) GetPromotionReport(ctx context.Context, db *sql.DB, queryParams domain.PromotionReportQueryParams, storeLocation *time.Location) ([]domain.PromotionReport, error) {
+	queryCondition, args := checkoutHistoryCondition(domain.CheckoutHistoryQueryParams{
+		Type: domain.CheckoutTypeSale,
+		From: queryParams.From,
+		To:   queryParams.To,
+	}, storeLocation)
+	queryCondition += "\nAND c.voided_at IS NULL AND pcp.coupon_id IS NULL"
+
+	query := `
+		SELECT COALESCE(pcp.promotion_id::text, ''), pcp.promotion_name, COUNT(DISTINCT c.id),
+			SUM(pc.quantity), SUM(pcp.discount)
+		FROM product_checkout_promotions pcp
+		INNER JOIN product_checkouts pc ON pc.id = pcp.product_checkout_id
+		INNER JOIN checkouts c ON c.id = pc.checkout_id
+	`
+	query += queryCondition
+	query += `
+		GROUP BY pcp.promotion_id, pcp.promotion_name
+		ORDER BY SUM(pcp.discount) desc
+	`
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := []domain.PromotionReport{}
+	for rows.Next() {
+		report := domain.PromotionReport{}
+
+		err := rows.Scan(
+			&report.PromotionID, &report.Name, &report.CheckoutCount,
+			&report.Quantity, &report.Discount,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+func scanPromotions(rows *sql.Rows) ([]domain.Promotion, error) {
+	promotions := []domain.Promotion{}
+	for rows.Next() {
+		promotion := domain.Promotion{}
+
+		err := rows.Scan(
+			&promotion.ID, &promotion.CreatedAt, &promotion.Name, &promotion.Scope,
+			&promotion.ProductID, &promotion.Category, &promotion.Type, &promotion.Value,
+			&promotion.BuyQuantity, &promotion.GetQuantity, &promotion.StartsAt, &promotion.EndsAt,
+			&promotion.StartHour, &promotion.EndHour, &promotion.Priority, &promotion.Stackable,
+			&promotion.IsActive,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		promotions = append(promotions, promotion)
+	}
+
+	return promotions, nil
+}
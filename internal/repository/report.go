@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"eniqilo-store/internal/domain"
+	"time"
+)
+
+type ReportRepository interface {
+	GetZReportTotals(ctx context.Context, tx *sql.Tx, from time.Time, to time.Time) (domain.ZReportTotals, error)
+	GetClosedZReport(ctx context.Context, db *sql.DB, date string) (*domain.ZReport, error)
+	CreateClosedZReport(ctx context.Context, tx *sql.Tx, report domain.ZReport) error
+}
+
+type reportRepository struct{}
+
+func NewReportRepository() ReportRepository {
+	return &reportRepository{}
+}
+
+func (rr *reportRepository) GetZReportTotals(ctx context.Context, tx *sql.Tx, from time.Time, to time.Time) (domain.ZReportTotals, error) {
+	totals := domain.ZReportTotals{}
+
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE type = 'sale'),
+			COUNT(*) FILTER (WHERE type = 'return'),
+			COALESCE(SUM(subtotal) FILTER (WHERE type = 'sale'), 0),
+			COALESCE(SUM(discount) FILTER (WHERE type = 'sale'), 0),
+			COALESCE(SUM(tax) FILTER (WHERE type = 'sale'), 0),
+			COALESCE(SUM(total) FILTER (WHERE type = 'sale'), 0),
+			COALESCE(SUM(tax) FILTER (WHERE type = 'return'), 0),
+			COALESCE(SUM(total) FILTER (WHERE type = 'return'), 0),
+			COALESCE(SUM(total) FILTER (WHERE type = 'gift_card'), 0),
+			COALESCE(SUM(change), 0)
+		FROM checkouts
+		WHERE created_at >= $1 AND created_at < $2 AND voided_at IS NULL
+	`
+	err := tx.QueryRowContext(ctx, query, from, to).Scan(
+		&totals.SaleCount, &totals.ReturnCount, &totals.GrossSales, &totals.Discounts,
+		&totals.SalesTax, &totals.SalesTotal, &totals.ReturnsTax, &totals.ReturnsTotal,
+		&totals.GiftCardSales, &totals.Change,
+	)
+	if err != nil {
+		return totals, err
+	}
+
+	query = `
+		SELECT p.method, SUM(p.amount)
+		FROM payments p
+		INNER JOIN checkouts c ON c.id = p.checkout_id
+		WHERE c.created_at >= $1 AND c.created_at < $2 AND c.voided_at IS NULL
+		GROUP BY p.method
+	`
+	rows, err := tx.QueryContext(ctx, query, from, to)
+	if err != nil {
+		return totals, err
+	}
+	totals.Payments, err = scanTenderAmounts(rows)
+	if err != nil {
+		return totals, err
+	}
+
+	// returns count against the products they take back
+	query = `
+		SELECT pc.product_id, MAX(pc.product_name), MAX(pc.product_sku), SUM(pc.quantity), SUM(pc.total)
+		FROM product_checkouts pc
+		INNER JOIN checkouts c ON c.id = pc.checkout_id
+		WHERE c.created_at >= $1 AND c.created_at < $2 AND c.voided_at IS NULL
+		GROUP BY pc.product_id
+		HAVING SUM(pc.quantity) > 0
+		ORDER BY SUM(pc.quantity) desc, SUM(pc.total) desc, pc.product_id
+		LIMIT $3
+	`
+	rows, err = tx.QueryContext(ctx, query, from, to, domain.ZReportTopProducts)
+	if err != nil {
+		return totals, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		product := domain.ZReportProduct{}
+
+		err := rows.Scan(&product.ProductID, &product.Name, &product.Sku, &product.Quantity, &product.Total)
+		if err != nil {
+			return totals, err
+		}
+
+		totals.TopProducts = append(totals.TopProducts, product)
+	}
+
+	return totals, rows.Err()
+}
+
+func (rr *reportRepository) GetClosedZReport(ctx context.Context, db *sql.DB, date string) (*domain.ZReport, error) {
+	query := `
+		SELECT report
+		FROM z_reports
+		WHERE date = $1
+	`
+	var rawReport []byte
+	err := db.QueryRowContext(ctx, query, date).Scan(&rawReport)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	report := domain.ZReport{}
+	err = json.Unmarshal(rawReport, &report)
+	if err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+func (rr *reportRepository) CreateClosedZReport(ctx context.Context, tx *sql.Tx, report domain.ZReport) error {
+	rawReport, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO z_reports (date, closed_at, user_admin_id, report)
+		VALUES ($1, $2, $3, $4)
+	`
+	_, err = tx.ExecContext(ctx, query, report.Date, report.ClosedAt, report.ClosedBy, rawReport)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"eniqilo-store/internal/domain"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type ShiftRepository interface {
+	CreateShift(ctx context.Context, db *sql.DB, shift domain.Shift) error
+	GetShiftByID(ctx context.Context, db *sql.DB, id string) (*domain.Shift, error)
+	GetShiftByIDForShare(ctx context.Context, tx *sql.Tx, id string) (*domain.Shift, error)
+	GetOpenShift(ctx context.Context, db *sql.DB, userAdminID string) (*domain.Shift, error)
+	GetOpenShiftForShare(ctx context.Context, tx *sql.Tx, userAdminID string) (*domain.Shift, error)
+	GetOpenShiftForUpdate(ctx context.Context, tx *sql.Tx, userAdminID string) (*domain.Shift, error)
+	CloseShift(ctx context.Context, tx *sql.Tx, shift domain.Shift) error
+	CreateCashPayout(ctx context.Context, tx *sql.Tx, payout domain.CashPayout) error
+	GetShiftTotals(ctx context.Context, tx *sql.Tx, shiftID string) (domain.ShiftTotals, error)
+}
+
+type shiftRepository struct{}
+
+func NewShiftRepository() ShiftRepository {
+	return &shiftRepository{}
+}
+
+const shiftColumns = `id, user_admin_id, till, opening_float, opened_at, closed_at, expected_cash, counted_cash,
+			denominations, tenders, notes`
+
+func (sr *shiftRepository) CreateShift(ctx context.Context, db *sql.DB, shift domain.Shift) error {
+	query := `
+		INSERT INTO shifts (id, user_admin_id, till, opening_float, opened_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := db.ExecContext(ctx, query, shift.ID, shift.UserAdminID, shift.Till, shift.OpeningFloat, shift.OpenedAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (sr *shiftRepository) GetShiftByID(ctx context.Context, db *sql.DB, id string) (*domain.Shift, error) {
+	query := `
+		SELECT ` + shiftColumns + `
+		FROM shifts
+		WHERE id = $1
+	`
+	return scanShift(db.QueryRowContext(ctx, query, id))
+}
+
+func (sr *shiftRepository) GetShiftByIDForShare(ctx context.Context, tx *sql.Tx, id string) (*domain.Shift, error) {
+	query := `
+		SELECT ` + shiftColumns + `
+		FROM shifts
+		WHERE id = $1
+		FOR SHARE
+	`
+	return scanShift(tx.QueryRowContext(ctx, query, id))
+}
+
+func (sr *shiftRepository) GetOpenShift(ctx context.Context, db *sql.DB, userAdminID string) (*domain.Shift, error) {
+	query := `
+		SELECT ` + shiftColumns + `
+		FROM shifts
+		WHERE user_admin_id = $1 AND closed_at IS NULL
+	`
+	return scanShift(db.QueryRowContext(ctx, query, userAdminID))
+}
+
+// keeps the shift from being closed until tx ends
+func (sr *shiftRepository) GetOpenShiftForShare(ctx context.Context, tx *sql.Tx, userAdminID string) (*domain.Shift, error) {
+	query := `
+		SELECT ` + shiftColumns + `
+		FROM shifts
+		WHERE user_admin_id = $1 AND closed_at IS NULL
+		FOR SHARE
+	`
+	return scanShift(tx.QueryRowContext(ctx, query, userAdminID))
+}
+
+func (sr *shiftRepository) GetOpenShiftForUpdate(ctx context.Context, tx *sql.Tx, userAdminID string) (*domain.Shift, error) {
+	query := `
+		SELECT ` + shiftColumns + `
+		FROM shifts
+		WHERE user_admin_id = $1 AND closed_at IS NULL
+		FOR UPDATE
+	`
+	return scanShift(tx.QueryRowContext(ctx, query, userAdminID))
+}
+
+func (sr *shiftRepository) CloseShift(ctx context.Context, tx *sql.Tx, shift domain.Shift) error {
+	denominations, err := json.Marshal(shift.Denominations)
+	if err != nil {
+		return err
+	}
+	tenders, err := json.Marshal(shift.Tenders)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE shifts
+		SET closed_at = $2,
+			expected_cash = $3,
+			counted_cash = $4,
+			denominations = $5,
+			tenders = $6,
+			notes = $7
+		WHERE id = $1
+	`
+	_, err = tx.ExecContext(ctx, query,
+		shift.ID, shift.ClosedAt, shift.ExpectedCash, shift.CountedCash, denominations, tenders, shift.Notes,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (sr *shiftRepository) CreateCashPayout(ctx context.Context, tx *sql.Tx, payout domain.CashPayout) error {
+	query := `
+		INSERT INTO cash_payouts (id, created_at, shift_id, user_admin_id, amount, reason)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := tx.ExecContext(ctx, query, payout.ID, payout.CreatedAt, payout.ShiftID, payout.UserAdminID, payout.Amount, payout.Reason)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (sr *shiftRepository) GetShiftTotals(ctx context.Context, tx *sql.Tx, shiftID string) (domain.ShiftTotals, error) {
+	totals := domain.ShiftTotals{}
+
+	query := `
+		SELECT COUNT(id), COALESCE(SUM(change), 0)
+		FROM checkouts
+		WHERE shift_id = $1 AND voided_at IS NULL
+	`
+	err := tx.QueryRowContext(ctx, query, shiftID).Scan(&totals.TransactionCount, &totals.Change)
+	if err != nil {
+		return totals, err
+	}
+
+	query = `
+		SELECT COALESCE(SUM(amount), 0)
+		FROM cash_payouts
+		WHERE shift_id = $1
+	`
+	err = tx.QueryRowContext(ctx, query, shiftID).Scan(&totals.Payouts)
+	if err != nil {
+		return totals, err
+	}
+
+	query = `
+		SELECT p.method, SUM(p.amount)
+		FROM payments p
+		INNER JOIN checkouts c ON c.id = p.checkout_id
+		WHERE c.shift_id = $1 AND c.voided_at IS NULL
+		GROUP BY p.method
+	`
+	rows, err := tx.QueryContext(ctx, query, shiftID)
+	if err != nil {
+		return totals, err
+	}
+	totals.Payments, err = scanTenderAmounts(rows)
+	if err != nil {
+		return totals, err
+	}
+
+	query = `
+		SELECT method, -SUM(amount)
+		FROM credit_entries
+		WHERE shift_id = $1 AND type = $2
+		GROUP BY method
+	`
+	rows, err = tx.QueryContext(ctx, query, shiftID, domain.CreditEntryPayment)
+	if err != nil {
+		return totals, err
+	}
+	totals.Collections, err = scanTenderAmounts(rows)
+	if err != nil {
+		return totals, err
+	}
+
+	return totals, nil
+}
+
+func scanTenderAmounts(rows *sql.Rows) (map[string]int, error) {
+	defer rows.Close()
+
+	amounts := map[string]int{}
+	for rows.Next() {
+		var method string
+		var amount int
+
+		err := rows.Scan(&method, &amount)
+		if err != nil {
+			return nil, err
+		}
+
+		amounts[method] = amount
+	}
+
+	return amounts, nil
+}
+
+func scanShift(row *sql.Row) (*domain.Shift, error) {
+	shift := domain.Shift{}
+	var denominations, tenders []byte
+	err := row.Scan(
+		&shift.ID, &shift.UserAdminID, &shift.Till, &shift.OpeningFloat, &shift.OpenedAt,
+		&shift.ClosedAt, &shift.ExpectedCash, &shift.CountedCash, &denominations, &tenders,
+		&shift.Notes,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		if err, ok := err.(*pgconn.PgError); ok {
+			if err.Code == "22P02" {
+				return nil, nil
+			}
+		}
+		return nil, err
+	}
+
+	if len(denominations) > 0 {
+		err = json.Unmarshal(denominations, &shift.Denominations)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(tenders) > 0 {
+		err = json.Unmarshal(tenders, &shift.Tenders)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &shift, nil
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"eniqilo-store/internal/domain"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type StaffInvitationRepository interface {
+	CreateStaffInvitation(ctx context.Context, db *sql.DB, invitation domain.StaffInvitation) error
+	GetPendingStaffInvitations(ctx context.Context, db *sql.DB, now time.Time) ([]domain.StaffInvitation, error)
+	RevokeStaffInvitation(ctx context.Context, db *sql.DB, id string, revokedAt time.Time) (int64, error)
+	GetStaffInvitationByTokenHashForUpdate(ctx context.Context, tx *sql.Tx, tokenHash string) (*domain.StaffInvitation, error)
+	UseStaffInvitation(ctx context.Context, tx *sql.Tx, id string, userAdminID string, usedAt time.Time) error
+}
+
+type staffInvitationRepository struct{}
+
+func NewStaffInvitationRepository() StaffInvitationRepository {
+	return &staffInvitationRepository{}
+}
+
+func (sr *staffInvitationRepository) CreateStaffInvitation(ctx context.Context, db *sql.DB, invitation domain.StaffInvitation) error {
+	query := `
+		INSERT INTO staff_invitations (id, created_at, created_by, token_hash, role, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := db.ExecContext(ctx, query,
+		invitation.ID, invitation.CreatedAt, invitation.CreatedBy, invitation.TokenHash,
+		invitation.Role, invitation.ExpiresAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (sr *staffInvitationRepository) GetPendingStaffInvitations(ctx context.Context, db *sql.DB, now time.Time) ([]domain.StaffInvitation, error) {
+	query := `
+		SELECT id, created_at, created_by, role, expires_at
+		FROM staff_invitations
+		WHERE revoked_at IS NULL AND used_at IS NULL AND expires_at > $1
+		ORDER BY created_at desc, sid desc
+	`
+	rows, err := db.QueryContext(ctx, query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invitations := []domain.StaffInvitation{}
+	for rows.Next() {
+		invitation := domain.StaffInvitation{}
+
+		err := rows.Scan(&invitation.ID, &invitation.CreatedAt, &invitation.CreatedBy, &invitation.Role, &invitation.ExpiresAt)
+		if err != nil {
+			return nil, err
+		}
+
+		invitations = append(invitations, invitation)
+	}
+
+	return invitations, nil
+}
+
+func (sr *staffInvitationRepository) RevokeStaffInvitation(ctx context.Context, db *sql.DB, id string, revokedAt time.Time) (int64, error) {
+	query := `
+		UPDATE staff_invitations
+		SET revoked_at = $2
+		WHERE id = $1 AND revoked_at IS NULL AND used_at IS NULL
+	`
+	res, err := db.ExecContext(ctx, query, id, revokedAt)
+	if err != nil {
+		if err, ok := err.(*pgconn.PgError); ok {
+			if err.Code == "22P02" {
+				return 0, nil
+			}
+		}
+		return 0, err
+	}
+
+	affRow, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return affRow, nil
+}
+
+func (sr *staffInvitationRepository) GetStaffInvitationByTokenHashForUpdate(ctx context.Context, tx *sql.Tx, tokenHash string) (*domain.StaffInvitation, error) {
+	query := `
+		SELECT id, created_at, created_by, token_hash, role, expires_at, revoked_at, used_at, used_by
+		FROM staff_invitations
+		WHERE token_hash = $1
+		FOR UPDATE
+	`
+	invitation := domain.StaffInvitation{}
+	err := tx.QueryRowContext(ctx, query, tokenHash).Scan(
+		&invitation.ID, &invitation.CreatedAt, &invitation.CreatedBy, &invitation.TokenHash,
+		&invitation.Role, &invitation.ExpiresAt, &invitation.RevokedAt, &invitation.UsedAt, &invitation.UsedBy,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &invitation, nil
+}
+
+func (sr *staffInvitationRepository) UseStaffInvitation(ctx context.Context, tx *sql.Tx, id string, userAdminID string, usedAt time.Time) error {
+	query := `
+		UPDATE staff_invitations
+		SET used_at = $2, used_by = $3
+		WHERE id = $1
+	`
+	_, err := tx.ExecContext(ctx, query, id, usedAt, userAdminID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
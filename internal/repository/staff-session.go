@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"eniqilo-store/internal/domain"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type StaffSessionRepository interface {
+	CreateStaffSession(ctx context.Context, tx *sql.Tx, session domain.StaffSession) error
+	GetStaffSessionByID(ctx context.Context, db *sql.DB, id string) (*domain.StaffSession, error)
+	GetStaffSessionForUpdate(ctx context.Context, tx *sql.Tx, id string) (*domain.StaffSession, error)
+	RevokeStaffSession(ctx context.Context, tx *sql.Tx, id string, revokedAt time.Time) error
+	RevokeStaffSessionsByUserAdminID(ctx context.Context, db *sql.DB, userAdminID string, revokedAt time.Time) (int64, error)
+	DeleteExpiredStaffSessions(ctx context.Context, db *sql.DB, before time.Time) error
+	CreateStaffRefreshToken(ctx context.Context, tx *sql.Tx, refreshToken domain.StaffRefreshToken) error
+	GetStaffRefreshTokenForUpdate(ctx context.Context, tx *sql.Tx, tokenHash string) (*domain.StaffRefreshToken, error)
+	UseStaffRefreshToken(ctx context.Context, tx *sql.Tx, tokenHash string, usedAt time.Time) error
+}
+
+type staffSessionRepository struct{}
+
+func NewStaffSessionRepository() StaffSessionRepository {
+	return &staffSessionRepository{}
+}
+
+func (sr *staffSessionRepository) CreateStaffSession(ctx context.Context, tx *sql.Tx, session domain.StaffSession) error {
+	query := `
+		INSERT INTO staff_sessions (id, created_at, user_admin_id)
+		VALUES ($1, $2, $3)
+	`
+	_, err := tx.ExecContext(ctx, query, session.ID, session.CreatedAt, session.UserAdminID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (sr *staffSessionRepository) GetStaffSessionByID(ctx context.Context, db *sql.DB, id string) (*domain.StaffSession, error) {
+	query := `
+		SELECT id, created_at, user_admin_id, revoked_at
+		FROM staff_sessions
+		WHERE id = $1
+	`
+	return scanStaffSession(db.QueryRowContext(ctx, query, id))
+}
+
+func (sr *staffSessionRepository) GetStaffSessionForUpdate(ctx context.Context, tx *sql.Tx, id string) (*domain.StaffSession, error) {
+	query := `
+		SELECT id, created_at, user_admin_id, revoked_at
+		FROM staff_sessions
+		WHERE id = $1
+		FOR UPDATE
+	`
+	return scanStaffSession(tx.QueryRowContext(ctx, query, id))
+}
+
+func (sr *staffSessionRepository) RevokeStaffSession(ctx context.Context, tx *sql.Tx, id string, revokedAt time.Time) error {
+	query := `
+		UPDATE staff_sessions
+		SET revoked_at = $2
+		WHERE id = $1 AND revoked_at IS NULL
+	`
+	_, err := tx.ExecContext(ctx, query, id, revokedAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (sr *staffSessionRepository) RevokeStaffSessionsByUserAdminID(ctx context.Context, db *sql.DB, userAdminID string, revokedAt time.Time) (int64, error) {
+	query := `
+		UPDATE staff_sessions
+		SET revoked_at = $2
+		WHERE user_admin_id = $1 AND revoked_at IS NULL
+	`
+	res, err := db.ExecContext(ctx, query, userAdminID, revokedAt)
+	if err != nil {
+		if err, ok := err.(*pgconn.PgError); ok {
+			if err.Code == "22P02" {
+				return 0, nil
+			}
+		}
+		return 0, err
+	}
+
+	affRow, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return affRow, nil
+}
+
+func (sr *staffSessionRepository) DeleteExpiredStaffSessions(ctx context.Context, db *sql.DB, before time.Time) error {
+	query := `
+		DELETE FROM staff_sessions s
+		WHERE NOT EXISTS (
+			SELECT 1
+			FROM staff_refresh_tokens t
+			WHERE t.session_id = s.id AND t.expires_at >= $1
+		)
+	`
+	_, err := db.ExecContext(ctx, query, before)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (sr *staffSessionRepository) CreateStaffRefreshToken(ctx context.Context, tx *sql.Tx, refreshToken domain.StaffRefreshToken) error {
+	query := `
+		INSERT INTO staff_refresh_tokens (token_hash, session_id, created_at, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	_, err := tx.ExecContext(ctx, query, refreshToken.TokenHash, refreshToken.SessionID, refreshToken.CreatedAt, refreshToken.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (sr *staffSessionRepository) GetStaffRefreshTokenForUpdate(ctx context.Context, tx *sql.Tx, tokenHash string) (*domain.StaffRefreshToken, error) {
+	query := `
+		SELECT token_hash, session_id, created_at, expires_at, used_at
+		FROM staff_refresh_tokens
+		WHERE token_hash = $1
+		FOR UPDATE
+	`
+	refreshToken := domain.StaffRefreshToken{}
+	err := tx.QueryRowContext(ctx, query, tokenHash).Scan(
+		&refreshToken.TokenHash, &refreshToken.SessionID, &refreshToken.CreatedAt,
+		&refreshToken.ExpiresAt, &refreshToken.UsedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &refreshToken, nil
+}
+
+func (sr *staffSessionRepository) UseStaffRefreshToken(ctx context.Context, tx *sql.Tx, tokenHash string, usedAt time.Time) error {
+	query := `
+		UPDATE staff_refresh_tokens
+		SET used_at = $2
+		WHERE token_hash = $1
+	`
+	_, err := tx.ExecContext(ctx, query, tokenHash, usedAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func scanStaffSession(row *sql.Row) (*domain.StaffSession, error) {
+	session := domain.StaffSession{}
+	err := row.Scan(&session.ID, &session.CreatedAt, &session.UserAdminID, &session.RevokedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		if err, ok := err.(*pgconn.PgError); ok {
+			if err.Code == "22P02" {
+				return nil, nil
+			}
+		}
+		return nil, err
+	}
+
+	return &session, nil
+}
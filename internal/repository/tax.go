@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"eniqilo-store/internal/domain"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type TaxRateRepository interface {
+	CreateTaxRate(ctx context.Context, db *sql.DB, taxRate domain.TaxRate) error
+	GetTaxRates(ctx context.Context, db *sql.DB) ([]domain.TaxRate, error)
+	GetTaxRatesForProducts(ctx context.Context, tx *sql.Tx, productIDs []string, categories []string) ([]domain.TaxRate, error)
+	UpdateTaxRateByID(ctx context.Context, db *sql.DB, taxRate domain.TaxRate) (int64, error)
+	DeleteTaxRateByID(ctx context.Context, db *sql.DB, id string) (int64, error)
+}
+
+type taxRateRepository struct{}
+
+func NewTaxRateRepository() TaxRateRepository {
+	return &taxRateRepository{}
+}
+
+func (tr *taxRateRepository) CreateTaxRate(ctx context.Context, db *sql.DB, taxRate domain.TaxRate) error {
+	query := `
+		INSERT INTO tax_rates (id, created_at, name, category, product_id, rate, inclusive)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := db.ExecContext(ctx, query,
+		taxRate.ID, taxRate.CreatedAt, taxRate.Name, taxRate.Category,
+		taxRate.ProductID, taxRate.Rate, taxRate.Inclusive,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (tr *taxRateRepository) GetTaxRates(ctx context.Context, db *sql.DB) ([]domain.TaxRate, error) {
+	query := `
+		SELECT id, created_at, name, category, product_id, rate, inclusive
+		FROM tax_rates
+		ORDER BY created_at desc, sid desc
+	`
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTaxRates(rows)
+}
+
+func (tr *taxRateRepository) GetTaxRatesForProducts(ctx context.Context, tx *sql.Tx, productIDs []string, categories []string) ([]domain.TaxRate, error) {
+	query := `
+		SELECT id, created_at, name, category, product_id, rate, inclusive
+		FROM tax_rates
+		WHERE product_id = any ($1)
+			OR category = any ($2)
+	`
+	rows, err := tx.QueryContext(ctx, query, productIDs, categories)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanTaxRates(rows)
+}
+
+func (tr *taxRateRepository) UpdateTaxRateByID(ctx context.Context, db *sql.DB, taxRate domain.TaxRate) (int64, error) {
+	query := `
+		UPDATE tax_rates
+		SET name = $2,
+			category = $3,
+			product_id = $4,
+			rate = $5,
+			inclusive = $6
+		WHERE id = $1
+	`
+	res, err := db.ExecContext(ctx, query,
+		taxRate.ID, taxRate.Name, taxRate.Category, taxRate.ProductID,
+		taxRate.Rate, taxRate.Inclusive,
+	)
+	if err != nil {
+		if err, ok := err.(*pgconn.PgError); ok {
+			if err.Code == "22P02" {
+				return 0, nil
+			}
+		}
+		return 0, err
+	}
+
+	affRow, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return affRow, nil
+}
+
+func (tr *taxRateRepository) DeleteTaxRateByID(ctx context.Context, db *sql.DB, id string) (int64, error) {
+	query := `
+		DELETE FROM tax_rates
+		WHERE id = $1
+	`
+	res, err := db.ExecContext(ctx, query, id)
+	if err != nil {
+		if err, ok := err.(*pgconn.PgError); ok {
+			if err.Code == "22P02" {
+				return 0, nil
+			}
+		}
+		return 0, err
+	}
+
+	affRow, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return affRow, nil
+}
+
+func scanTaxRates(rows *sql.Rows) ([]domain.TaxRate, error) {
+	taxRates := []domain.TaxRate{}
+	for rows.Next() {
+		taxRate := domain.TaxRate{}
+
+		err := rows.Scan(
+			&taxRate.ID, &taxRate.CreatedAt, &taxRate.Name, &taxRate.Category,
+			&taxRate.ProductID, &taxRate.Rate, &taxRate.Inclusive,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		taxRates = append(taxRates, taxRate)
+	}
+
+	return taxRates, nil
+}
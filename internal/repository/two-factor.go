@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"eniqilo-store/internal/domain"
+	"time"
+)
+
+type TwoFactorRepository interface {
+	GetStaffTwoFactor(ctx context.Context, db *sql.DB, userAdminID string) (*domain.StaffTwoFactor, error)
+	GetStaffTwoFactorForUpdate(ctx context.Context, tx *sql.Tx, userAdminID string) (*domain.StaffTwoFactor, error)
+	SetStaffTwoFactorPendingSecret(ctx context.Context, tx *sql.Tx, userAdminID string, secret string) error
+	EnableStaffTwoFactor(ctx context.Context, tx *sql.Tx, userAdminID string, enabledAt time.Time, lastStep int64) error
+	UpdateStaffTwoFactorLastStep(ctx context.Context, tx *sql.Tx, userAdminID string, lastStep int64) error
+	DeleteStaffTwoFactor(ctx context.Context, tx *sql.Tx, userAdminID string) error
+	ReplaceStaffRecoveryCodes(ctx context.Context, tx *sql.Tx, userAdminID string, codeHashes []string, createdAt time.Time) error
+	UseStaffRecoveryCode(ctx context.Context, tx *sql.Tx, userAdminID string, codeHash string, usedAt time.Time) (int64, error)
+	CountStaffRecoveryCodes(ctx context.Context, db *sql.DB, userAdminID string) (int, error)
+	CreateLoginChallenge(ctx context.Context, tx *sql.Tx, challenge domain.LoginChallenge) error
+	GetLoginChallengeForUpdate(ctx context.Context, tx *sql.Tx, tokenHash string) (*domain.LoginChallenge, error)
+	FailLoginChallenge(ctx context.Context, tx *sql.Tx, tokenHash string) error
+	UseLoginChallenge(ctx context.Context, tx *sql.Tx, tokenHash string, usedAt time.Time) error
+	DeleteExpiredLoginChallenges(ctx context.Context, db *sql.DB, before time.Time) error
+}
+
+type twoFactorRepository struct{}
+
+func NewTwoFactorRepository() TwoFactorRepository {
+	return &twoFactorRepository{}
+}
+
+func (tr *twoFactorRepository) GetStaffTwoFactor(ctx context.Context, db *sql.DB, userAdminID string) (*domain.StaffTwoFactor, error) {
+	query := `
+		SELECT user_admin_id, secret, pending_secret, enabled_at, last_step
+		FROM staff_two_factors
+		WHERE user_admin_id = $1
+	`
+	return scanStaffTwoFactor(db.QueryRowContext(ctx, query, userAdminID))
+}
+
+// checks of one staff member's codes wait for each other
+func (tr *twoFactorRepository) GetStaffTwoFactorForUpdate(ctx context.Context, tx *sql.Tx, userAdminID string) (*domain.StaffTwoFactor, error) {
+	query := `
+		SELECT user_admin_id, secret, pending_secret, enabled_at, last_step
+		FROM staff_two_factors
+		WHERE user_admin_id = $1
+		FOR UPDATE
+	`
+	return scanStaffTwoFactor(tx.QueryRowContext(ctx, query, userAdminID))
+}
+
+func (tr *twoFactorRepository) SetStaffTwoFactorPendingSecret(ctx context.Context, tx *sql.Tx, userAdminID string, secret string) error {
+	query := `
+		INSERT INTO staff_two_factors (user_admin_id, pending_secret)
+		VALUES ($1, $2)
+		ON CONFLICT (user_admin_id) DO UPDATE
+		SET pending_secret = EXCLUDED.pending_secret
+	`
+	_, err := tx.ExecContext(ctx, query, userAdminID, secret)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (tr *twoFactorRepository) EnableStaffTwoFactor(ctx context.Context, tx *sql.Tx, userAdminID string, enabledAt time.Time, lastStep int64) error {
+	query := `
+		UPDATE staff_two_factors
+		SET secret = pending_secret,
+			pending_secret = NULL,
+			enabled_at = $2,
+			last_step = $3
+		WHERE user_admin_id = $1
+	`
+	_, err := tx.ExecContext(ctx, query, userAdminID, enabledAt, lastStep)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (tr *twoFactorRepository) UpdateStaffTwoFactorLastStep(ctx context.Context, tx *sql.Tx, userAdminID string, lastStep int64) error {
+	query := `
+		UPDATE staff_two_factors
+		SET last_step = $2
+		WHERE user_admin_id = $1
+	`
+	_, err := tx.ExecContext(ctx, query, userAdminID, lastStep)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (tr *twoFactorRepository) DeleteStaffTwoFactor(ctx context.Context, tx *sql.Tx, userAdminID string) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM staff_recovery_codes WHERE user_admin_id = $1`, userAdminID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM staff_two_factors WHERE user_admin_id = $1`, userAdminID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (tr *twoFactorRepository) ReplaceStaffRecoveryCodes(ctx context.Context, tx *sql.Tx, userAdminID string, codeHashes []string, createdAt time.Time) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM staff_recovery_codes WHERE user_admin_id = $1`, userAdminID)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO staff_recovery_codes (user_admin_id, code_hash, created_at)
+		VALUES ($1, $2, $3)
+	`
+	for _, codeHash := range codeHashes {
+		_, err = tx.ExecContext(ctx, query, userAdminID, codeHash, createdAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (tr *twoFactorRepository) UseStaffRecoveryCode(ctx context.Context, tx *sql.Tx, userAdminID string, codeHash string, usedAt time.Time) (int64, error) {
+	query := `
+		UPDATE staff_recovery_codes
+		SET used_at = $3
+		WHERE user_admin_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+	res, err := tx.ExecContext(ctx, query, userAdminID, codeHash, usedAt)
+	if err != nil {
+		return 0, err
+	}
+
+	affRow, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return affRow, nil
+}
+
+func (tr *twoFactorRepository) CountStaffRecoveryCodes(ctx context.Context, db *sql.DB, userAdminID string) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM staff_recovery_codes
+		WHERE user_admin_id = $1 AND used_at IS NULL
+	`
+	var count int
+	err := db.QueryRowContext(ctx, query, userAdminID).Scan(&count)
+
+	return count, err
+}
+
+func (tr *twoFactorRepository) CreateLoginChallenge(ctx context.Context, tx *sql.Tx, challenge domain.LoginChallenge) error {
+	query := `
+		INSERT INTO login_challenges (token_hash, user_admin_id, type, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := tx.ExecContext(ctx, query,
+		challenge.TokenHash, challenge.UserAdminID, challenge.Type, challenge.CreatedAt, challenge.ExpiresAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (tr *twoFactorRepository) GetLoginChallengeForUpdate(ctx context.Context, tx *sql.Tx, tokenHash string) (*domain.LoginChallenge, error) {
+	query := `
+		SELECT token_hash, user_admin_id, type, created_at, expires_at, failed_attempts, used_at
+		FROM login_challenges
+		WHERE token_hash = $1
+		FOR UPDATE
+	`
+	return scanLoginChallenge(tx.QueryRowContext(ctx, query, tokenHash))
+}
+
+func (tr *twoFactorRepository) FailLoginChallenge(ctx context.Context, tx *sql.Tx, tokenHash string) error {
+	query := `
+		UPDATE login_challenges
+		SET failed_attempts = failed_attempts + 1
+		WHERE token_hash = $1
+	`
+	_, err := tx.ExecContext(ctx, query, tokenHash)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (tr *twoFactorRepository) UseLoginChallenge(ctx context.Context, tx *sql.Tx, tokenHash string, usedAt time.Time) error {
+	query := `
+		UPDATE login_challenges
+		SET used_at = $2
+		WHERE token_hash = $1
+	`
+	_, err := tx.ExecContext(ctx, query, tokenHash, usedAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (tr *twoFactorRepository) DeleteExpiredLoginChallenges(ctx context.Context, db *sql.DB, before time.Time) error {
+	_, err := db.ExecContext(ctx, `DELETE FROM login_challenges WHERE expires_at < $1`, before)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func scanStaffTwoFactor(row *sql.Row) (*domain.StaffTwoFactor, error) {
+	twoFactor := domain.StaffTwoFactor{}
+	err := row.Scan(
+		&twoFactor.UserAdminID, &twoFactor.Secret, &twoFactor.PendingSecret, &twoFactor.EnabledAt,
+		&twoFactor.LastStep,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &twoFactor, nil
+}
+
+func scanLoginChallenge(row *sql.Row) (*domain.LoginChallenge, error) {
+	challenge := domain.LoginChallenge{}
+	err := row.Scan(
+		&challenge.TokenHash, &challenge.UserAdminID, &challenge.Type, &challenge.CreatedAt,
+		&challenge.ExpiresAt, &challenge.FailedAttempts, &challenge.UsedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &challenge, nil
+}
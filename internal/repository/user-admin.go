@@ -4,13 +4,19 @@ import (
 	"context"
 	"database/sql"
 	"eniqilo-store/internal/domain"
+
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 type UserAdminRepository interface {
-	CreateUserAdminRepository(ctx context.Context, db *sql.DB, userAdmin domain.UserAdmin) error
+	CreateUserAdminRepository(ctx context.Context, tx *sql.Tx, userAdmin domain.UserAdmin) error
 	GetUserByIDAdminRepository(ctx context.Context, tx *sql.Tx, id string) (*domain.UserAdmin, error)
 	GetUserByPhoneNumberRepository(ctx context.Context, db *sql.DB, phoneNumber string) (*domain.UserAdmin, error)
 	CheckPhoneNumberExists(ctx context.Context, tx *sql.Tx, phoneNumber string) (bool, error)
+	LockUserAdmins(ctx context.Context, tx *sql.Tx) error
+	CheckUserAdminExists(ctx context.Context, tx *sql.Tx) (bool, error)
+	GetUserAdmins(ctx context.Context, db *sql.DB) ([]domain.UserAdmin, error)
+	UpdateUserAdminRole(ctx context.Context, db *sql.DB, id string, role string) (*domain.UserAdmin, error)
 }
 
 type userRepository struct{}
@@ -19,10 +25,10 @@ func NewUserAdminRepository() UserAdminRepository {
 	return &userRepository{}
 }
 
-func (u *userRepository) CreateUserAdminRepository(ctx context.Context, db *sql.DB, userAdmin domain.UserAdmin) error {
+func (u *userRepository) CreateUserAdminRepository(ctx context.Context, tx *sql.Tx, userAdmin domain.UserAdmin) error {
 	query := `INSERT INTO user_admins (id, created_at, phone_number, password, name, role) VALUES ($1, $2, $3, $4, $5, $6)`
 
-	_, err := db.ExecContext(ctx, query, userAdmin.ID, userAdmin.CreatedAt, userAdmin.PhoneNumber, userAdmin.Password, userAdmin.Name, userAdmin.Role)
+	_, err := tx.ExecContext(ctx, query, userAdmin.ID, userAdmin.CreatedAt, userAdmin.PhoneNumber, userAdmin.Password, userAdmin.Name, userAdmin.Role)
 	if err != nil {
 		return err
 	}
@@ -33,15 +39,15 @@ func (u *userRepository) CreateUserAdminRepository(ctx context.Context, db *sql.
 func (u *userRepository) GetUserByIDAdminRepository(ctx context.Context, tx *sql.Tx, id string) (*domain.UserAdmin, error) {
 	user := domain.UserAdmin{}
 
-	query := `SELECT id, phone_number, name, role FROM user_admins WHERE id = $1`
+	query := `SELECT id, created_at, phone_number, name, role FROM user_admins WHERE id = $1`
 
 	row := tx.QueryRowContext(ctx, query, id)
-	err := row.Scan(user)
+	err := row.Scan(&user.ID, &user.CreatedAt, &user.PhoneNumber, &user.Name, &user.Role)
 	if err != nil {
 		return nil, err
 	}
 
-	return &domain.UserAdmin{}, nil
+	return &user, nil
 }
 
 func (u *userRepository) GetUserByPhoneNumberRepository(ctx context.Context, db *sql.DB, phoneNumber string) (*domain.UserAdmin, error) {
@@ -74,3 +80,81 @@ func (u *userRepository) CheckPhoneNumberExists(ctx context.Context, tx *sql.Tx,
 
 	return exists, nil
 }
+
+// blocks registrations until tx ends, logins still go through
+func (u *userRepository) LockUserAdmins(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `LOCK TABLE user_admins IN SHARE ROW EXCLUSIVE MODE`)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (u *userRepository) CheckUserAdminExists(ctx context.Context, tx *sql.Tx) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM user_admins
+		)
+	`
+	var exists bool
+	err := tx.QueryRowContext(ctx, query).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (u *userRepository) GetUserAdmins(ctx context.Context, db *sql.DB) ([]domain.UserAdmin, error) {
+	query := `
+		SELECT id, created_at, phone_number, name, role
+		FROM user_admins
+		ORDER BY name, sid
+	`
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []domain.UserAdmin{}
+	for rows.Next() {
+		user := domain.UserAdmin{}
+
+		err := rows.Scan(&user.ID, &user.CreatedAt, &user.PhoneNumber, &user.Name, &user.Role)
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+func (u *userRepository) UpdateUserAdminRole(ctx context.Context, db *sql.DB, id string, role string) (*domain.UserAdmin, error) {
+	user := domain.UserAdmin{}
+
+	query := `
+		UPDATE user_admins
+		SET role = $2
+		WHERE id = $1
+		RETURNING id, created_at, phone_number, name, role
+	`
+	err := db.QueryRowContext(ctx, query, id, role).Scan(&user.ID, &user.CreatedAt, &user.PhoneNumber, &user.Name, &user.Role)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		if err, ok := err.(*pgconn.PgError); ok {
+			if err.Code == "22P02" {
+				return nil, nil
+			}
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
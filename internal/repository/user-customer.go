@@ -12,6 +12,7 @@ type UserCustomerRepository interface {
 	CreateUserCustomer(ctx context.Context, db *sql.DB, userCustomer domain.UserCustomer) error
 	GetCustomers(ctx context.Context, db *sql.DB, queryParams string, args []any) ([]domain.UserCustomerResponse, error)
 	CheckCustomerExistsByID(ctx context.Context, db *sql.DB, id string) (bool, error)
+	LockCustomerByID(ctx context.Context, tx *sql.Tx, id string) error
 }
 
 type userCustomerRepository struct{}
@@ -76,3 +77,15 @@ func (ucr *userCustomerRepository) CheckCustomerExistsByID(ctx context.Context,
 
 	return exists, nil
 }
+
+// serializes spending from the customer's balances until tx ends
+func (ucr *userCustomerRepository) LockCustomerByID(ctx context.Context, tx *sql.Tx, id string) error {
+	query := `SELECT id FROM user_customers WHERE id = $1 FOR UPDATE`
+	var lockedID string
+	err := tx.QueryRowContext(ctx, query, id).Scan(&lockedID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package server
+
+import (
+	"context"
+	"eniqilo-store/internal/domain"
+	"log"
+	"time"
+)
+
+func startJob(name string, interval time.Duration, job func(ctx context.Context) domain.MessageErr) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), interval)
+			err := job(ctx)
+			cancel()
+			if err != nil {
+				log.Printf("%s: %s", name, err.Message())
+			}
+		}
+	}()
+}
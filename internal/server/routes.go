@@ -2,14 +2,18 @@ package server
 
 import (
 	"eniqilo-store/internal/auth"
+	"eniqilo-store/internal/domain"
 	"eniqilo-store/internal/handler"
 	"eniqilo-store/internal/repository"
 	"eniqilo-store/internal/service"
+	"log"
 	"net/http"
 	"net/url"
 	"os"
 	"strconv"
 	"strings"
+	"time"
+	_ "time/tzdata"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
@@ -17,31 +21,138 @@ import (
 )
 
 var (
-	jwtSecret     = os.Getenv("JWT_SECRET")
-	bcryptSalt, _ = strconv.Atoi(os.Getenv("BCRYPT_SALT"))
+	jwtSecret                 = os.Getenv("JWT_SECRET")
+	bcryptSalt, _             = strconv.Atoi(os.Getenv("BCRYPT_SALT"))
+	idempotencyKeyTTLHours, _ = strconv.Atoi(os.Getenv("IDEMPOTENCY_KEY_TTL_HOURS"))
+	voidWindowMinutes, _      = strconv.Atoi(os.Getenv("VOID_WINDOW_MINUTES"))
+	storeTimezone             = os.Getenv("STORE_TIMEZONE")
+	pointsEarnRate, _         = strconv.ParseFloat(os.Getenv("POINTS_EARN_RATE"), 64)
+	cartTTLHours, _           = strconv.Atoi(os.Getenv("CART_TTL_HOURS"))
+	shiftsEnabled, _          = strconv.ParseBool(os.Getenv("SHIFTS_ENABLED"))
+	invitationTTLHours, _     = strconv.Atoi(os.Getenv("STAFF_INVITATION_TTL_HOURS"))
+	accessTokenTTLMinutes, _  = strconv.Atoi(os.Getenv("ACCESS_TOKEN_TTL_MINUTES"))
+	refreshTokenTTLHours, _   = strconv.Atoi(os.Getenv("REFRESH_TOKEN_TTL_HOURS"))
+	trustedProxies            = os.Getenv("TRUSTED_PROXIES")
+	totpIssuer                = os.Getenv("TOTP_ISSUER")
+	// set but empty turns the requirement off for every role
+	twoFactorRequiredRoles, hasTwoFactorRequiredRoles = os.LookupEnv("TWO_FACTOR_REQUIRED_ROLES")
 )
 
 func (s *Server) RegisterRoutes() http.Handler {
 	db := s.db.GetDB()
 
 	userAdminRepository := repository.NewUserAdminRepository()
+	staffInvitationRepository := repository.NewStaffInvitationRepository()
+	staffSessionRepository := repository.NewStaffSessionRepository()
+	loginAttemptRepository := repository.NewLoginAttemptRepository()
+	twoFactorRepository := repository.NewTwoFactorRepository()
 	productRepository := repository.NewProductRepository()
 	userCustomerRepository := repository.NewUserCustomerRepository()
 	checkoutRepository := repository.NewCheckoutRepository()
+	idempotencyKeyRepository := repository.NewIdempotencyKeyRepository()
+	paymentRepository := repository.NewPaymentRepository()
+	taxRateRepository := repository.NewTaxRateRepository()
+	promotionRepository := repository.NewPromotionRepository()
+	couponRepository := repository.NewCouponRepository()
+	pointsRepository := repository.NewPointsRepository()
+	creditRepository := repository.NewCreditRepository()
+	giftCardRepository := repository.NewGiftCardRepository()
+	cartRepository := repository.NewCartRepository()
+	shiftRepository := repository.NewShiftRepository()
+	reportRepository := repository.NewReportRepository()
 
-	userAdminService := service.NewUserAdminService(db, userAdminRepository, jwtSecret, bcryptSalt)
+	idempotencyKeyTTL := 24 * time.Hour
+	if idempotencyKeyTTLHours > 0 {
+		idempotencyKeyTTL = time.Duration(idempotencyKeyTTLHours) * time.Hour
+	}
+	voidWindow := 8 * time.Hour
+	if voidWindowMinutes > 0 {
+		voidWindow = time.Duration(voidWindowMinutes) * time.Minute
+	}
+	if len(storeTimezone) == 0 {
+		storeTimezone = "Asia/Jakarta"
+	}
+	storeLocation, err := time.LoadLocation(storeTimezone)
+	if err != nil {
+		log.Fatalf("invalid STORE_TIMEZONE: %s", err)
+	}
+	if pointsEarnRate <= 0 {
+		pointsEarnRate = 1
+	}
+	cartTTL := 24 * time.Hour
+	if cartTTLHours > 0 {
+		cartTTL = time.Duration(cartTTLHours) * time.Hour
+	}
+	invitationTTL := 72 * time.Hour
+	if invitationTTLHours > 0 {
+		invitationTTL = time.Duration(invitationTTLHours) * time.Hour
+	}
+	accessTokenTTL := 15 * time.Minute
+	if accessTokenTTLMinutes > 0 {
+		accessTokenTTL = time.Duration(accessTokenTTLMinutes) * time.Minute
+	}
+	refreshTokenTTL := 7 * 24 * time.Hour
+	if refreshTokenTTLHours > 0 {
+		refreshTokenTTL = time.Duration(refreshTokenTTLHours) * time.Hour
+	}
+	if len(totpIssuer) == 0 {
+		totpIssuer = "Eniqilo Store"
+	}
+	twoFactorRoles := []string{domain.UserAdminRoleOwner, domain.UserAdminRoleManager}
+	if hasTwoFactorRequiredRoles {
+		var err error
+		twoFactorRoles, err = domain.ParseTwoFactorRoles(twoFactorRequiredRoles)
+		if err != nil {
+			log.Fatalf("invalid TWO_FACTOR_REQUIRED_ROLES: %s", err)
+		}
+	}
+
+	userAdminService := service.NewUserAdminService(db, userAdminRepository, staffInvitationRepository, staffSessionRepository, loginAttemptRepository, twoFactorRepository, jwtSecret, bcryptSalt, invitationTTL, accessTokenTTL, refreshTokenTTL, totpIssuer, twoFactorRoles)
+	twoFactorService := service.NewTwoFactorService(db, twoFactorRepository, loginAttemptRepository, totpIssuer, twoFactorRoles)
 	productService := service.NewProductService(db, productRepository)
 	userCustomerService := service.NewUserCustomerService(db, userCustomerRepository)
-	checkoutService := service.NewCheckoutService(db, checkoutRepository, userCustomerRepository, productRepository)
-	auths := auth.NewAuthMiddleware(db, jwtSecret, userAdminRepository)
+	checkoutService := service.NewCheckoutService(db, checkoutRepository, userCustomerRepository, productRepository, idempotencyKeyRepository, paymentRepository, taxRateRepository, promotionRepository, couponRepository, pointsRepository, creditRepository, giftCardRepository, shiftRepository, idempotencyKeyTTL, voidWindow, storeLocation, pointsEarnRate, shiftsEnabled)
+	taxRateService := service.NewTaxRateService(db, taxRateRepository)
+	promotionService := service.NewPromotionService(db, promotionRepository, storeLocation)
+	couponService := service.NewCouponService(db, couponRepository, storeLocation)
+	pointsService := service.NewPointsService(db, pointsRepository, userCustomerRepository)
+	creditService := service.NewCreditService(db, creditRepository, userCustomerRepository, shiftRepository, storeLocation, shiftsEnabled)
+	giftCardService := service.NewGiftCardService(db, giftCardRepository, checkoutRepository, paymentRepository, userCustomerRepository, shiftRepository, shiftsEnabled)
+	cartService := service.NewCartService(db, cartRepository, userCustomerRepository, checkoutService, cartTTL)
+	shiftService := service.NewShiftService(db, shiftRepository)
+	reportService := service.NewReportService(db, reportRepository, storeLocation)
+	auths := auth.NewAuthMiddleware(db, jwtSecret, userAdminRepository, staffSessionRepository)
 
 	userAdminHandler := handler.NewUserAdminHandler(userAdminService)
+	twoFactorHandler := handler.NewTwoFactorHandler(twoFactorService)
 	productHandler := handler.NewProductHandler(productService)
 	userCustomerHandler := handler.NewUserCustomerHandler(userCustomerService)
 	checkoutHandler := handler.NewCheckoutHandler(checkoutService)
+	taxRateHandler := handler.NewTaxRateHandler(taxRateService)
+	promotionHandler := handler.NewPromotionHandler(promotionService)
+	couponHandler := handler.NewCouponHandler(couponService)
+	pointsHandler := handler.NewPointsHandler(pointsService)
+	creditHandler := handler.NewCreditHandler(creditService)
+	giftCardHandler := handler.NewGiftCardHandler(giftCardService)
+	cartHandler := handler.NewCartHandler(cartService)
+	shiftHandler := handler.NewShiftHandler(shiftService)
+	reportHandler := handler.NewReportHandler(reportService)
+
+	startJob("delete expired idempotency keys", time.Hour, checkoutService.DeleteExpiredIdempotencyKeys)
+	startJob("delete expired carts", time.Hour, cartService.DeleteExpiredCarts)
+	startJob("delete expired staff sessions", time.Hour, userAdminService.DeleteExpiredStaffSessions)
+	startJob("delete expired login challenges", time.Hour, twoFactorService.DeleteExpiredLoginChallenges)
 
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.Default()
+	// logins are limited per client IP, so it can't come from headers anyone can set
+	var proxies []string
+	if len(trustedProxies) > 0 {
+		proxies = strings.Split(trustedProxies, ",")
+	}
+	if err := r.SetTrustedProxies(proxies); err != nil {
+		log.Fatalf("invalid TRUSTED_PROXIES: %s", err)
+	}
 
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		v.RegisterValidation("validurl", validURL)
@@ -57,23 +168,115 @@ func (s *Server) RegisterRoutes() http.Handler {
 	staff := apiV1.Group("/staff")
 	staff.POST("/register", userAdminHandler.RegisterUserAdminHandler())
 	staff.POST("/login", userAdminHandler.LoginUserAdminHandler())
+	staff.POST("/login/enrol", userAdminHandler.EnrolLoginHandler())
+	staff.POST("/login/verify", userAdminHandler.VerifyLoginHandler())
+	staff.POST("/refresh", userAdminHandler.RefreshTokenHandler())
+	staff.POST("/logout", auths.Authentication(), userAdminHandler.LogoutUserAdminHandler())
+
+	staffManagement := staff.Group("")
+	staffManagement.Use(auths.Authentication(), auths.Authorization(domain.PermissionStaffManage))
+	staffManagement.GET("", userAdminHandler.GetUserAdmins())
+	staffManagement.GET("/login-attempts", userAdminHandler.GetLoginAttempts())
+	staffManagement.PUT(":id/role", userAdminHandler.UpdateUserAdminRole())
+	staffManagement.DELETE(":id/sessions", userAdminHandler.RevokeUserAdminSessions())
+
+	twoFactor := staff.Group("/2fa")
+	twoFactor.Use(auths.Authentication())
+	twoFactor.GET("", twoFactorHandler.GetTwoFactor())
+	twoFactor.POST("/enrol", twoFactorHandler.EnrolTwoFactor())
+	twoFactor.POST("/confirm", twoFactorHandler.ConfirmTwoFactor())
+	twoFactor.POST("/recovery-codes", twoFactorHandler.RegenerateRecoveryCodes())
+	twoFactor.POST("/disable", twoFactorHandler.DisableTwoFactor())
+
+	invitation := staff.Group("/invitations")
+	invitation.Use(auths.Authentication(), auths.Authorization(domain.PermissionStaffInvite))
+	invitation.POST("", userAdminHandler.CreateStaffInvitation())
+	invitation.GET("", userAdminHandler.GetStaffInvitations())
+	invitation.DELETE(":id", userAdminHandler.RevokeStaffInvitation())
 
 	product := apiV1.Group("/product")
 	product.Use(auths.Authentication())
-	product.POST("", productHandler.CreateProduct())
-	product.GET("", productHandler.GetProducts())
-	product.PUT(":id", productHandler.UpdateProductByID())
-	product.DELETE(":id", productHandler.DeleteProductByID())
+	product.POST("", auths.Authorization(domain.PermissionProductWrite), productHandler.CreateProduct())
+	product.GET("", auths.Authorization(domain.PermissionProductRead), productHandler.GetProducts())
+	product.PUT(":id", auths.Authorization(domain.PermissionProductWrite), productHandler.UpdateProductByID())
+	product.DELETE(":id", auths.Authorization(domain.PermissionProductDelete), productHandler.DeleteProductByID())
 	product.GET("/customer", productHandler.GetProductsForCustomer())
 
 	checkout := product.Group("/checkout")
-	checkout.POST("", checkoutHandler.CreateCheckout())
-	checkout.GET("/history", checkoutHandler.GetCheckoutHistory())
+	checkout.POST("", auths.Authorization(domain.PermissionCheckoutCreate), checkoutHandler.CreateCheckout())
+	checkout.POST("/quote", auths.Authorization(domain.PermissionCheckoutCreate), checkoutHandler.QuoteCheckout())
+	checkout.GET("/history", auths.Authorization(domain.PermissionCheckoutRead), checkoutHandler.GetCheckoutHistory())
+	checkout.GET("/summary", auths.Authorization(domain.PermissionReportRead), checkoutHandler.GetCashierSalesSummary())
+	checkout.GET(":id", auths.Authorization(domain.PermissionCheckoutRead), checkoutHandler.GetCheckoutByID())
+	checkout.POST(":id/return", auths.Authorization(domain.PermissionCheckoutRefund), checkoutHandler.CreateReturn())
+	checkout.POST(":id/void", auths.Authorization(domain.PermissionCheckoutVoid), checkoutHandler.VoidCheckout())
+
+	cart := apiV1.Group("/cart")
+	cart.Use(auths.Authentication(), auths.Authorization(domain.PermissionCheckoutCreate))
+	cart.POST("", cartHandler.CreateCart())
+	cart.GET("", cartHandler.GetCarts())
+	cart.GET(":id", cartHandler.GetCartByID())
+	cart.DELETE(":id", cartHandler.DeleteCartByID())
+	cart.PUT(":id/items", cartHandler.SetCartItem())
+	cart.DELETE(":id/items/:productId", cartHandler.RemoveCartItem())
+	cart.PUT(":id/customer", cartHandler.SetCartCustomer())
+	cart.POST(":id/park", cartHandler.ParkCart())
+	cart.POST(":id/resume", cartHandler.ResumeCart())
+	cart.POST(":id/checkout", cartHandler.CheckoutCart())
+
+	shift := apiV1.Group("/shift")
+	shift.Use(auths.Authentication(), auths.Authorization(domain.PermissionShiftOperate))
+	shift.POST("", shiftHandler.OpenShift())
+	shift.GET("/current", shiftHandler.GetCurrentShift())
+	shift.POST("/current/payouts", shiftHandler.CreateCashPayout())
+	shift.POST("/current/close", shiftHandler.CloseShift())
+	shift.GET(":id", shiftHandler.GetShiftByID())
+
+	tax := apiV1.Group("/tax")
+	tax.Use(auths.Authentication())
+	tax.POST("", auths.Authorization(domain.PermissionPricingWrite), taxRateHandler.CreateTaxRate())
+	tax.GET("", auths.Authorization(domain.PermissionPricingRead), taxRateHandler.GetTaxRates())
+	tax.PUT(":id", auths.Authorization(domain.PermissionPricingWrite), taxRateHandler.UpdateTaxRateByID())
+	tax.DELETE(":id", auths.Authorization(domain.PermissionPricingWrite), taxRateHandler.DeleteTaxRateByID())
+
+	promotion := apiV1.Group("/promotion")
+	promotion.Use(auths.Authentication())
+	promotion.POST("", auths.Authorization(domain.PermissionPricingWrite), promotionHandler.CreatePromotion())
+	promotion.GET("", auths.Authorization(domain.PermissionPricingRead), promotionHandler.GetPromotions())
+	promotion.GET("/report", auths.Authorization(domain.PermissionReportRead), promotionHandler.GetPromotionReport())
+	promotion.PUT(":id", auths.Authorization(domain.PermissionPricingWrite), promotionHandler.UpdatePromotionByID())
+	promotion.DELETE(":id", auths.Authorization(domain.PermissionPricingWrite), promotionHandler.DeletePromotionByID())
+
+	coupon := apiV1.Group("/coupon")
+	coupon.Use(auths.Authentication())
+	coupon.POST("", auths.Authorization(domain.PermissionPricingWrite), couponHandler.CreateCoupon())
+	coupon.GET("", auths.Authorization(domain.PermissionPricingRead), couponHandler.GetCoupons())
+	coupon.GET("/report", auths.Authorization(domain.PermissionReportRead), couponHandler.GetCouponReport())
+	coupon.PUT(":id", auths.Authorization(domain.PermissionPricingWrite), couponHandler.UpdateCouponByID())
+	coupon.DELETE(":id", auths.Authorization(domain.PermissionPricingWrite), couponHandler.DeleteCouponByID())
+
+	giftCard := apiV1.Group("/gift-card")
+	giftCard.Use(auths.Authentication())
+	giftCard.POST("", auths.Authorization(domain.PermissionCheckoutCreate), giftCardHandler.IssueGiftCard())
+	giftCard.GET(":code", auths.Authorization(domain.PermissionCheckoutCreate), giftCardHandler.GetGiftCardByCode())
+	giftCard.PUT(":code", auths.Authorization(domain.PermissionGiftCardManage), giftCardHandler.UpdateGiftCardStatus())
+
+	report := apiV1.Group("/reports")
+	report.Use(auths.Authentication())
+	report.GET("/z", auths.Authorization(domain.PermissionReportRead), reportHandler.GetZReport())
+	report.POST("/z/close", auths.Authorization(domain.PermissionDayClose), reportHandler.CloseZReport())
 
 	customer := apiV1.Group("/customer")
 	customer.Use(auths.Authentication())
-	customer.GET("", userCustomerHandler.GetUserCustomers())
-	customer.POST("/register", userCustomerHandler.CreateUserCustomer())
+	customer.GET("", auths.Authorization(domain.PermissionCustomerRead), userCustomerHandler.GetUserCustomers())
+	customer.POST("/register", auths.Authorization(domain.PermissionCustomerWrite), userCustomerHandler.CreateUserCustomer())
+	customer.GET(":id/points", auths.Authorization(domain.PermissionCustomerRead), pointsHandler.GetPointsBalance())
+	customer.GET(":id/points/ledger", auths.Authorization(domain.PermissionCustomerRead), pointsHandler.GetPointsLedger())
+	customer.GET("/credit/aging", auths.Authorization(domain.PermissionReportRead), creditHandler.GetCreditAging())
+	customer.GET(":id/credit", auths.Authorization(domain.PermissionCustomerRead), creditHandler.GetCreditAccount())
+	customer.PUT(":id/credit", auths.Authorization(domain.PermissionCreditManage), creditHandler.UpdateCreditLimit())
+	customer.POST(":id/credit/payments", auths.Authorization(domain.PermissionCheckoutCreate), creditHandler.CreateCreditPayment())
+	customer.GET(":id/credit/statement", auths.Authorization(domain.PermissionCustomerRead), creditHandler.GetCreditStatement())
 
 	return r
 }
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"eniqilo-store/internal/domain"
+	"eniqilo-store/internal/repository"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type CartService interface {
+	CreateCart(ctx context.Context, body domain.CartRequest) (*domain.CartResponse, domain.MessageErr)
+	GetCarts(ctx context.Context, queryParams domain.CartQueryParams) ([]domain.CartResponse, domain.MessageErr)
+	GetCartByID(ctx context.Context, id string) (*domain.CartResponse, domain.MessageErr)
+	SetCartItem(ctx context.Context, id string, body domain.CartItemRequest) (*domain.CartResponse, domain.MessageErr)
+	RemoveCartItem(ctx context.Context, id string, productID string) (*domain.CartResponse, domain.MessageErr)
+	SetCartCustomer(ctx context.Context, id string, body domain.CartCustomerRequest) (*domain.CartResponse, domain.MessageErr)
+	ParkCart(ctx context.Context, id string, body domain.CartParkRequest) (*domain.CartResponse, domain.MessageErr)
+	ResumeCart(ctx context.Context, id string, body domain.CartResumeRequest) (*domain.CartResponse, domain.MessageErr)
+	CheckoutCart(ctx context.Context, id string, body domain.CartCheckoutRequest) (*domain.StoredResponse, domain.MessageErr)
+	DeleteCartByID(ctx context.Context, id string) domain.MessageErr
+	DeleteExpiredCarts(ctx context.Context) domain.MessageErr
+}
+
+type cartService struct {
+	db                     *sql.DB
+	cartRepository         repository.CartRepository
+	userCustomerRepository repository.UserCustomerRepository
+	checkoutService        CheckoutService
+	cartTTL                time.Duration
+}
+
+func NewCartService(db *sql.DB, cartRepository repository.CartRepository, userCustomerRepository repository.UserCustomerRepository, checkoutService CheckoutService, cartTTL time.Duration) CartService {
+	return &cartService{
+		db:                     db,
+		cartRepository:         cartRepository,
+		userCustomerRepository: userCustomerRepository,
+		checkoutService:        checkoutService,
+		cartTTL:                cartTTL,
+	}
+}
+
+func (cs *cartService) CreateCart(ctx context.Context, body domain.CartRequest) (*domain.CartResponse, domain.MessageErr) {
+	cart := body.NewCart()
+	if cart.UserCustomerID != nil {
+		errMsg := cs.checkCustomerExists(ctx, *cart.UserCustomerID)
+		if errMsg != nil {
+			return nil, errMsg
+		}
+	}
+
+	err := cs.cartRepository.CreateCart(ctx, cs.db, cart)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	response := cart.NewCartResponse(nil)
+
+	return &response, nil
+}
+
+func (cs *cartService) GetCarts(ctx context.Context, queryParams domain.CartQueryParams) ([]domain.CartResponse, domain.MessageErr) {
+	carts, err := cs.cartRepository.GetCarts(ctx, cs.db, queryParams)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	var cartIDs []string
+	for _, c := range carts {
+		cartIDs = append(cartIDs, c.ID)
+	}
+	items, err := cs.cartRepository.GetCartItemsByCartIDs(ctx, cs.db, cartIDs)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	cartResponses := []domain.CartResponse{}
+	for _, c := range carts {
+		cartResponses = append(cartResponses, c.NewCartResponse(items[c.ID]))
+	}
+
+	return cartResponses, nil
+}
+
+func (cs *cartService) GetCartByID(ctx context.Context, id string) (*domain.CartResponse, domain.MessageErr) {
+	cart, err := cs.cartRepository.GetCartByID(ctx, cs.db, id)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if cart == nil {
+		return nil, domain.NewNotFoundError("cart is not found")
+	}
+
+	items, err := cs.cartRepository.GetCartItemsByCartIDs(ctx, cs.db, []string{cart.ID})
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	response := cart.NewCartResponse(items[cart.ID])
+
+	return &response, nil
+}
+
+func (cs *cartService) SetCartItem(ctx context.Context, id string, body domain.CartItemRequest) (*domain.CartResponse, domain.MessageErr) {
+	return cs.updateCart(ctx, id, domain.CartStatusOpen, func(tx *sql.Tx, cart *domain.Cart) domain.MessageErr {
+		err := cs.cartRepository.UpsertCartItem(ctx, tx, body.NewCartItem(cart.ID))
+		if err != nil {
+			if err, ok := err.(*pgconn.PgError); ok {
+				if err.Code == "22P02" || err.Code == "23503" {
+					return domain.NewNotFoundError("productId is not found")
+				}
+			}
+			return domain.NewInternalServerError(err.Error())
+		}
+
+		return nil
+	})
+}
+
+func (cs *cartService) RemoveCartItem(ctx context.Context, id string, productID string) (*domain.CartResponse, domain.MessageErr) {
+	return cs.updateCart(ctx, id, domain.CartStatusOpen, func(tx *sql.Tx, cart *domain.Cart) domain.MessageErr {
+		affRow, err := cs.cartRepository.DeleteCartItem(ctx, tx, cart.ID, productID)
+		if err != nil {
+			return domain.NewInternalServerError(err.Error())
+		}
+		if affRow == 0 {
+			return domain.NewNotFoundError("product is not in the cart")
+		}
+
+		return nil
+	})
+}
+
+func (cs *cartService) SetCartCustomer(ctx context.Context, id string, body domain.CartCustomerRequest) (*domain.CartResponse, domain.MessageErr) {
+	errMsg := cs.checkCustomerExists(ctx, body.CustomerID)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+
+	return cs.updateCart(ctx, id, domain.CartStatusOpen, func(tx *sql.Tx, cart *domain.Cart) domain.MessageErr {
+		cart.UserCustomerID = &body.CustomerID
+
+		return nil
+	})
+}
+
+func (cs *cartService) ParkCart(ctx context.Context, id string, body domain.CartParkRequest) (*domain.CartResponse, domain.MessageErr) {
+	return cs.updateCart(ctx, id, domain.CartStatusOpen, func(tx *sql.Tx, cart *domain.Cart) domain.MessageErr {
+		cart.Status = domain.CartStatusParked
+		if len(body.Label) > 0 {
+			cart.Label = &body.Label
+		}
+
+		return nil
+	})
+}
+
+func (cs *cartService) ResumeCart(ctx context.Context, id string, body domain.CartResumeRequest) (*domain.CartResponse, domain.MessageErr) {
+	return cs.updateCart(ctx, id, domain.CartStatusParked, func(tx *sql.Tx, cart *domain.Cart) domain.MessageErr {
+		cart.Status = domain.CartStatusOpen
+		if len(body.Till) > 0 {
+			cart.Till = body.Till
+		}
+
+		return nil
+	})
+}
+
+func (cs *cartService) CheckoutCart(ctx context.Context, id string, body domain.CartCheckoutRequest) (*domain.StoredResponse, domain.MessageErr) {
+	tx, err := cs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback()
+
+	// held until the cart is marked, so edits and other checkouts of it wait
+	cart, err := cs.cartRepository.GetCartForUpdate(ctx, tx, id)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if cart == nil {
+		return nil, domain.NewNotFoundError("cart is not found")
+	}
+	if cart.Status == domain.CartStatusCheckedOut {
+		return nil, domain.NewConflictError(fmt.Sprintf("cart is already checked out as %s", *cart.CheckoutID))
+	}
+	if cart.Status != domain.CartStatusOpen {
+		return nil, domain.NewBadRequestError("cart is parked, resume it first")
+	}
+	if cart.UserCustomerID == nil {
+		return nil, domain.NewBadRequestError("cart has no customer")
+	}
+
+	items, err := cs.cartRepository.GetCartItemsByCartIDs(ctx, cs.db, []string{cart.ID})
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if len(items[cart.ID]) == 0 {
+		return nil, domain.NewBadRequestError("cart is empty")
+	}
+
+	// the cart's own key makes a retry after the sale went through replay it
+	response, errMsg := cs.checkoutService.CreateCheckout(ctx, cart.NewCheckoutRequest(items[cart.ID], body), cart.IdempotencyKey())
+	if errMsg != nil {
+		return nil, errMsg
+	}
+
+	if response.Status == http.StatusOK {
+		err = cs.cartRepository.CheckOutCart(ctx, tx, cart.ID, response.CheckoutID)
+		if err != nil {
+			return nil, domain.NewInternalServerError(err.Error())
+		}
+
+		err = tx.Commit()
+		if err != nil {
+			return nil, domain.NewInternalServerError(err.Error())
+		}
+	}
+
+	return response, nil
+}
+
+func (cs *cartService) DeleteCartByID(ctx context.Context, id string) domain.MessageErr {
+	affRow, err := cs.cartRepository.DeleteCartByID(ctx, cs.db, id)
+	if err != nil {
+		return domain.NewInternalServerError(err.Error())
+	}
+	if affRow == 0 {
+		return domain.NewNotFoundError("cart is not found or already checked out")
+	}
+
+	return nil
+}
+
+func (cs *cartService) DeleteExpiredCarts(ctx context.Context) domain.MessageErr {
+	_, err := cs.cartRepository.DeleteExpiredCarts(ctx, cs.db, time.Now().Add(-cs.cartTTL))
+	if err != nil {
+		return domain.NewInternalServerError(err.Error())
+	}
+
+	return nil
+}
+
+func (cs *cartService) updateCart(ctx context.Context, id string, status string, update func(tx *sql.Tx, cart *domain.Cart) domain.MessageErr) (*domain.CartResponse, domain.MessageErr) {
+	tx, err := cs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback()
+
+	cart, err := cs.cartRepository.GetCartForUpdate(ctx, tx, id)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if cart == nil {
+		return nil, domain.NewNotFoundError("cart is not found")
+	}
+	if cart.Status != status {
+		return nil, domain.NewBadRequestError(fmt.Sprintf("cart is %s", cart.Status))
+	}
+
+	errMsg := update(tx, cart)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+
+	cart.Touch()
+	err = cs.cartRepository.UpdateCart(ctx, tx, *cart)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	return cs.GetCartByID(ctx, cart.ID)
+}
+
+func (cs *cartService) checkCustomerExists(ctx context.Context, customerID string) domain.MessageErr {
+	ok, err := cs.userCustomerRepository.CheckCustomerExistsByID(ctx, cs.db, customerID)
+	if err != nil {
+		return domain.NewInternalServerError(err.Error())
+	}
+	if !ok {
+		return domain.NewNotFoundError("customerId is not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"eniqilo-store/internal/domain"
+	"eniqilo-store/internal/repository"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCheckoutCart(t *testing.T) {
+	db := newTestDB(t)
+	cs := NewCartService(db, repository.NewCartRepository(), repository.NewUserCustomerRepository(), newTestCheckoutService(db, false), time.Hour)
+	cashier := createTestUserAdmin(t, db, domain.UserAdminRoleCashier)
+
+	tests := []struct {
+		name       string
+		customer   bool
+		items      bool
+		park       bool
+		wantStatus int
+	}{
+		{name: "open cart", customer: true, items: true, wantStatus: http.StatusOK},
+		{name: "parked cart", customer: true, items: true, park: true, wantStatus: http.StatusBadRequest},
+		{name: "no customer", items: true, wantStatus: http.StatusBadRequest},
+		{name: "empty cart", customer: true, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			customer := createTestCustomer(t, db)
+			product := createTestProduct(t, db, 1000, 100)
+			cart, errMsg := cs.CreateCart(ctx, domain.CartRequest{UserAdminID: cashier.ID, Till: "1"})
+			if errMsg != nil {
+				t.Fatalf("CreateCart() error = %v", errMsg.Message())
+			}
+			if tt.customer {
+				_, errMsg = cs.SetCartCustomer(ctx, cart.ID, domain.CartCustomerRequest{CustomerID: customer.ID})
+				if errMsg != nil {
+					t.Fatalf("SetCartCustomer() error = %v", errMsg.Message())
+				}
+			}
+			if tt.items {
+				_, errMsg = cs.SetCartItem(ctx, cart.ID, domain.CartItemRequest{ProductID: product.ID, Quantity: 2})
+				if errMsg != nil {
+					t.Fatalf("SetCartItem() error = %v", errMsg.Message())
+				}
+			}
+			if tt.park {
+				_, errMsg = cs.ParkCart(ctx, cart.ID, domain.CartParkRequest{})
+				if errMsg != nil {
+					t.Fatalf("ParkCart() error = %v", errMsg.Message())
+				}
+			}
+
+			change := 0
+			_, errMsg = cs.CheckoutCart(ctx, cart.ID, domain.CartCheckoutRequest{UserAdminID: cashier.ID, Paid: 2000, Change: &change})
+			status := http.StatusOK
+			if errMsg != nil {
+				status = errMsg.Status()
+			}
+			if status != tt.wantStatus {
+				t.Errorf("CheckoutCart() status = %d, want %d", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestCheckoutCartRetry(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	cs := NewCartService(db, repository.NewCartRepository(), repository.NewUserCustomerRepository(), newTestCheckoutService(db, false), time.Hour)
+	cashier := createTestUserAdmin(t, db, domain.UserAdminRoleCashier)
+	customer := createTestCustomer(t, db)
+	product := createTestProduct(t, db, 1000, 100)
+
+	cart, errMsg := cs.CreateCart(ctx, domain.CartRequest{UserAdminID: cashier.ID, Till: "1", CustomerID: customer.ID})
+	if errMsg != nil {
+		t.Fatalf("CreateCart() error = %v", errMsg.Message())
+	}
+	_, errMsg = cs.SetCartItem(ctx, cart.ID, domain.CartItemRequest{ProductID: product.ID, Quantity: 2})
+	if errMsg != nil {
+		t.Fatalf("SetCartItem() error = %v", errMsg.Message())
+	}
+
+	change := 0
+	body := domain.CartCheckoutRequest{UserAdminID: cashier.ID, Paid: 2000, Change: &change}
+	first, errMsg := cs.CheckoutCart(ctx, cart.ID, body)
+	if errMsg != nil {
+		t.Fatalf("CheckoutCart() error = %v", errMsg.Message())
+	}
+
+	_, errMsg = cs.CheckoutCart(ctx, cart.ID, body)
+	if errMsg == nil || errMsg.Status() != http.StatusConflict {
+		t.Fatalf("CheckoutCart() of a checked out cart = %v, want a conflict", errMsg)
+	}
+
+	// the sale went through but the cart wasn't marked
+	_, err := db.Exec(`UPDATE carts SET status = $1, checkout_id = NULL WHERE id = $2`, domain.CartStatusOpen, cart.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	retry, errMsg := cs.CheckoutCart(ctx, cart.ID, body)
+	if errMsg != nil {
+		t.Fatalf("CheckoutCart() retry error = %v", errMsg.Message())
+	}
+	if retry.CheckoutID != first.CheckoutID {
+		t.Errorf("retry sold again as %s, want %s", retry.CheckoutID, first.CheckoutID)
+	}
+	if stock := getTestProductStock(t, db, product.ID); stock != 98 {
+		t.Errorf("stock = %d, want 98", stock)
+	}
+}
+
+func TestCheckoutCartConcurrent(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	cs := NewCartService(db, repository.NewCartRepository(), repository.NewUserCustomerRepository(), newTestCheckoutService(db, false), time.Hour)
+	customer := createTestCustomer(t, db)
+	product := createTestProduct(t, db, 1000, 100)
+
+	cashier := createTestUserAdmin(t, db, domain.UserAdminRoleCashier)
+	cart, errMsg := cs.CreateCart(ctx, domain.CartRequest{UserAdminID: cashier.ID, Till: "1", CustomerID: customer.ID})
+	if errMsg != nil {
+		t.Fatalf("CreateCart() error = %v", errMsg.Message())
+	}
+	_, errMsg = cs.SetCartItem(ctx, cart.ID, domain.CartItemRequest{ProductID: product.ID, Quantity: 2})
+	if errMsg != nil {
+		t.Fatalf("SetCartItem() error = %v", errMsg.Message())
+	}
+
+	cashiers := []domain.UserAdmin{cashier}
+	for range 4 {
+		cashiers = append(cashiers, createTestUserAdmin(t, db, domain.UserAdminRoleCashier))
+	}
+
+	var wg sync.WaitGroup
+	var checkedOut, conflicts atomic.Int32
+	for _, c := range cashiers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			change := 0
+			_, errMsg := cs.CheckoutCart(ctx, cart.ID, domain.CartCheckoutRequest{UserAdminID: c.ID, Paid: 2000, Change: &change})
+			switch {
+			case errMsg == nil:
+				checkedOut.Add(1)
+			case errMsg.Status() == http.StatusConflict:
+				conflicts.Add(1)
+			default:
+				t.Errorf("CheckoutCart() error = %v", errMsg.Message())
+			}
+		}()
+	}
+	wg.Wait()
+
+	if checkedOut.Load() != 1 || conflicts.Load() != 4 {
+		t.Errorf("checked out %d times with %d conflicts, want 1 and 4", checkedOut.Load(), conflicts.Load())
+	}
+	if stock := getTestProductStock(t, db, product.ID); stock != 98 {
+		t.Errorf("stock = %d, want 98", stock)
+	}
+}
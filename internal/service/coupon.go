@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"eniqilo-store/internal/domain"
+	"eniqilo-store/internal/repository"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type CouponService interface {
+	CreateCoupon(ctx context.Context, coupon domain.Coupon) domain.MessageErr
+	GetCoupons(ctx context.Context) ([]domain.CouponResponse, domain.MessageErr)
+	UpdateCouponByID(ctx context.Context, coupon domain.Coupon) domain.MessageErr
+	DeleteCouponByID(ctx context.Context, id string) domain.MessageErr
+	GetCouponReport(ctx context.Context, queryParams domain.CouponReportQueryParams) ([]domain.CouponReport, domain.MessageErr)
+}
+
+type couponService struct {
+	db               *sql.DB
+	couponRepository repository.CouponRepository
+	storeLocation    *time.Location
+}
+
+func NewCouponService(db *sql.DB, couponRepository repository.CouponRepository, storeLocation *time.Location) CouponService {
+	return &couponService{
+		db:               db,
+		couponRepository: couponRepository,
+		storeLocation:    storeLocation,
+	}
+}
+
+func (cs *couponService) CreateCoupon(ctx context.Context, coupon domain.Coupon) domain.MessageErr {
+	if msg := coupon.Validate(); len(msg) > 0 {
+		return domain.NewBadRequestError(msg)
+	}
+
+	err := cs.couponRepository.CreateCoupon(ctx, cs.db, coupon)
+	if err != nil {
+		return couponWriteError(err)
+	}
+
+	return nil
+}
+
+func (cs *couponService) GetCoupons(ctx context.Context) ([]domain.CouponResponse, domain.MessageErr) {
+	coupons, err := cs.couponRepository.GetCoupons(ctx, cs.db)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	couponResponses := []domain.CouponResponse{}
+	for _, c := range coupons {
+		couponResponses = append(couponResponses, c.NewCouponResponse())
+	}
+
+	return couponResponses, nil
+}
+
+func (cs *couponService) UpdateCouponByID(ctx context.Context, coupon domain.Coupon) domain.MessageErr {
+	if msg := coupon.Validate(); len(msg) > 0 {
+		return domain.NewBadRequestError(msg)
+	}
+
+	affRow, err := cs.couponRepository.UpdateCouponByID(ctx, cs.db, coupon)
+	if err != nil {
+		return couponWriteError(err)
+	}
+	if affRow == 0 {
+		return domain.NewNotFoundError("coupon is not found")
+	}
+
+	return nil
+}
+
+func (cs *couponService) DeleteCouponByID(ctx context.Context, id string) domain.MessageErr {
+	affRow, err := cs.couponRepository.DeleteCouponByID(ctx, cs.db, id)
+	if err != nil {
+		if err, ok := err.(*pgconn.PgError); ok {
+			if err.Code == "23503" {
+				return domain.NewConflictError("coupon has been redeemed, deactivate it instead")
+			}
+		}
+
+		return domain.NewInternalServerError(err.Error())
+	}
+	if affRow == 0 {
+		return domain.NewNotFoundError("coupon is not found")
+	}
+
+	return nil
+}
+
+func (cs *couponService) GetCouponReport(ctx context.Context, queryParams domain.CouponReportQueryParams) ([]domain.CouponReport, domain.MessageErr) {
+	reports, err := cs.couponRepository.GetCouponReport(ctx, cs.db, queryParams, cs.storeLocation)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	return reports, nil
+}
+
+func couponWriteError(err error) domain.MessageErr {
+	if err, ok := err.(*pgconn.PgError); ok {
+		if err.Code == "23505" {
+			return domain.NewConflictError("coupon code already exists")
+		}
+	}
+
+	return domain.NewInternalServerError(err.Error())
+}
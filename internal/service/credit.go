@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"eniqilo-store/internal/domain"
+	"eniqilo-store/internal/repository"
+	"fmt"
+	"time"
+)
+
+type CreditService interface {
+	GetCreditAccount(ctx context.Context, customerID string) (*domain.CreditAccountResponse, domain.MessageErr)
+	UpdateCreditLimit(ctx context.Context, customerID string, body domain.CreditLimitRequest) (*domain.CreditAccountResponse, domain.MessageErr)
+	CreateCreditPayment(ctx context.Context, customerID string, body domain.CreditPaymentRequest) (*domain.CreditAccountResponse, domain.MessageErr)
+	GetCreditStatement(ctx context.Context, customerID string, queryParams domain.CreditStatementQueryParams) (*domain.CreditStatementResponse, domain.MessageErr)
+	GetCreditAging(ctx context.Context) ([]domain.CreditAging, domain.MessageErr)
+}
+
+type creditService struct {
+	db                     *sql.DB
+	creditRepository       repository.CreditRepository
+	userCustomerRepository repository.UserCustomerRepository
+	shiftRepository        repository.ShiftRepository
+	storeLocation          *time.Location
+	shiftsEnabled          bool
+}
+
+func NewCreditService(db *sql.DB, creditRepository repository.CreditRepository, userCustomerRepository repository.UserCustomerRepository, shiftRepository repository.ShiftRepository, storeLocation *time.Location, shiftsEnabled bool) CreditService {
+	return &creditService{
+		db:                     db,
+		creditRepository:       creditRepository,
+		userCustomerRepository: userCustomerRepository,
+		shiftRepository:        shiftRepository,
+		storeLocation:          storeLocation,
+		shiftsEnabled:          shiftsEnabled,
+	}
+}
+
+func (cs *creditService) GetCreditAccount(ctx context.Context, customerID string) (*domain.CreditAccountResponse, domain.MessageErr) {
+	account, err := cs.creditRepository.GetCreditAccount(ctx, cs.db, customerID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if account == nil {
+		return nil, domain.NewNotFoundError("customer is not found")
+	}
+
+	return account, nil
+}
+
+func (cs *creditService) UpdateCreditLimit(ctx context.Context, customerID string, body domain.CreditLimitRequest) (*domain.CreditAccountResponse, domain.MessageErr) {
+	affRow, err := cs.creditRepository.UpdateCreditLimit(ctx, cs.db, customerID, *body.CreditLimit)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if affRow == 0 {
+		return nil, domain.NewNotFoundError("customer is not found")
+	}
+
+	return cs.GetCreditAccount(ctx, customerID)
+}
+
+func (cs *creditService) CreateCreditPayment(ctx context.Context, customerID string, body domain.CreditPaymentRequest) (*domain.CreditAccountResponse, domain.MessageErr) {
+	ok, err := cs.userCustomerRepository.CheckCustomerExistsByID(ctx, cs.db, customerID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if !ok {
+		return nil, domain.NewNotFoundError("customer is not found")
+	}
+
+	tx, err := cs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback()
+
+	entry := body.NewCreditEntry(customerID)
+	shiftID, errMsg := openShiftID(ctx, tx, cs.shiftRepository, body.UserAdminID, cs.shiftsEnabled)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	entry.ShiftID = shiftID
+
+	err = cs.userCustomerRepository.LockCustomerByID(ctx, tx, customerID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	balance, err := cs.creditRepository.GetCreditBalance(ctx, tx, customerID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if body.Amount > balance {
+		return nil, domain.NewBadRequestError(fmt.Sprintf("amount exceeds the outstanding balance of %d", max(balance, 0)))
+	}
+
+	err = cs.creditRepository.BulkCreateCreditEntry(ctx, tx, []domain.CreditEntry{entry})
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	return cs.GetCreditAccount(ctx, customerID)
+}
+
+func (cs *creditService) GetCreditStatement(ctx context.Context, customerID string, queryParams domain.CreditStatementQueryParams) (*domain.CreditStatementResponse, domain.MessageErr) {
+	ok, err := cs.userCustomerRepository.CheckCustomerExistsByID(ctx, cs.db, customerID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if !ok {
+		return nil, domain.NewNotFoundError("customer is not found")
+	}
+
+	openingBalance, entries, err := cs.creditRepository.GetCreditStatement(ctx, cs.db, customerID, queryParams, cs.storeLocation)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	entryResponses := domain.NewCreditEntryResponses(entries, openingBalance)
+	statement := domain.CreditStatementResponse{
+		CustomerID:     customerID,
+		OpeningBalance: openingBalance,
+		ClosingBalance: openingBalance,
+		Entries:        entryResponses,
+	}
+	if len(entryResponses) > 0 {
+		statement.ClosingBalance = entryResponses[len(entryResponses)-1].Balance
+	}
+
+	return &statement, nil
+}
+
+func (cs *creditService) GetCreditAging(ctx context.Context) ([]domain.CreditAging, domain.MessageErr) {
+	accounts, err := cs.creditRepository.GetOutstandingCreditEntries(ctx, cs.db)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	now := time.Now()
+	agings := []domain.CreditAging{}
+	for _, a := range accounts {
+		agings = append(agings, domain.NewCreditAging(a.CustomerID, a.CustomerName, a.Entries, now))
+	}
+
+	return agings, nil
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"eniqilo-store/internal/domain"
+	"eniqilo-store/internal/repository"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCreateCheckoutCreditLimit(t *testing.T) {
+	db := newTestDB(t)
+	cs := newTestCheckoutService(db, false)
+	crs := NewCreditService(db, repository.NewCreditRepository(), repository.NewUserCustomerRepository(), repository.NewShiftRepository(), time.UTC, false)
+
+	tests := []struct {
+		name          string
+		chargedBefore bool
+		paidBefore    int
+		wantStatus    int
+		wantBalance   int
+	}{
+		{name: "within the limit", wantStatus: http.StatusOK, wantBalance: 1000},
+		{name: "over the limit", chargedBefore: true, wantStatus: http.StatusBadRequest, wantBalance: 1000},
+		{name: "payment frees up credit", chargedBefore: true, paidBefore: 500, wantStatus: http.StatusOK, wantBalance: 1500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			cashier := createTestUserAdmin(t, db, domain.UserAdminRoleCashier)
+			customer := createTestCustomer(t, db)
+			product := createTestProduct(t, db, 1000, 100)
+
+			creditLimit := 1500
+			_, errMsg := crs.UpdateCreditLimit(ctx, customer.ID, domain.CreditLimitRequest{CreditLimit: &creditLimit})
+			if errMsg != nil {
+				t.Fatalf("UpdateCreditLimit() error = %v", errMsg.Message())
+			}
+
+			checkout := func() domain.MessageErr {
+				change := 0
+				_, errMsg := cs.CreateCheckout(ctx, domain.CheckoutRequest{
+					UserAdminID:    cashier.ID,
+					CustomerID:     customer.ID,
+					ProductDetails: []domain.ProductCheckoutRequest{{ProductID: product.ID, Quantity: 1}},
+					Payments:       []domain.PaymentRequest{{Method: domain.PaymentMethodStoreCredit, Amount: 1000}},
+					Change:         &change,
+				}, "")
+				return errMsg
+			}
+
+			if tt.chargedBefore {
+				errMsg := checkout()
+				if errMsg != nil {
+					t.Fatalf("CreateCheckout() error = %v", errMsg.Message())
+				}
+			}
+			if tt.paidBefore > 0 {
+				_, errMsg := crs.CreateCreditPayment(ctx, customer.ID, domain.CreditPaymentRequest{
+					UserAdminID: cashier.ID,
+					Method:      domain.PaymentMethodCash,
+					Amount:      tt.paidBefore,
+				})
+				if errMsg != nil {
+					t.Fatalf("CreateCreditPayment() error = %v", errMsg.Message())
+				}
+			}
+
+			errMsg = checkout()
+			status := http.StatusOK
+			if errMsg != nil {
+				status = errMsg.Status()
+			}
+			if status != tt.wantStatus {
+				t.Fatalf("CreateCheckout() status = %d, want %d", status, tt.wantStatus)
+			}
+
+			account, errMsg := crs.GetCreditAccount(ctx, customer.ID)
+			if errMsg != nil {
+				t.Fatalf("GetCreditAccount() error = %v", errMsg.Message())
+			}
+			if account.Balance != tt.wantBalance || account.Available != creditLimit-tt.wantBalance {
+				t.Errorf("balance %d, available %d, want %d, %d", account.Balance, account.Available, tt.wantBalance, creditLimit-tt.wantBalance)
+			}
+		})
+	}
+}
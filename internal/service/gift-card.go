@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"eniqilo-store/internal/domain"
+	"eniqilo-store/internal/repository"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type GiftCardService interface {
+	IssueGiftCard(ctx context.Context, body domain.GiftCardRequest) (*domain.GiftCardResponse, domain.MessageErr)
+	GetGiftCardByCode(ctx context.Context, code string) (*domain.GiftCardResponse, domain.MessageErr)
+	UpdateGiftCardStatus(ctx context.Context, code string, body domain.GiftCardStatusRequest) (*domain.GiftCardResponse, domain.MessageErr)
+}
+
+type giftCardService struct {
+	db                     *sql.DB
+	giftCardRepository     repository.GiftCardRepository
+	checkoutRepository     repository.CheckoutRepository
+	paymentRepository      repository.PaymentRepository
+	userCustomerRepository repository.UserCustomerRepository
+	shiftRepository        repository.ShiftRepository
+	shiftsEnabled          bool
+}
+
+func NewGiftCardService(db *sql.DB, giftCardRepository repository.GiftCardRepository, checkoutRepository repository.CheckoutRepository, paymentRepository repository.PaymentRepository, userCustomerRepository repository.UserCustomerRepository, shiftRepository repository.ShiftRepository, shiftsEnabled bool) GiftCardService {
+	return &giftCardService{
+		db:                     db,
+		giftCardRepository:     giftCardRepository,
+		checkoutRepository:     checkoutRepository,
+		paymentRepository:      paymentRepository,
+		userCustomerRepository: userCustomerRepository,
+		shiftRepository:        shiftRepository,
+		shiftsEnabled:          shiftsEnabled,
+	}
+}
+
+func (gs *giftCardService) IssueGiftCard(ctx context.Context, body domain.GiftCardRequest) (*domain.GiftCardResponse, domain.MessageErr) {
+	for _, p := range body.Payments {
+		if !slices.Contains(domain.GiftCardIssueMethods, p.Method) {
+			return nil, domain.NewBadRequestError(fmt.Sprintf("a gift card cannot be paid with %s", p.Method))
+		}
+	}
+
+	change, errMsg := calculateChange(body.Payments, body.Amount)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	if *body.Change != change {
+		return nil, domain.NewBadRequestError(fmt.Sprintf("change is incorrect should be %d", change))
+	}
+
+	ok, err := gs.userCustomerRepository.CheckCustomerExistsByID(ctx, gs.db, body.CustomerID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if !ok {
+		return nil, domain.NewNotFoundError("customerId is not found")
+	}
+
+	giftCard, checkout, payments, err := body.NewGiftCard()
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	tx, err := gs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback()
+
+	checkout.ShiftID, errMsg = openShiftID(ctx, tx, gs.shiftRepository, checkout.UserAdminID, gs.shiftsEnabled)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+
+	err = gs.checkoutRepository.CreateCheckout(ctx, tx, checkout, nil)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	err = gs.paymentRepository.BulkCreatePayment(ctx, tx, payments)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	err = gs.giftCardRepository.CreateGiftCard(ctx, tx, giftCard)
+	if err != nil {
+		if err, ok := err.(*pgconn.PgError); ok {
+			if err.Code == "23505" {
+				return nil, domain.NewConflictError("gift card code already exists")
+			}
+		}
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	issue := domain.NewGiftCardEntry(giftCard.ID, &checkout.ID, domain.GiftCardEntryIssue, giftCard.InitialBalance, giftCard.CreatedAt)
+	err = gs.giftCardRepository.BulkCreateGiftCardEntry(ctx, tx, []domain.GiftCardEntry{issue})
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	response := giftCard.NewGiftCardResponse(giftCard.InitialBalance)
+
+	return &response, nil
+}
+
+func (gs *giftCardService) GetGiftCardByCode(ctx context.Context, code string) (*domain.GiftCardResponse, domain.MessageErr) {
+	giftCard, err := gs.giftCardRepository.GetGiftCardByCode(ctx, gs.db, domain.NormalizeGiftCardCode(code))
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if giftCard == nil {
+		return nil, domain.NewNotFoundError("gift card is not found")
+	}
+
+	balance, err := gs.giftCardRepository.GetGiftCardBalance(ctx, gs.db, giftCard.ID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	response := giftCard.NewGiftCardResponse(balance)
+
+	return &response, nil
+}
+
+func (gs *giftCardService) UpdateGiftCardStatus(ctx context.Context, code string, body domain.GiftCardStatusRequest) (*domain.GiftCardResponse, domain.MessageErr) {
+	affRow, err := gs.giftCardRepository.UpdateGiftCardStatusByCode(ctx, gs.db, domain.NormalizeGiftCardCode(code), body.Status, body.ExpiresAt)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if affRow == 0 {
+		return nil, domain.NewNotFoundError("gift card is not found")
+	}
+
+	return gs.GetGiftCardByCode(ctx, code)
+}
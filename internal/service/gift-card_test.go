@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"eniqilo-store/internal/domain"
+	"eniqilo-store/internal/repository"
+	"net/http"
+	"testing"
+)
+
+func TestCreateCheckoutGiftCardBalance(t *testing.T) {
+	db := newTestDB(t)
+	cs := newTestCheckoutService(db, false)
+	gs := NewGiftCardService(db, repository.NewGiftCardRepository(), repository.NewCheckoutRepository(), repository.NewPaymentRepository(), repository.NewUserCustomerRepository(), repository.NewShiftRepository(), false)
+
+	tests := []struct {
+		name        string
+		spentBefore bool
+		voidBefore  bool
+		status      string
+		wantStatus  int
+		wantBalance int
+	}{
+		{name: "within the balance", wantStatus: http.StatusOK, wantBalance: 400},
+		{name: "over the balance", spentBefore: true, wantStatus: http.StatusBadRequest, wantBalance: 400},
+		{name: "void puts the balance back", spentBefore: true, voidBefore: true, wantStatus: http.StatusOK, wantBalance: 400},
+		{name: "frozen card", status: domain.GiftCardStatusFrozen, wantStatus: http.StatusBadRequest, wantBalance: 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			cashier := createTestUserAdmin(t, db, domain.UserAdminRoleCashier)
+			customer := createTestCustomer(t, db)
+			product := createTestProduct(t, db, 600, 100)
+
+			change := 0
+			giftCard, errMsg := gs.IssueGiftCard(ctx, domain.GiftCardRequest{
+				UserAdminID: cashier.ID,
+				CustomerID:  customer.ID,
+				Amount:      1000,
+				Payments:    []domain.PaymentRequest{{Method: domain.PaymentMethodCash, Amount: 1000}},
+				Change:      &change,
+			})
+			if errMsg != nil {
+				t.Fatalf("IssueGiftCard() error = %v", errMsg.Message())
+			}
+
+			checkout := func() (*domain.StoredResponse, domain.MessageErr) {
+				change := 0
+				return cs.CreateCheckout(ctx, domain.CheckoutRequest{
+					UserAdminID:    cashier.ID,
+					CustomerID:     customer.ID,
+					ProductDetails: []domain.ProductCheckoutRequest{{ProductID: product.ID, Quantity: 1}},
+					Payments:       []domain.PaymentRequest{{Method: domain.PaymentMethodGiftCard, Amount: 600, GiftCardCode: giftCard.Code}},
+					Change:         &change,
+				}, "")
+			}
+
+			if tt.spentBefore {
+				before, errMsg := checkout()
+				if errMsg != nil {
+					t.Fatalf("CreateCheckout() error = %v", errMsg.Message())
+				}
+				if tt.voidBefore {
+					_, errMsg := cs.VoidCheckout(ctx, before.CheckoutID, domain.VoidRequest{
+						UserAdminID:   cashier.ID,
+						UserAdminRole: cashier.Role,
+						Reason:        "test",
+					})
+					if errMsg != nil {
+						t.Fatalf("VoidCheckout() error = %v", errMsg.Message())
+					}
+				}
+			}
+			if len(tt.status) > 0 {
+				_, errMsg := gs.UpdateGiftCardStatus(ctx, giftCard.Code, domain.GiftCardStatusRequest{Status: tt.status})
+				if errMsg != nil {
+					t.Fatalf("UpdateGiftCardStatus() error = %v", errMsg.Message())
+				}
+			}
+
+			_, errMsg = checkout()
+			status := http.StatusOK
+			if errMsg != nil {
+				status = errMsg.Status()
+			}
+			if status != tt.wantStatus {
+				t.Fatalf("CreateCheckout() status = %d, want %d", status, tt.wantStatus)
+			}
+
+			current, errMsg := gs.GetGiftCardByCode(ctx, giftCard.Code)
+			if errMsg != nil {
+				t.Fatalf("GetGiftCardByCode() error = %v", errMsg.Message())
+			}
+			if current.Balance != tt.wantBalance {
+				t.Errorf("balance = %d, want %d", current.Balance, tt.wantBalance)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"eniqilo-store/internal/domain"
+	"eniqilo-store/internal/repository"
+)
+
+type PointsService interface {
+	GetPointsBalance(ctx context.Context, customerID string) (*domain.PointsBalanceResponse, domain.MessageErr)
+	GetPointsLedger(ctx context.Context, customerID string, queryParams domain.PointsLedgerQueryParams) ([]domain.PointsEntryResponse, *domain.PaginationMeta, domain.MessageErr)
+}
+
+type pointsService struct {
+	db                     *sql.DB
+	pointsRepository       repository.PointsRepository
+	userCustomerRepository repository.UserCustomerRepository
+}
+
+func NewPointsService(db *sql.DB, pointsRepository repository.PointsRepository, userCustomerRepository repository.UserCustomerRepository) PointsService {
+	return &pointsService{
+		db:                     db,
+		pointsRepository:       pointsRepository,
+		userCustomerRepository: userCustomerRepository,
+	}
+}
+
+func (ps *pointsService) GetPointsBalance(ctx context.Context, customerID string) (*domain.PointsBalanceResponse, domain.MessageErr) {
+	errMsg := ps.checkCustomerExists(ctx, customerID)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+
+	_, balance, err := ps.pointsRepository.CountPointsLedger(ctx, ps.db, customerID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	return &domain.PointsBalanceResponse{
+		CustomerID: customerID,
+		Balance:    balance,
+	}, nil
+}
+
+func (ps *pointsService) GetPointsLedger(ctx context.Context, customerID string, queryParams domain.PointsLedgerQueryParams) ([]domain.PointsEntryResponse, *domain.PaginationMeta, domain.MessageErr) {
+	errMsg := ps.checkCustomerExists(ctx, customerID)
+	if errMsg != nil {
+		return nil, nil, errMsg
+	}
+
+	limit, offset := queryParams.LimitOffset()
+	entries, err := ps.pointsRepository.GetPointsLedger(ctx, ps.db, customerID, limit, offset)
+	if err != nil {
+		return nil, nil, domain.NewInternalServerError(err.Error())
+	}
+	total, _, err := ps.pointsRepository.CountPointsLedger(ctx, ps.db, customerID)
+	if err != nil {
+		return nil, nil, domain.NewInternalServerError(err.Error())
+	}
+
+	entryResponses := []domain.PointsEntryResponse{}
+	for _, e := range entries {
+		entryResponses = append(entryResponses, e.NewPointsEntryResponse())
+	}
+	meta := domain.PaginationMeta{
+		Limit:  limit,
+		Offset: offset,
+		Total:  total,
+	}
+
+	return entryResponses, &meta, nil
+}
+
+func (ps *pointsService) checkCustomerExists(ctx context.Context, customerID string) domain.MessageErr {
+	ok, err := ps.userCustomerRepository.CheckCustomerExistsByID(ctx, ps.db, customerID)
+	if err != nil {
+		return domain.NewInternalServerError(err.Error())
+	}
+	if !ok {
+		return domain.NewNotFoundError("customer is not found")
+	}
+
+	return nil
+}
@@ -2,52 +2,702 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
 	"eniqilo-store/internal/domain"
 	"eniqilo-store/internal/repository"
 	"fmt"
+	"net/http"
+	"strings"
+	"time"
 )
 
 type CheckoutService interface {
-	CreateCheckout(ctx context.Context, body domain.CheckoutRequest) domain.MessageErr
-	GetCheckoutHistory(ctx context.Context, queryParams domain.CheckoutHistoryQueryParams) ([]domain.GetCheckoutHistoryResponse, domain.MessageErr)
+	CreateCheckout(ctx context.Context, body domain.CheckoutRequest, idempotencyKey string) (*domain.StoredResponse, domain.MessageErr)
+	QuoteCheckout(ctx context.Context, body domain.CheckoutQuoteRequest) (*domain.CheckoutQuoteResponse, domain.MessageErr)
+	CreateReturn(ctx context.Context, checkoutID string, body domain.ReturnRequest) (*domain.CheckoutResponse, domain.MessageErr)
+	VoidCheckout(ctx context.Context, checkoutID string, body domain.VoidRequest) (*domain.CheckoutResponse, domain.MessageErr)
+	GetCheckoutHistory(ctx context.Context, queryParams domain.CheckoutHistoryQueryParams) ([]domain.CheckoutResponse, *domain.PaginationMeta, domain.MessageErr)
+	GetCheckoutByID(ctx context.Context, id string) (*domain.CheckoutResponse, domain.MessageErr)
+	GetCashierSalesSummary(ctx context.Context, queryParams domain.CheckoutSummaryQueryParams) ([]domain.CashierSalesSummary, domain.MessageErr)
+	DeleteExpiredIdempotencyKeys(ctx context.Context) domain.MessageErr
 }
 
 type checkoutService struct {
-	db                     *sql.DB
-	checkoutRepository     repository.CheckoutRepository
-	userCustomerRepository repository.UserCustomerRepository
-	productRepository      repository.ProductRepository
+	db                       *sql.DB
+	checkoutRepository       repository.CheckoutRepository
+	userCustomerRepository   repository.UserCustomerRepository
+	productRepository        repository.ProductRepository
+	idempotencyKeyRepository repository.IdempotencyKeyRepository
+	paymentRepository        repository.PaymentRepository
+	taxRateRepository        repository.TaxRateRepository
+	promotionRepository      repository.PromotionRepository
+	couponRepository         repository.CouponRepository
+	pointsRepository         repository.PointsRepository
+	creditRepository         repository.CreditRepository
+	giftCardRepository       repository.GiftCardRepository
+	shiftRepository          repository.ShiftRepository
+	idempotencyKeyTTL        time.Duration
+	voidWindow               time.Duration
+	storeLocation            *time.Location
+	pointsEarnRate           float64
+	shiftsEnabled            bool
 }
 
-func NewCheckoutService(db *sql.DB, checkoutRepository repository.CheckoutRepository, userCustomerRepository repository.UserCustomerRepository, productRepository repository.ProductRepository) CheckoutService {
+func NewCheckoutService(db *sql.DB, checkoutRepository repository.CheckoutRepository, userCustomerRepository repository.UserCustomerRepository, productRepository repository.ProductRepository, idempotencyKeyRepository repository.IdempotencyKeyRepository, paymentRepository repository.PaymentRepository, taxRateRepository repository.TaxRateRepository, promotionRepository repository.PromotionRepository, couponRepository repository.CouponRepository, pointsRepository repository.PointsRepository, creditRepository repository.CreditRepository, giftCardRepository repository.GiftCardRepository, shiftRepository repository.ShiftRepository, idempotencyKeyTTL time.Duration, voidWindow time.Duration, storeLocation *time.Location, pointsEarnRate float64, shiftsEnabled bool) CheckoutService {
 	return &checkoutService{
-		db:                     db,
-		checkoutRepository:     checkoutRepository,
-		userCustomerRepository: userCustomerRepository,
-		productRepository:      productRepository,
+		db:                       db,
+		checkoutRepository:       checkoutRepository,
+		userCustomerRepository:   userCustomerRepository,
+		productRepository:        productRepository,
+		idempotencyKeyRepository: idempotencyKeyRepository,
+		paymentRepository:        paymentRepository,
+		taxRateRepository:        taxRateRepository,
+		promotionRepository:      promotionRepository,
+		couponRepository:         couponRepository,
+		pointsRepository:         pointsRepository,
+		creditRepository:         creditRepository,
+		giftCardRepository:       giftCardRepository,
+		shiftRepository:          shiftRepository,
+		idempotencyKeyTTL:        idempotencyKeyTTL,
+		voidWindow:               voidWindow,
+		storeLocation:            storeLocation,
+		pointsEarnRate:           pointsEarnRate,
+		shiftsEnabled:            shiftsEnabled,
 	}
 }
 
-func (cs *checkoutService) CreateCheckout(ctx context.Context, body domain.CheckoutRequest) domain.MessageErr {
-	checkout, productCheckouts := body.NewCheckouts()
+func (cs *checkoutService) CreateCheckout(ctx context.Context, body domain.CheckoutRequest, idempotencyKey string) (*domain.StoredResponse, domain.MessageErr) {
+	var requestHash string
+	if len(idempotencyKey) > 0 {
+		rawBody, err := json.Marshal(body)
+		if err != nil {
+			return nil, domain.NewInternalServerError(err.Error())
+		}
+		hash := sha256.Sum256(rawBody)
+		requestHash = hex.EncodeToString(hash[:])
+
+		storedResponse, errMsg := cs.getStoredResponse(ctx, body.UserAdminID, idempotencyKey, requestHash)
+		if errMsg != nil {
+			return nil, errMsg
+		}
+		if storedResponse != nil {
+			return storedResponse, nil
+		}
+	}
+
+	response, errMsg := cs.createCheckout(ctx, body, idempotencyKey, requestHash)
+	// cart keys only replay sales, a failed cart checkout can be fixed and sent again
+	if errMsg != nil && errMsg.Status() < http.StatusInternalServerError && strings.HasPrefix(idempotencyKey, domain.ClientIdempotencyKeyPrefix) {
+		return cs.storeFailedResponse(ctx, body.UserAdminID, idempotencyKey, requestHash, errMsg)
+	}
+
+	return response, errMsg
+}
+
+func (cs *checkoutService) createCheckout(ctx context.Context, body domain.CheckoutRequest, idempotencyKey string, requestHash string) (*domain.StoredResponse, domain.MessageErr) {
+	if len(body.Payments) == 0 && body.Paid == 0 {
+		return nil, domain.NewBadRequestError("paid is required")
+	}
+
+	checkout, productCheckouts, payments := body.NewCheckouts()
+	if body.Paid != 0 && body.Paid != checkout.Paid {
+		return nil, domain.NewBadRequestError(fmt.Sprintf("paid should equal the sum of payments %d", checkout.Paid))
+	}
 
 	ok, err := cs.userCustomerRepository.CheckCustomerExistsByID(ctx, cs.db, checkout.UserCustomerID)
 	if err != nil {
-		return domain.NewInternalServerError(err.Error())
+		return nil, domain.NewInternalServerError(err.Error())
 	}
 	if !ok {
-		return domain.NewNotFoundError("customerId is not found")
+		return nil, domain.NewNotFoundError("customerId is not found")
+	}
+
+	tx, err := cs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback()
+
+	shiftID, errMsg := openShiftID(ctx, tx, cs.shiftRepository, checkout.UserAdminID, cs.shiftsEnabled)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	checkout.ShiftID = shiftID
+
+	var coupon *domain.Coupon
+	if len(body.CouponCode) > 0 {
+		coupon, errMsg = cs.getRedeemableCoupon(ctx, tx, body.CouponCode, checkout.UserCustomerID, true)
+		if errMsg != nil {
+			return nil, errMsg
+		}
+	}
+
+	errMsg = cs.priceProductCheckouts(ctx, tx, productCheckouts, coupon, true)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	checkout.Subtotal, checkout.Discount, checkout.Tax, checkout.Total = domain.SumProductCheckouts(productCheckouts)
+	change, errMsg := calculateChange(body.PaymentRequests(), checkout.Total)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	if *checkout.Change != change {
+		return nil, domain.NewBadRequestError(fmt.Sprintf("change is incorrect should be %d", change))
+	}
+
+	redeemedPoints := domain.PaymentsAmount(payments, domain.PaymentMethodPoints)
+	creditCharge := domain.PaymentsAmount(payments, domain.PaymentMethodStoreCredit)
+	if redeemedPoints > 0 || creditCharge > 0 {
+		err = cs.userCustomerRepository.LockCustomerByID(ctx, tx, checkout.UserCustomerID)
+		if err != nil {
+			return nil, domain.NewInternalServerError(err.Error())
+		}
+	}
+	if redeemedPoints > 0 {
+		balance, err := cs.pointsRepository.GetPointsBalance(ctx, tx, checkout.UserCustomerID)
+		if err != nil {
+			return nil, domain.NewInternalServerError(err.Error())
+		}
+		if balance < redeemedPoints {
+			return nil, domain.NewBadRequestError(fmt.Sprintf("not enough points, balance is %d", balance))
+		}
+	}
+	if creditCharge > 0 {
+		creditLimit, err := cs.creditRepository.GetCreditLimit(ctx, tx, checkout.UserCustomerID)
+		if err != nil {
+			return nil, domain.NewInternalServerError(err.Error())
+		}
+		balance, err := cs.creditRepository.GetCreditBalance(ctx, tx, checkout.UserCustomerID)
+		if err != nil {
+			return nil, domain.NewInternalServerError(err.Error())
+		}
+		available := domain.CreditAvailable(creditLimit, balance)
+		if creditCharge > available {
+			return nil, domain.NewBadRequestError(fmt.Sprintf("store credit exceeds the available credit of %d", available))
+		}
+	}
+
+	errMsg = cs.redeemGiftCards(ctx, tx, payments)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+
+	err = cs.checkoutRepository.CreateCheckout(ctx, tx, checkout, productCheckouts)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	err = cs.checkoutRepository.BulkCreateProductCheckout(ctx, tx, productCheckouts)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	var appliedPromotions []domain.AppliedPromotion
+	for _, pc := range productCheckouts {
+		appliedPromotions = append(appliedPromotions, pc.Promotions...)
+	}
+	err = cs.promotionRepository.BulkCreateAppliedPromotion(ctx, tx, appliedPromotions)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	if coupon != nil {
+		redemption := domain.NewCouponRedemption(*coupon, checkout, domain.CouponDiscount(productCheckouts))
+		err = cs.couponRepository.CreateCouponRedemption(ctx, tx, redemption)
+		if err != nil {
+			return nil, domain.NewInternalServerError(err.Error())
+		}
+	}
+
+	err = cs.paymentRepository.BulkCreatePayment(ctx, tx, payments)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	err = cs.pointsRepository.BulkCreatePointsEntry(ctx, tx, domain.NewSalePointsEntries(checkout, payments, cs.pointsEarnRate))
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	err = cs.creditRepository.BulkCreateCreditEntry(ctx, tx, domain.NewCheckoutCreditEntries(checkout, payments))
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	err = cs.giftCardRepository.BulkCreateGiftCardEntry(ctx, tx, domain.NewGiftCardPaymentEntries(checkout, payments))
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	for _, pc := range productCheckouts {
+		affRow, err := cs.productRepository.UpdateProductStockByID(ctx, tx, pc.ProductID, pc.Quantity)
+		if err != nil {
+			return nil, domain.NewInternalServerError(err.Error())
+		}
+		if affRow == 0 {
+			return nil, domain.NewConflictError("stock has changed, please retry checkout")
+		}
+	}
+
+	checkoutResponse := checkout.NewCheckoutResponse(productCheckouts, payments)
+	rawResponse, err := json.Marshal(domain.NewMessageSuccess("success checkout", checkoutResponse))
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	response := domain.StoredResponse{
+		Status:     http.StatusOK,
+		Body:       rawResponse,
+		CheckoutID: checkout.ID,
+	}
+
+	if len(idempotencyKey) > 0 {
+		key := domain.NewIdempotencyKey(body.UserAdminID, idempotencyKey, requestHash, checkout.ID, response, cs.idempotencyKeyTTL)
+		affRow, err := cs.idempotencyKeyRepository.CreateIdempotencyKey(ctx, tx, key)
+		if err != nil {
+			return nil, domain.NewInternalServerError(err.Error())
+		}
+		if affRow == 0 {
+			// a concurrent request with the same key won, replay its response
+			tx.Rollback()
+
+			storedResponse, errMsg := cs.getStoredResponse(ctx, body.UserAdminID, idempotencyKey, requestHash)
+			if errMsg != nil {
+				return nil, errMsg
+			}
+			if storedResponse != nil {
+				return storedResponse, nil
+			}
+
+			return nil, domain.NewConflictError("a request with the same Idempotency-Key is still being processed")
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	return &response, nil
+}
+
+func (cs *checkoutService) QuoteCheckout(ctx context.Context, body domain.CheckoutQuoteRequest) (*domain.CheckoutQuoteResponse, domain.MessageErr) {
+	if len(body.CustomerID) > 0 {
+		ok, err := cs.userCustomerRepository.CheckCustomerExistsByID(ctx, cs.db, body.CustomerID)
+		if err != nil {
+			return nil, domain.NewInternalServerError(err.Error())
+		}
+		if !ok {
+			return nil, domain.NewNotFoundError("customerId is not found")
+		}
+	}
+
+	productCheckouts := body.NewProductCheckouts()
+
+	// no row locks, so a quote never holds up a checkout
+	tx, err := cs.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback()
+
+	var coupon *domain.Coupon
+	if len(body.CouponCode) > 0 {
+		var errMsg domain.MessageErr
+		coupon, errMsg = cs.getRedeemableCoupon(ctx, tx, body.CouponCode, body.CustomerID, false)
+		if errMsg != nil {
+			return nil, errMsg
+		}
+	}
+
+	errMsg := cs.priceProductCheckouts(ctx, tx, productCheckouts, coupon, false)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+
+	quote := domain.NewCheckoutQuoteResponse(productCheckouts)
+	payments := body.PaymentRequests()
+	if len(payments) > 0 {
+		change, errMsg := calculateChange(payments, quote.Total)
+		if errMsg != nil {
+			return nil, errMsg
+		}
+
+		for _, p := range payments {
+			quote.Paid += p.Amount
+		}
+		quote.Change = change
+	}
+
+	return &quote, nil
+}
+
+func (cs *checkoutService) CreateReturn(ctx context.Context, checkoutID string, body domain.ReturnRequest) (*domain.CheckoutResponse, domain.MessageErr) {
+	tx, err := cs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback()
+
+	// serializes returns of the sale, so the returned quantities stay current
+	sale, err := cs.checkoutRepository.GetCheckoutForUpdate(ctx, tx, checkoutID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if sale == nil {
+		return nil, domain.NewNotFoundError("checkout is not found")
+	}
+	if sale.Type != domain.CheckoutTypeSale {
+		return nil, domain.NewBadRequestError("only a sale can be returned")
+	}
+	if sale.VoidedAt != nil {
+		return nil, domain.NewBadRequestError("checkout is voided")
+	}
+
+	soldLines, err := cs.checkoutRepository.GetProductCheckoutsByCheckoutID(ctx, tx, sale.ID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	returnedQuantities, err := cs.checkoutRepository.GetReturnedQuantities(ctx, tx, sale.ID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	var soldProductIDs []string
+	soldProducts := map[string]domain.ProductCheckout{}
+	returnableQuantities := map[string]int{}
+	for _, sl := range soldLines {
+		soldProduct, ok := soldProducts[sl.ProductID]
+		if !ok {
+			soldProductIDs = append(soldProductIDs, sl.ProductID)
+			soldProducts[sl.ProductID] = sl
+		} else {
+			soldProduct.Quantity += sl.Quantity
+			soldProduct.Discount += sl.Discount
+			soldProduct.Tax += sl.Tax
+			soldProduct.Total += sl.Total
+			soldProducts[sl.ProductID] = soldProduct
+		}
+		returnableQuantities[sl.ProductID] += sl.Quantity
+	}
+	for productID, quantity := range returnedQuantities {
+		returnableQuantities[productID] -= quantity
+	}
+
+	// no product details means returning everything that is left
+	if len(body.ProductDetails) == 0 {
+		for _, productID := range soldProductIDs {
+			if returnableQuantities[productID] > 0 {
+				body.ProductDetails = append(body.ProductDetails, domain.ReturnProductRequest{
+					ProductID: productID,
+					Quantity:  returnableQuantities[productID],
+				})
+			}
+		}
+		if len(body.ProductDetails) == 0 {
+			return nil, domain.NewBadRequestError("checkout is already fully returned")
+		}
+	}
+
+	returnQuantities := map[string]int{}
+	for i, pd := range body.ProductDetails {
+		if _, ok := soldProducts[pd.ProductID]; !ok {
+			return nil, domain.NewBadRequestError(fmt.Sprintf("%s is not part of the checkout", pd.ProductID))
+		}
+		if len(pd.RestockState) == 0 {
+			body.ProductDetails[i].RestockState = domain.RestockStateSellable
+		}
+
+		returnQuantities[pd.ProductID] += pd.Quantity
+		if returnQuantities[pd.ProductID] > returnableQuantities[pd.ProductID] {
+			return nil, domain.NewBadRequestError(fmt.Sprintf(
+				"%s return quantity exceeds the %d left to return",
+				soldProducts[pd.ProductID].ProductName, returnableQuantities[pd.ProductID],
+			))
+		}
+	}
+
+	checkout, productCheckouts := body.NewReturn(*sale, soldProducts, returnedQuantities)
+	shiftID, errMsg := openShiftID(ctx, tx, cs.shiftRepository, checkout.UserAdminID, cs.shiftsEnabled)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	checkout.ShiftID = shiftID
+
+	salePayments, err := cs.paymentRepository.GetPaymentsByCheckoutID(ctx, tx, sale.ID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	salePointsEntries, err := cs.pointsRepository.GetPointsEntriesByCheckoutID(ctx, tx, sale.ID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	earlierRefunds, err := cs.paymentRepository.GetReturnPaymentsByCheckoutID(ctx, tx, sale.ID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	payments := domain.NewRefundPayments(*sale, checkout, salePayments, earlierRefunds)
+	earlierPointsEntries, err := cs.pointsRepository.GetReturnPointsEntriesByCheckoutID(ctx, tx, sale.ID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	returnedTotal, err := cs.checkoutRepository.GetReturnedTotal(ctx, tx, sale.ID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	pointsEntries := domain.NewReturnPointsEntries(*sale, checkout, payments, salePointsEntries, earlierPointsEntries, returnedTotal)
+
+	err = cs.checkoutRepository.CreateCheckout(ctx, tx, checkout, productCheckouts)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	err = cs.checkoutRepository.BulkCreateProductCheckout(ctx, tx, productCheckouts)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	err = cs.paymentRepository.BulkCreatePayment(ctx, tx, payments)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	err = cs.pointsRepository.BulkCreatePointsEntry(ctx, tx, pointsEntries)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	err = cs.creditRepository.BulkCreateCreditEntry(ctx, tx, domain.NewCheckoutCreditEntries(checkout, payments))
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	err = cs.giftCardRepository.BulkCreateGiftCardEntry(ctx, tx, domain.NewGiftCardPaymentEntries(checkout, payments))
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	for _, pc := range productCheckouts {
+		err = cs.productRepository.RestockProductByID(ctx, tx, pc.ProductID, -pc.Quantity, *pc.RestockState)
+		if err != nil {
+			return nil, domain.NewInternalServerError(err.Error())
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	response := checkout.NewCheckoutResponse(productCheckouts, payments)
+
+	return &response, nil
+}
+
+func (cs *checkoutService) VoidCheckout(ctx context.Context, checkoutID string, body domain.VoidRequest) (*domain.CheckoutResponse, domain.MessageErr) {
+	tx, err := cs.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback()
+
+	checkout, err := cs.checkoutRepository.GetCheckoutForUpdate(ctx, tx, checkoutID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if checkout == nil {
+		return nil, domain.NewNotFoundError("checkout is not found")
+	}
+	if checkout.UserAdminID != body.UserAdminID && !domain.HasPermission(body.UserAdminRole, domain.PermissionCheckoutVoidAny) {
+		return nil, domain.NewForbiddenError("only the cashier of the checkout or a manager can void it")
+	}
+	if checkout.Type != domain.CheckoutTypeSale {
+		return nil, domain.NewBadRequestError("only a sale can be voided")
+	}
+	if checkout.VoidedAt != nil {
+		return nil, domain.NewConflictError("checkout is already voided")
+	}
+	if time.Since(checkout.CreatedAt) > cs.voidWindow {
+		return nil, domain.NewBadRequestError(fmt.Sprintf("checkout can only be voided within %s", cs.voidWindow))
+	}
+	if checkout.ShiftID != nil {
+		shift, err := cs.shiftRepository.GetShiftByIDForShare(ctx, tx, *checkout.ShiftID)
+		if err != nil {
+			return nil, domain.NewInternalServerError(err.Error())
+		}
+		if shift != nil && shift.ClosedAt != nil {
+			return nil, domain.NewBadRequestError("checkout of a closed shift cannot be voided")
+		}
 	}
 
+	returnedQuantities, err := cs.checkoutRepository.GetReturnedQuantities(ctx, tx, checkout.ID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if len(returnedQuantities) > 0 {
+		return nil, domain.NewBadRequestError("checkout with returns cannot be voided")
+	}
+
+	productCheckouts, err := cs.checkoutRepository.GetProductCheckoutsByCheckoutID(ctx, tx, checkout.ID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	for _, pc := range productCheckouts {
+		err = cs.productRepository.RestockProductByID(ctx, tx, pc.ProductID, pc.Quantity, domain.RestockStateSellable)
+		if err != nil {
+			return nil, domain.NewInternalServerError(err.Error())
+		}
+	}
+
+	rawVoidedAt := time.Now().Format(time.RFC3339)
+	voidedAt, _ := time.Parse(time.RFC3339, rawVoidedAt)
+	checkout.VoidedAt = &voidedAt
+	checkout.VoidedBy = &body.UserAdminID
+	checkout.VoidReason = &body.Reason
+
+	err = cs.checkoutRepository.VoidCheckoutByID(ctx, tx, *checkout)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	err = cs.couponRepository.DeleteCouponRedemptionByCheckoutID(ctx, tx, checkout.ID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	pointsEntries, err := cs.pointsRepository.GetPointsEntriesByCheckoutID(ctx, tx, checkout.ID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	err = cs.pointsRepository.BulkCreatePointsEntry(ctx, tx, domain.NewVoidPointsEntries(pointsEntries, voidedAt))
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	creditEntries, err := cs.creditRepository.GetCreditEntriesByCheckoutID(ctx, tx, checkout.ID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	err = cs.creditRepository.BulkCreateCreditEntry(ctx, tx, domain.NewVoidCreditEntries(creditEntries, body.UserAdminID, voidedAt))
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	giftCardEntries, err := cs.giftCardRepository.GetGiftCardEntriesByCheckoutID(ctx, tx, checkout.ID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	err = cs.giftCardRepository.BulkCreateGiftCardEntry(ctx, tx, domain.NewVoidGiftCardEntries(giftCardEntries, voidedAt))
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	payments, err := cs.paymentRepository.GetPaymentsByCheckoutIDs(ctx, cs.db, []string{checkout.ID})
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	responses := []domain.CheckoutResponse{checkout.NewCheckoutResponse(productCheckouts, payments[checkout.ID])}
+	errMsg := cs.attachPromotions(ctx, responses)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	response := responses[0]
+
+	return &response, nil
+}
+
+func (cs *checkoutService) GetCheckoutHistory(ctx context.Context, queryParams domain.CheckoutHistoryQueryParams) ([]domain.CheckoutResponse, *domain.PaginationMeta, domain.MessageErr) {
+	checkouts, err := cs.checkoutRepository.GetCheckoutHistory(ctx, cs.db, queryParams, cs.storeLocation)
+	if err != nil {
+		return nil, nil, domain.NewInternalServerError(err.Error())
+	}
+
+	total, err := cs.checkoutRepository.CountCheckoutHistory(ctx, cs.db, queryParams, cs.storeLocation)
+	if err != nil {
+		return nil, nil, domain.NewInternalServerError(err.Error())
+	}
+
+	limit, offset := queryParams.LimitOffset()
+	meta := domain.PaginationMeta{
+		Limit:  limit,
+		Offset: offset,
+		Total:  total,
+	}
+
+	checkoutHistory := domain.NewCheckoutResponses(checkouts)
+	errMsg := cs.attachPayments(ctx, checkoutHistory)
+	if errMsg != nil {
+		return nil, nil, errMsg
+	}
+	errMsg = cs.attachPromotions(ctx, checkoutHistory)
+	if errMsg != nil {
+		return nil, nil, errMsg
+	}
+
+	return checkoutHistory, &meta, nil
+}
+
+func (cs *checkoutService) GetCheckoutByID(ctx context.Context, id string) (*domain.CheckoutResponse, domain.MessageErr) {
+	checkoutLines, err := cs.checkoutRepository.GetCheckoutByID(ctx, cs.db, id)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if len(checkoutLines) == 0 {
+		return nil, domain.NewNotFoundError("checkout is not found")
+	}
+
+	checkouts := domain.NewCheckoutResponses(checkoutLines)
+	errMsg := cs.attachPayments(ctx, checkouts)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	errMsg = cs.attachPromotions(ctx, checkouts)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	checkout := checkouts[0]
+
+	return &checkout, nil
+}
+
+func (cs *checkoutService) GetCashierSalesSummary(ctx context.Context, queryParams domain.CheckoutSummaryQueryParams) ([]domain.CashierSalesSummary, domain.MessageErr) {
+	summaries, err := cs.checkoutRepository.GetCashierSalesSummary(ctx, cs.db, queryParams, cs.storeLocation)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	return summaries, nil
+}
+
+func (cs *checkoutService) DeleteExpiredIdempotencyKeys(ctx context.Context) domain.MessageErr {
+	_, err := cs.idempotencyKeyRepository.DeleteExpiredIdempotencyKeys(ctx, cs.db, time.Now())
+	if err != nil {
+		return domain.NewInternalServerError(err.Error())
+	}
+
+	return nil
+}
+
+// shared by checkout and quote, a checkout locks the stock for the rest of tx
+func (cs *checkoutService) priceProductCheckouts(ctx context.Context, tx *sql.Tx, productCheckouts []domain.ProductCheckout, coupon *domain.Coupon, lock bool) domain.MessageErr {
 	var productIDs []string
 	productQuantities := map[string]int{}
 	for _, pc := range productCheckouts {
-		productIDs = append(productIDs, pc.ProductID)
-		productQuantities[pc.ProductID] = pc.Quantity
+		if _, ok := productQuantities[pc.ProductID]; !ok {
+			productIDs = append(productIDs, pc.ProductID)
+		}
+		productQuantities[pc.ProductID] += pc.Quantity
 	}
 
-	ok, err = cs.productRepository.CheckProductExistsByIDs(ctx, cs.db, productIDs)
+	ok, err := cs.productRepository.CheckProductExistsByIDs(ctx, tx, productIDs)
 	if err != nil {
 		return domain.NewInternalServerError(err.Error())
 	}
@@ -55,7 +705,11 @@ func (cs *checkoutService) CreateCheckout(ctx context.Context, body domain.Check
 		return domain.NewNotFoundError("one of productIds is not found")
 	}
 
-	productsStock, err := cs.productRepository.GetProductStockByIDs(ctx, cs.db, productIDs)
+	getProductStock := cs.productRepository.GetProductStockByIDs
+	if lock {
+		getProductStock = cs.productRepository.GetProductStockByIDsForUpdate
+	}
+	productsStock, err := getProductStock(ctx, tx, productIDs)
 	if err != nil {
 		return domain.NewInternalServerError(err.Error())
 	}
@@ -65,7 +719,7 @@ func (cs *checkoutService) CreateCheckout(ctx context.Context, body domain.Check
 		}
 	}
 
-	ok, err = cs.productRepository.CheckProductAvailabilities(ctx, cs.db, productIDs)
+	ok, err = cs.productRepository.CheckProductAvailabilities(ctx, tx, productIDs)
 	if err != nil {
 		return domain.NewInternalServerError(err.Error())
 	}
@@ -73,83 +727,253 @@ func (cs *checkoutService) CreateCheckout(ctx context.Context, body domain.Check
 		return domain.NewBadRequestError("one of productIds isAvailable == false")
 	}
 
-	totalPrice := 0
-	productPrices, err := cs.productRepository.GetProductPriceByIDs(ctx, cs.db, productIDs)
+	productPrices, err := cs.productRepository.GetProductPriceByIDs(ctx, tx, productIDs)
 	if err != nil {
 		return domain.NewInternalServerError(err.Error())
 	}
+	var categories []string
+	productMap := map[string]domain.ProductResponse{}
+	productCategories := map[string]string{}
 	for _, ps := range productPrices {
-		totalPrice += ps.Price * productQuantities[ps.ID]
+		categories = append(categories, ps.Category)
+		productMap[ps.ID] = ps
+		productCategories[ps.ID] = ps.Category
 	}
-	if checkout.Paid < totalPrice {
-		return domain.NewBadRequestError(fmt.Sprintf("not enough money, total price is %d", totalPrice))
-	}
-	change := checkout.Paid - totalPrice
-	if *checkout.Change != change {
-		return domain.NewBadRequestError(fmt.Sprintf("change is incorrect should be %d", change))
+	for i, pc := range productCheckouts {
+		product := productMap[pc.ProductID]
+		productCheckouts[i].ProductName = product.Name
+		productCheckouts[i].ProductSku = product.Sku
+		productCheckouts[i].Price = product.Price
 	}
 
-	tx, err := cs.db.BeginTx(ctx, nil)
+	promotions, err := cs.promotionRepository.GetActivePromotions(ctx, tx)
 	if err != nil {
 		return domain.NewInternalServerError(err.Error())
 	}
-	defer tx.Rollback()
+	now := time.Now().In(cs.storeLocation)
+	domain.ApplyPromotions(productCheckouts, productCategories, promotions, now)
+	if coupon != nil {
+		coupon.Apply(productCheckouts, now)
+	}
 
-	err = cs.checkoutRepository.CreateCheckout(ctx, tx, checkout, productCheckouts)
+	taxRates, err := cs.taxRateRepository.GetTaxRatesForProducts(ctx, tx, productIDs, categories)
 	if err != nil {
 		return domain.NewInternalServerError(err.Error())
 	}
+	productTaxRates := map[string]*domain.TaxRate{}
+	categoryTaxRates := map[string]*domain.TaxRate{}
+	for i, tr := range taxRates {
+		if tr.ProductID != nil {
+			productTaxRates[*tr.ProductID] = &taxRates[i]
+		}
+		if tr.Category != nil {
+			categoryTaxRates[*tr.Category] = &taxRates[i]
+		}
+	}
 
-	err = cs.checkoutRepository.BulkCreateProductCheckout(ctx, tx, productCheckouts)
+	for i, pc := range productCheckouts {
+		// a rate set on the product overrides the one of its category
+		taxRate, ok := productTaxRates[pc.ProductID]
+		if !ok {
+			taxRate = categoryTaxRates[productCategories[pc.ProductID]]
+		}
+		productCheckouts[i].ApplyTax(taxRate)
+	}
+
+	return nil
+}
+
+func (cs *checkoutService) getRedeemableCoupon(ctx context.Context, tx *sql.Tx, code string, customerID string, lock bool) (*domain.Coupon, domain.MessageErr) {
+	getCoupon := cs.couponRepository.GetCouponByCode
+	if lock {
+		getCoupon = cs.couponRepository.GetCouponByCodeForUpdate
+	}
+	coupon, err := getCoupon(ctx, tx, domain.NormalizeCouponCode(code))
 	if err != nil {
-		return domain.NewInternalServerError(err.Error())
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if coupon == nil {
+		return nil, domain.NewNotFoundError("couponCode is not found")
 	}
 
-	for _, pc := range productCheckouts {
-		err = cs.productRepository.UpdateProductStockByID(ctx, tx, pc.ProductID, pc.Quantity)
+	customerRedemptions := 0
+	if len(customerID) > 0 {
+		customerRedemptions, err = cs.couponRepository.CountCustomerRedemptions(ctx, tx, coupon.ID, customerID)
 		if err != nil {
-			return domain.NewInternalServerError(err.Error())
+			return nil, domain.NewInternalServerError(err.Error())
 		}
 	}
+	if msg := coupon.Unredeemable(time.Now(), customerRedemptions); len(msg) > 0 {
+		return nil, domain.NewBadRequestError(msg)
+	}
 
-	err = tx.Commit()
+	return coupon, nil
+}
+
+func (cs *checkoutService) attachPayments(ctx context.Context, checkouts []domain.CheckoutResponse) domain.MessageErr {
+	var checkoutIDs []string
+	for _, c := range checkouts {
+		checkoutIDs = append(checkoutIDs, c.TransactionID)
+	}
+
+	payments, err := cs.paymentRepository.GetPaymentsByCheckoutIDs(ctx, cs.db, checkoutIDs)
+	if err != nil {
+		return domain.NewInternalServerError(err.Error())
+	}
+	for i, c := range checkouts {
+		checkouts[i].Payments = domain.NewPaymentResponses(payments[c.TransactionID])
+	}
+
+	return nil
+}
+
+func (cs *checkoutService) attachPromotions(ctx context.Context, checkouts []domain.CheckoutResponse) domain.MessageErr {
+	var productCheckoutIDs []string
+	for _, c := range checkouts {
+		for _, pd := range c.ProductDetails {
+			productCheckoutIDs = append(productCheckoutIDs, pd.ID)
+		}
+	}
+
+	appliedPromotions, err := cs.promotionRepository.GetAppliedPromotionsByProductCheckoutIDs(ctx, cs.db, productCheckoutIDs)
+	if err != nil {
+		return domain.NewInternalServerError(err.Error())
+	}
+	for i, c := range checkouts {
+		for j, pd := range c.ProductDetails {
+			checkouts[i].ProductDetails[j].Promotions = domain.NewAppliedPromotionResponses(appliedPromotions[pd.ID])
+		}
+	}
+
+	return nil
+}
+
+func (cs *checkoutService) redeemGiftCards(ctx context.Context, tx *sql.Tx, payments []domain.Payment) domain.MessageErr {
+	codes := domain.GiftCardCodes(payments)
+	if len(codes) == 0 {
+		return nil
+	}
+
+	giftCards, err := cs.giftCardRepository.GetGiftCardsByCodesForUpdate(ctx, tx, codes)
+	if err != nil {
+		return domain.NewInternalServerError(err.Error())
+	}
+	var giftCardIDs []string
+	giftCardByCode := map[string]domain.GiftCard{}
+	for _, g := range giftCards {
+		giftCardIDs = append(giftCardIDs, g.ID)
+		giftCardByCode[g.Code] = g
+	}
+	balances, err := cs.giftCardRepository.GetGiftCardBalances(ctx, tx, giftCardIDs)
 	if err != nil {
 		return domain.NewInternalServerError(err.Error())
 	}
 
+	now := time.Now()
+	for _, code := range codes {
+		giftCard, ok := giftCardByCode[code]
+		if !ok {
+			return domain.NewNotFoundError(fmt.Sprintf("gift card %s is not found", code))
+		}
+		if msg := giftCard.Unredeemable(now); len(msg) > 0 {
+			return domain.NewBadRequestError(fmt.Sprintf("%s: %s", code, msg))
+		}
+	}
+
+	redeemed := domain.GiftCardRedemptions(payments)
+	for _, code := range codes {
+		giftCard := giftCardByCode[code]
+		if redeemed[code] > balances[giftCard.ID] {
+			return domain.NewBadRequestError(fmt.Sprintf("not enough balance on gift card %s, balance is %d", giftCard.Code, balances[giftCard.ID]))
+		}
+	}
+	for i, p := range payments {
+		if p.Method == domain.PaymentMethodGiftCard {
+			giftCard := giftCardByCode[p.GiftCardCode]
+			payments[i].GiftCardID = &giftCard.ID
+		}
+	}
+
 	return nil
 }
 
-func (cs *checkoutService) GetCheckoutHistory(ctx context.Context, queryParams domain.CheckoutHistoryQueryParams) ([]domain.GetCheckoutHistoryResponse, domain.MessageErr) {
-	checkouts, err := cs.checkoutRepository.GetCheckoutHistory(ctx, cs.db, queryParams)
+// change only comes from cash, other tenders must not cover more than the total
+func calculateChange(payments []domain.PaymentRequest, totalPrice int) (int, domain.MessageErr) {
+	cash := 0
+	nonCash := 0
+	for _, p := range payments {
+		if p.Method == domain.PaymentMethodCash {
+			cash += p.Amount
+		} else {
+			nonCash += p.Amount
+		}
+	}
+
+	if nonCash > totalPrice {
+		return 0, domain.NewBadRequestError(fmt.Sprintf("non-cash payments exceed the total price of %d", totalPrice))
+	}
+	remaining := totalPrice - nonCash
+	if cash < remaining {
+		return 0, domain.NewBadRequestError(fmt.Sprintf("not enough money, total price is %d", totalPrice))
+	}
+
+	return cash - remaining, nil
+}
+
+func (cs *checkoutService) storeFailedResponse(ctx context.Context, userAdminID string, idempotencyKey string, requestHash string, errMsg domain.MessageErr) (*domain.StoredResponse, domain.MessageErr) {
+	rawResponse, err := json.Marshal(errMsg)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	response := domain.StoredResponse{
+		Status: errMsg.Status(),
+		Body:   rawResponse,
+	}
+
+	tx, err := cs.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, domain.NewInternalServerError(err.Error())
 	}
+	defer tx.Rollback()
 
-	productDetailsMap := map[string][]domain.ProductCheckoutResponse{}
-	for _, chk := range checkouts {
-		productDetailsMap[chk.TransactionID] = append(productDetailsMap[chk.TransactionID], domain.ProductCheckoutResponse{
-			ProductID: chk.ProductID,
-			Quantity:  chk.Quantity,
-		})
+	key := domain.NewIdempotencyKey(userAdminID, idempotencyKey, requestHash, "", response, cs.idempotencyKeyTTL)
+	affRow, err := cs.idempotencyKeyRepository.CreateIdempotencyKey(ctx, tx, key)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
 	}
+	if affRow == 0 {
+		tx.Rollback()
 
-	checkoutHistory := []domain.GetCheckoutHistoryResponse{}
-	uniqueMap := map[string]bool{}
-	for _, chk := range checkouts {
-		if !uniqueMap[chk.TransactionID] {
-			uniqueMap[chk.TransactionID] = true
-			history := domain.GetCheckoutHistoryResponse{
-				TransactionID:  chk.TransactionID,
-				CustomerID:     chk.CustomerID,
-				CreatedAt:      chk.CreatedAt,
-				Paid:           chk.Paid,
-				Change:         chk.Change,
-				ProductDetails: productDetailsMap[chk.TransactionID],
-			}
-			checkoutHistory = append(checkoutHistory, history)
+		storedResponse, storedErrMsg := cs.getStoredResponse(ctx, userAdminID, idempotencyKey, requestHash)
+		if storedErrMsg != nil {
+			return nil, storedErrMsg
+		}
+		if storedResponse != nil {
+			return storedResponse, nil
 		}
+
+		return nil, errMsg
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	return nil, errMsg
+}
+
+func (cs *checkoutService) getStoredResponse(ctx context.Context, userAdminID string, idempotencyKey string, requestHash string) (*domain.StoredResponse, domain.MessageErr) {
+	storedKey, err := cs.idempotencyKeyRepository.GetIdempotencyKey(ctx, cs.db, userAdminID, idempotencyKey)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if storedKey == nil {
+		return nil, nil
+	}
+	if storedKey.RequestHash != requestHash {
+		return nil, domain.NewUnprocessableEntityError("Idempotency-Key is already used with a different request body")
 	}
 
-	return checkoutHistory, nil
+	return storedKey.StoredResponse(), nil
 }
@@ -0,0 +1,782 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"eniqilo-store/internal/domain"
+	"eniqilo-store/internal/repository"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCreateCheckoutDoesNotOversell(t *testing.T) {
+	db := newTestDB(t)
+	cs := newTestCheckoutService(db, false)
+	cashier := createTestUserAdmin(t, db, domain.UserAdminRoleCashier)
+	customer := createTestCustomer(t, db)
+
+	tests := []struct {
+		name      string
+		stock     int
+		checkouts int
+		quantity  int
+		wantSold  int
+	}{
+		{name: "one each", stock: 5, checkouts: 20, quantity: 1, wantSold: 5},
+		{name: "several each", stock: 10, checkouts: 8, quantity: 3, wantSold: 3},
+		{name: "out of stock", stock: 0, checkouts: 5, quantity: 1, wantSold: 0},
+		{name: "enough for all", stock: 10, checkouts: 10, quantity: 1, wantSold: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := createTestProduct(t, db, 1000, tt.stock)
+
+			var wg sync.WaitGroup
+			var sold atomic.Int32
+			for range tt.checkouts {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					change := 0
+					_, errMsg := cs.CreateCheckout(context.Background(), domain.CheckoutRequest{
+						UserAdminID:    cashier.ID,
+						CustomerID:     customer.ID,
+						ProductDetails: []domain.ProductCheckoutRequest{{ProductID: product.ID, Quantity: tt.quantity}},
+						Paid:           1000 * tt.quantity,
+						Change:         &change,
+					}, "")
+					if errMsg == nil {
+						sold.Add(1)
+						return
+					}
+					if errMsg.Status() != http.StatusBadRequest && errMsg.Status() != http.StatusConflict {
+						t.Errorf("CreateCheckout() error = %v", errMsg.Message())
+					}
+				}()
+			}
+			wg.Wait()
+
+			if int(sold.Load()) != tt.wantSold {
+				t.Errorf("sold %d checkouts, want %d", sold.Load(), tt.wantSold)
+			}
+			stock := getTestProductStock(t, db, product.ID)
+			if stock != tt.stock-tt.wantSold*tt.quantity {
+				t.Errorf("stock = %d, want %d", stock, tt.stock-tt.wantSold*tt.quantity)
+			}
+			if stock < 0 {
+				t.Errorf("stock dropped below zero: %d", stock)
+			}
+		})
+	}
+}
+
+func TestCreateCheckoutIdempotencyKeyScope(t *testing.T) {
+	db := newTestDB(t)
+	cs := newTestCheckoutService(db, false)
+	customer := createTestCustomer(t, db)
+	product := createTestProduct(t, db, 1000, 100)
+
+	checkout := func(userAdminID string, key string) (*domain.StoredResponse, domain.MessageErr) {
+		change := 0
+		return cs.CreateCheckout(context.Background(), domain.CheckoutRequest{
+			UserAdminID:    userAdminID,
+			CustomerID:     customer.ID,
+			ProductDetails: []domain.ProductCheckoutRequest{{ProductID: product.ID, Quantity: 1}},
+			Paid:           1000,
+			Change:         &change,
+		}, key)
+	}
+
+	tests := []struct {
+		name         string
+		firstKey     string
+		secondKey    string
+		sameStaff    bool
+		wantReplayed bool
+	}{
+		{name: "same staff same key", firstKey: "client:a", secondKey: "client:a", sameStaff: true, wantReplayed: true},
+		{name: "other staff same key", firstKey: "client:b", secondKey: "client:b", sameStaff: false, wantReplayed: false},
+		{name: "client key looking like a cart key", firstKey: "cart:c", secondKey: "client:cart:c", sameStaff: true, wantReplayed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first := createTestUserAdmin(t, db, domain.UserAdminRoleCashier)
+			second := first
+			if !tt.sameStaff {
+				second = createTestUserAdmin(t, db, domain.UserAdminRoleCashier)
+			}
+
+			firstResponse, errMsg := checkout(first.ID, tt.firstKey)
+			if errMsg != nil {
+				t.Fatalf("first CreateCheckout() error = %v", errMsg.Message())
+			}
+			secondResponse, errMsg := checkout(second.ID, tt.secondKey)
+			if errMsg != nil {
+				t.Fatalf("second CreateCheckout() error = %v", errMsg.Message())
+			}
+
+			if secondResponse.Replayed != tt.wantReplayed {
+				t.Errorf("Replayed = %v, want %v", secondResponse.Replayed, tt.wantReplayed)
+			}
+			if (secondResponse.CheckoutID == firstResponse.CheckoutID) != tt.wantReplayed {
+				t.Errorf("second checkout %s, first %s", secondResponse.CheckoutID, firstResponse.CheckoutID)
+			}
+		})
+	}
+}
+
+func TestCreateCheckoutIdempotencyKeyFailure(t *testing.T) {
+	db := newTestDB(t)
+	cs := newTestCheckoutService(db, false)
+
+	tests := []struct {
+		name         string
+		key          string
+		wantReplayed bool
+	}{
+		{name: "client key replays the failure", key: "client:" + uuid.NewString(), wantReplayed: true},
+		{name: "cart key checks out again", key: "cart:" + uuid.NewString(), wantReplayed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cashier := createTestUserAdmin(t, db, domain.UserAdminRoleCashier)
+			customer := createTestCustomer(t, db)
+			product := createTestProduct(t, db, 1000, 0)
+			change := 0
+			body := domain.CheckoutRequest{
+				UserAdminID:    cashier.ID,
+				CustomerID:     customer.ID,
+				ProductDetails: []domain.ProductCheckoutRequest{{ProductID: product.ID, Quantity: 1}},
+				Paid:           1000,
+				Change:         &change,
+			}
+
+			_, errMsg := cs.CreateCheckout(context.Background(), body, tt.key)
+			if errMsg == nil || errMsg.Status() != http.StatusBadRequest {
+				t.Fatalf("first CreateCheckout() error = %v, want a bad request", errMsg)
+			}
+
+			_, err := db.Exec(`UPDATE products SET stock = 10 WHERE id = $1`, product.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			response, errMsg := cs.CreateCheckout(context.Background(), body, tt.key)
+			if errMsg != nil {
+				t.Fatalf("second CreateCheckout() error = %v", errMsg.Message())
+			}
+			if response.Replayed != tt.wantReplayed {
+				t.Errorf("Replayed = %v, want %v", response.Replayed, tt.wantReplayed)
+			}
+			wantStatus := http.StatusOK
+			if tt.wantReplayed {
+				wantStatus = http.StatusBadRequest
+			}
+			if response.Status != wantStatus {
+				t.Errorf("status = %d, want %d", response.Status, wantStatus)
+			}
+		})
+	}
+}
+
+func TestVoidCheckout(t *testing.T) {
+	db := newTestDB(t)
+	cs := newTestCheckoutService(db, true)
+	ss := NewShiftService(db, repository.NewShiftRepository())
+	customer := createTestCustomer(t, db)
+	product := createTestProduct(t, db, 1000, 100)
+	manager := createTestUserAdmin(t, db, domain.UserAdminRoleManager)
+
+	tests := []struct {
+		name        string
+		closeShift  bool
+		voidBy      string
+		wantStatus  int
+		wantRestock bool
+	}{
+		{name: "cashier of the sale", voidBy: "cashier", wantStatus: http.StatusOK, wantRestock: true},
+		{name: "other cashier", voidBy: "other", wantStatus: http.StatusForbidden},
+		{name: "manager override", voidBy: "manager", wantStatus: http.StatusOK, wantRestock: true},
+		{name: "shift closed", closeShift: true, voidBy: "cashier", wantStatus: http.StatusBadRequest},
+		{name: "shift closed with manager override", closeShift: true, voidBy: "manager", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			cashier := createTestUserAdmin(t, db, domain.UserAdminRoleCashier)
+			other := createTestUserAdmin(t, db, domain.UserAdminRoleCashier)
+
+			openingFloat := 0
+			_, errMsg := ss.OpenShift(ctx, domain.ShiftOpenRequest{UserAdminID: cashier.ID, Till: "1", OpeningFloat: &openingFloat})
+			if errMsg != nil {
+				t.Fatalf("OpenShift() error = %v", errMsg.Message())
+			}
+
+			change := 0
+			response, errMsg := cs.CreateCheckout(ctx, domain.CheckoutRequest{
+				UserAdminID:    cashier.ID,
+				CustomerID:     customer.ID,
+				ProductDetails: []domain.ProductCheckoutRequest{{ProductID: product.ID, Quantity: 1}},
+				Paid:           1000,
+				Change:         &change,
+			}, "")
+			if errMsg != nil {
+				t.Fatalf("CreateCheckout() error = %v", errMsg.Message())
+			}
+
+			if tt.closeShift {
+				_, errMsg = ss.CloseShift(ctx, domain.ShiftCloseRequest{
+					UserAdminID:   cashier.ID,
+					Denominations: []domain.Denomination{{Value: 1000, Count: 1}},
+				})
+				if errMsg != nil {
+					t.Fatalf("CloseShift() error = %v", errMsg.Message())
+				}
+			}
+
+			voidBy := map[string]domain.UserAdmin{"cashier": cashier, "other": other, "manager": manager}[tt.voidBy]
+			stock := getTestProductStock(t, db, product.ID)
+			_, errMsg = cs.VoidCheckout(ctx, response.CheckoutID, domain.VoidRequest{
+				UserAdminID:   voidBy.ID,
+				UserAdminRole: voidBy.Role,
+				Reason:        "test",
+			})
+
+			status := http.StatusOK
+			if errMsg != nil {
+				status = errMsg.Status()
+			}
+			if status != tt.wantStatus {
+				t.Errorf("VoidCheckout() status = %d, want %d", status, tt.wantStatus)
+			}
+			restocked := getTestProductStock(t, db, product.ID) - stock
+			if (restocked == 1) != tt.wantRestock {
+				t.Errorf("restocked %d", restocked)
+			}
+		})
+	}
+}
+
+func TestCalculateChange(t *testing.T) {
+	tests := []struct {
+		name       string
+		payments   []domain.PaymentRequest
+		totalPrice int
+		wantChange int
+		wantStatus int
+	}{
+		{
+			name:       "exact cash",
+			payments:   []domain.PaymentRequest{{Method: domain.PaymentMethodCash, Amount: 5000}},
+			totalPrice: 5000,
+		},
+		{
+			name:       "change from cash",
+			payments:   []domain.PaymentRequest{{Method: domain.PaymentMethodCash, Amount: 10000}},
+			totalPrice: 7500,
+			wantChange: 2500,
+		},
+		{
+			name: "split tender gives change from cash only",
+			payments: []domain.PaymentRequest{
+				{Method: domain.PaymentMethodQris, Amount: 4000},
+				{Method: domain.PaymentMethodCash, Amount: 5000},
+			},
+			totalPrice: 7500,
+			wantChange: 1500,
+		},
+		{
+			name:       "non-cash covering the total",
+			payments:   []domain.PaymentRequest{{Method: domain.PaymentMethodDebitCard, Amount: 7500}},
+			totalPrice: 7500,
+		},
+		{
+			name:       "non-cash over the total",
+			payments:   []domain.PaymentRequest{{Method: domain.PaymentMethodEWallet, Amount: 8000}},
+			totalPrice: 7500,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "not enough",
+			payments: []domain.PaymentRequest{
+				{Method: domain.PaymentMethodStoreCredit, Amount: 2000},
+				{Method: domain.PaymentMethodCash, Amount: 5000},
+			},
+			totalPrice: 7500,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change, errMsg := calculateChange(tt.payments, tt.totalPrice)
+			if errMsg != nil {
+				if errMsg.Status() != tt.wantStatus {
+					t.Fatalf("calculateChange() status = %d, want %d", errMsg.Status(), tt.wantStatus)
+				}
+				return
+			}
+			if tt.wantStatus != 0 {
+				t.Fatalf("calculateChange() succeeded, want status %d", tt.wantStatus)
+			}
+			if change != tt.wantChange {
+				t.Errorf("change = %d, want %d", change, tt.wantChange)
+			}
+		})
+	}
+}
+
+func TestGetCheckoutByID(t *testing.T) {
+	db := newTestDB(t)
+	cs := newTestCheckoutService(db, false)
+	cashier := createTestUserAdmin(t, db, domain.UserAdminRoleCashier)
+	customer := createTestCustomer(t, db)
+	product := createTestProduct(t, db, 2500, 10)
+	created := createTestCheckout(t, cs, cashier.ID, customer.ID, product.ID, 2, 5000)
+
+	var want domain.CheckoutResponse
+	err := json.Unmarshal(created.Body, &want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		id         string
+		wantStatus int
+	}{
+		{name: "created checkout", id: created.CheckoutID, wantStatus: http.StatusOK},
+		{name: "unknown id", id: uuid.NewString(), wantStatus: http.StatusNotFound},
+		{name: "malformed id", id: "not-a-uuid", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkout, errMsg := cs.GetCheckoutByID(context.Background(), tt.id)
+			if errMsg != nil {
+				if errMsg.Status() != tt.wantStatus {
+					t.Errorf("GetCheckoutByID() status = %d, want %d", errMsg.Status(), tt.wantStatus)
+				}
+				return
+			}
+			if tt.wantStatus != http.StatusOK {
+				t.Fatalf("GetCheckoutByID() succeeded, want status %d", tt.wantStatus)
+			}
+
+			if checkout.TransactionID != want.TransactionID || checkout.CashierID != cashier.ID || checkout.CustomerID != customer.ID {
+				t.Errorf("GetCheckoutByID() = %+v, want %+v", checkout, want)
+			}
+			if checkout.Total != 5000 || checkout.Paid != 5000 || checkout.Change != 0 {
+				t.Errorf("total %d, paid %d, change %d", checkout.Total, checkout.Paid, checkout.Change)
+			}
+			if len(checkout.ProductDetails) != 1 || checkout.ProductDetails[0].Quantity != 2 || checkout.ProductDetails[0].Total != 5000 {
+				t.Errorf("product details = %+v", checkout.ProductDetails)
+			}
+			if len(checkout.Payments) != len(want.Payments) {
+				t.Errorf("payments = %+v, want %+v", checkout.Payments, want.Payments)
+			}
+		})
+	}
+}
+
+func TestCheckoutKeepsProductSnapshot(t *testing.T) {
+	db := newTestDB(t)
+	cs := newTestCheckoutService(db, false)
+	cashier := createTestUserAdmin(t, db, domain.UserAdminRoleCashier)
+	customer := createTestCustomer(t, db)
+
+	tests := []struct {
+		name     string
+		newName  string
+		newPrice int
+	}{
+		{name: "price raised", newName: "Test Product", newPrice: 1500},
+		{name: "renamed and discounted", newName: "Renamed Product", newPrice: 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := createTestProduct(t, db, 1000, 10)
+			created := createTestCheckout(t, cs, cashier.ID, customer.ID, product.ID, 3, 3000)
+
+			product.Name = tt.newName
+			product.Price = tt.newPrice
+			_, err := repository.NewProductRepository().UpdateProductByID(context.Background(), db, product)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checkout, errMsg := cs.GetCheckoutByID(context.Background(), created.CheckoutID)
+			if errMsg != nil {
+				t.Fatalf("GetCheckoutByID() error = %v", errMsg.Message())
+			}
+			line := checkout.ProductDetails[0]
+			if line.Name != "Test Product" || line.Sku != product.Sku || line.Price != 1000 || line.Total != 3000 {
+				t.Errorf("line = %+v, want the product as sold", line)
+			}
+			if checkout.Total != 3000 {
+				t.Errorf("total = %d, want 3000", checkout.Total)
+			}
+		})
+	}
+}
+
+func TestGetCashierSalesSummary(t *testing.T) {
+	db := newTestDB(t)
+	cs := newTestCheckoutService(db, false)
+	customer := createTestCustomer(t, db)
+	product := createTestProduct(t, db, 1000, 100)
+
+	tests := []struct {
+		name       string
+		quantities []int
+		voided     int
+		wantCount  int
+		wantTotal  int
+	}{
+		{name: "one sale", quantities: []int{2}, wantCount: 1, wantTotal: 2000},
+		{name: "several sales", quantities: []int{1, 3, 2}, wantCount: 3, wantTotal: 6000},
+		{name: "voided sales are left out", quantities: []int{1, 4}, voided: 1, wantCount: 1, wantTotal: 4000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			cashier := createTestUserAdmin(t, db, domain.UserAdminRoleCashier)
+			for i, quantity := range tt.quantities {
+				created := createTestCheckout(t, cs, cashier.ID, customer.ID, product.ID, quantity, 1000*quantity)
+				if i < tt.voided {
+					_, errMsg := cs.VoidCheckout(ctx, created.CheckoutID, domain.VoidRequest{UserAdminID: cashier.ID, UserAdminRole: cashier.Role, Reason: "test"})
+					if errMsg != nil {
+						t.Fatalf("VoidCheckout() error = %v", errMsg.Message())
+					}
+				}
+			}
+
+			summaries, errMsg := cs.GetCashierSalesSummary(ctx, domain.CheckoutSummaryQueryParams{CashierId: cashier.ID})
+			if errMsg != nil {
+				t.Fatalf("GetCashierSalesSummary() error = %v", errMsg.Message())
+			}
+			if len(summaries) != 1 {
+				t.Fatalf("got %d summaries, want 1", len(summaries))
+			}
+			summary := summaries[0]
+			if summary.CashierID != cashier.ID || summary.TransactionCount != tt.wantCount || summary.Total != tt.wantTotal {
+				t.Errorf("summary = %+v, want %d sales totalling %d", summary, tt.wantCount, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestCreateReturn(t *testing.T) {
+	db := newTestDB(t)
+	cs := newTestCheckoutService(db, false)
+	cashier := createTestUserAdmin(t, db, domain.UserAdminRoleCashier)
+	customer := createTestCustomer(t, db)
+
+	tests := []struct {
+		name         string
+		details      [][]domain.ReturnProductRequest
+		wantStatuses []int
+		wantStock    int
+		wantDamaged  int
+	}{
+		{
+			name:         "everything left",
+			details:      [][]domain.ReturnProductRequest{nil},
+			wantStatuses: []int{http.StatusOK},
+			wantStock:    10,
+		},
+		{
+			name: "sellable and damaged",
+			details: [][]domain.ReturnProductRequest{{
+				{Quantity: 1, RestockState: domain.RestockStateSellable},
+				{Quantity: 1, RestockState: domain.RestockStateDamaged},
+			}},
+			wantStatuses: []int{http.StatusOK},
+			wantStock:    8,
+			wantDamaged:  1,
+		},
+		{
+			name: "more than is left",
+			details: [][]domain.ReturnProductRequest{
+				{{Quantity: 2}},
+				{{Quantity: 2}},
+			},
+			wantStatuses: []int{http.StatusOK, http.StatusBadRequest},
+			wantStock:    9,
+		},
+		{
+			name:         "already fully returned",
+			details:      [][]domain.ReturnProductRequest{nil, nil},
+			wantStatuses: []int{http.StatusOK, http.StatusBadRequest},
+			wantStock:    10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			product := createTestProduct(t, db, 1000, 10)
+			sale := createTestCheckout(t, cs, cashier.ID, customer.ID, product.ID, 3, 3000)
+
+			for i, details := range tt.details {
+				for j := range details {
+					details[j].ProductID = product.ID
+				}
+				_, errMsg := cs.CreateReturn(ctx, sale.CheckoutID, domain.ReturnRequest{
+					UserAdminID:    cashier.ID,
+					Reason:         domain.ReturnReasonDefective,
+					ProductDetails: details,
+				})
+				status := http.StatusOK
+				if errMsg != nil {
+					status = errMsg.Status()
+				}
+				if status != tt.wantStatuses[i] {
+					t.Errorf("return %d: CreateReturn() status = %d, want %d", i, status, tt.wantStatuses[i])
+				}
+			}
+
+			var stock, damaged int
+			err := db.QueryRow(`SELECT stock, damaged_stock FROM products WHERE id = $1`, product.ID).Scan(&stock, &damaged)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stock != tt.wantStock || damaged != tt.wantDamaged {
+				t.Errorf("stock %d, damaged %d, want %d, %d", stock, damaged, tt.wantStock, tt.wantDamaged)
+			}
+		})
+	}
+}
+
+func TestQuoteCheckout(t *testing.T) {
+	db := newTestDB(t)
+	cs := newTestCheckoutService(db, false)
+	product := createTestProduct(t, db, 1250, 5)
+
+	tests := []struct {
+		name       string
+		quantity   int
+		payments   []domain.PaymentRequest
+		wantStatus int
+		wantTotal  int
+		wantChange int
+	}{
+		{name: "without payments", quantity: 2, wantStatus: http.StatusOK, wantTotal: 2500},
+		{
+			name:       "with change",
+			quantity:   4,
+			payments:   []domain.PaymentRequest{{Method: domain.PaymentMethodCash, Amount: 10000}},
+			wantStatus: http.StatusOK,
+			wantTotal:  5000,
+			wantChange: 5000,
+		},
+		{
+			name:       "not enough paid",
+			quantity:   1,
+			payments:   []domain.PaymentRequest{{Method: domain.PaymentMethodCash, Amount: 1000}},
+			wantStatus: http.StatusBadRequest,
+		},
+		{name: "more than in stock", quantity: 6, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote, errMsg := cs.QuoteCheckout(context.Background(), domain.CheckoutQuoteRequest{
+				ProductDetails: []domain.ProductCheckoutRequest{{ProductID: product.ID, Quantity: tt.quantity}},
+				Payments:       tt.payments,
+			})
+			status := http.StatusOK
+			if errMsg != nil {
+				status = errMsg.Status()
+			}
+			if status != tt.wantStatus {
+				t.Fatalf("QuoteCheckout() status = %d, want %d", status, tt.wantStatus)
+			}
+			if errMsg == nil && (quote.Total != tt.wantTotal || quote.Change != tt.wantChange) {
+				t.Errorf("total %d, change %d, want %d, %d", quote.Total, quote.Change, tt.wantTotal, tt.wantChange)
+			}
+			if stock := getTestProductStock(t, db, product.ID); stock != 5 {
+				t.Errorf("quote changed the stock to %d", stock)
+			}
+		})
+	}
+}
+
+func TestQuoteCheckoutCouponCustomerLimit(t *testing.T) {
+	db := newTestDB(t)
+	cs := newTestCheckoutService(db, false)
+
+	tests := []struct {
+		name         string
+		withCustomer bool
+		redeemed     bool
+		wantStatus   int
+	}{
+		{name: "customer under the limit", withCustomer: true, wantStatus: http.StatusOK},
+		{name: "customer at the limit", withCustomer: true, redeemed: true, wantStatus: http.StatusBadRequest},
+		{name: "no customer", redeemed: true, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			cashier := createTestUserAdmin(t, db, domain.UserAdminRoleCashier)
+			customer := createTestCustomer(t, db)
+			product := createTestProduct(t, db, 1000, 10)
+			maxRedemptionsPerCustomer := 1
+			coupon := createTestCoupon(t, db, 100, nil, &maxRedemptionsPerCustomer)
+
+			if tt.redeemed {
+				change := 0
+				_, errMsg := cs.CreateCheckout(ctx, domain.CheckoutRequest{
+					UserAdminID:    cashier.ID,
+					CustomerID:     customer.ID,
+					ProductDetails: []domain.ProductCheckoutRequest{{ProductID: product.ID, Quantity: 1}},
+					CouponCode:     coupon.Code,
+					Paid:           900,
+					Change:         &change,
+				}, "")
+				if errMsg != nil {
+					t.Fatalf("CreateCheckout() error = %v", errMsg.Message())
+				}
+			}
+
+			body := domain.CheckoutQuoteRequest{
+				ProductDetails: []domain.ProductCheckoutRequest{{ProductID: product.ID, Quantity: 1}},
+				CouponCode:     coupon.Code,
+			}
+			if tt.withCustomer {
+				body.CustomerID = customer.ID
+			}
+			_, errMsg := cs.QuoteCheckout(ctx, body)
+			status := http.StatusOK
+			if errMsg != nil {
+				status = errMsg.Status()
+			}
+			if status != tt.wantStatus {
+				t.Errorf("QuoteCheckout() status = %d, want %d", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestQuoteCheckoutDoesNotLock(t *testing.T) {
+	db := newTestDB(t)
+	cs := newTestCheckoutService(db, false)
+	product := createTestProduct(t, db, 1000, 10)
+	coupon := createTestCoupon(t, db, 100, nil, nil)
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	_, err = tx.Exec(`SELECT id FROM products WHERE id = $1 FOR UPDATE`, product.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = tx.Exec(`SELECT id FROM coupons WHERE id = $1 FOR UPDATE`, coupon.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	quote, errMsg := cs.QuoteCheckout(ctx, domain.CheckoutQuoteRequest{
+		ProductDetails: []domain.ProductCheckoutRequest{{ProductID: product.ID, Quantity: 1}},
+		CouponCode:     coupon.Code,
+	})
+	if errMsg != nil {
+		t.Fatalf("QuoteCheckout() error = %v", errMsg.Message())
+	}
+	if quote.Total != 900 {
+		t.Errorf("total = %d, want 900", quote.Total)
+	}
+}
+
+func TestCreateCheckoutCouponLimits(t *testing.T) {
+	db := newTestDB(t)
+	cs := newTestCheckoutService(db, false)
+
+	tests := []struct {
+		name              string
+		redeemedBySame    int
+		redeemedByOthers  int
+		voidOneRedemption bool
+		wantStatus        int
+	}{
+		{name: "first redemption", wantStatus: http.StatusOK},
+		{name: "customer limit", redeemedBySame: 1, wantStatus: http.StatusBadRequest},
+		{name: "second customer", redeemedByOthers: 1, wantStatus: http.StatusOK},
+		{name: "fully redeemed", redeemedByOthers: 2, wantStatus: http.StatusBadRequest},
+		{name: "void gives the redemption back", redeemedByOthers: 2, voidOneRedemption: true, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			cashier := createTestUserAdmin(t, db, domain.UserAdminRoleCashier)
+			customer := createTestCustomer(t, db)
+			product := createTestProduct(t, db, 1000, 100)
+			maxRedemptions, maxRedemptionsPerCustomer := 2, 1
+			coupon := createTestCoupon(t, db, 100, &maxRedemptions, &maxRedemptionsPerCustomer)
+
+			checkout := func(customerID string) (*domain.StoredResponse, domain.MessageErr) {
+				change := 0
+				return cs.CreateCheckout(ctx, domain.CheckoutRequest{
+					UserAdminID:    cashier.ID,
+					CustomerID:     customerID,
+					ProductDetails: []domain.ProductCheckoutRequest{{ProductID: product.ID, Quantity: 1}},
+					CouponCode:     strings.ToLower(coupon.Code),
+					Paid:           900,
+					Change:         &change,
+				}, "")
+			}
+
+			var redemptionIDs []string
+			for i := 0; i < tt.redeemedBySame+tt.redeemedByOthers; i++ {
+				customerID := customer.ID
+				if i >= tt.redeemedBySame {
+					customerID = createTestCustomer(t, db).ID
+				}
+				response, errMsg := checkout(customerID)
+				if errMsg != nil {
+					t.Fatalf("CreateCheckout() error = %v", errMsg.Message())
+				}
+				redemptionIDs = append(redemptionIDs, response.CheckoutID)
+			}
+			if tt.voidOneRedemption {
+				_, errMsg := cs.VoidCheckout(ctx, redemptionIDs[0], domain.VoidRequest{
+					UserAdminID:   cashier.ID,
+					UserAdminRole: cashier.Role,
+					Reason:        "test",
+				})
+				if errMsg != nil {
+					t.Fatalf("VoidCheckout() error = %v", errMsg.Message())
+				}
+			}
+
+			_, errMsg := checkout(customer.ID)
+			status := http.StatusOK
+			if errMsg != nil {
+				status = errMsg.Status()
+			}
+			if status != tt.wantStatus {
+				t.Errorf("CreateCheckout() status = %d, want %d", status, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"eniqilo-store/internal/domain"
+	"eniqilo-store/internal/repository"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type PromotionService interface {
+	CreatePromotion(ctx context.Context, promotion domain.Promotion) domain.MessageErr
+	GetPromotions(ctx context.Context) ([]domain.PromotionResponse, domain.MessageErr)
+	UpdatePromotionByID(ctx context.Context, promotion domain.Promotion) domain.MessageErr
+	DeletePromotionByID(ctx context.Context, id string) domain.MessageErr
+	GetPromotionReport(ctx context.Context, queryParams domain.PromotionReportQueryParams) ([]domain.PromotionReport, domain.MessageErr)
+}
+
+type promotionService struct {
+	db                  *sql.DB
+	promotionRepository repository.PromotionRepository
+	storeLocation       *time.Location
+}
+
+func NewPromotionService(db *sql.DB, promotionRepository repository.PromotionRepository, storeLocation *time.Location) PromotionService {
+	return &promotionService{
+		db:                  db,
+		promotionRepository: promotionRepository,
+		storeLocation:       storeLocation,
+	}
+}
+
+func (ps *promotionService) CreatePromotion(ctx context.Context, promotion domain.Promotion) domain.MessageErr {
+	if msg := promotion.Validate(); len(msg) > 0 {
+		return domain.NewBadRequestError(msg)
+	}
+
+	err := ps.promotionRepository.CreatePromotion(ctx, ps.db, promotion)
+	if err != nil {
+		return promotionWriteError(err)
+	}
+
+	return nil
+}
+
+func (ps *promotionService) GetPromotions(ctx context.Context) ([]domain.PromotionResponse, domain.MessageErr) {
+	promotions, err := ps.promotionRepository.GetPromotions(ctx, ps.db)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	promotionResponses := []domain.PromotionResponse{}
+	for _, p := range promotions {
+		promotionResponses = append(promotionResponses, p.NewPromotionResponse())
+	}
+
+	return promotionResponses, nil
+}
+
+func (ps *promotionService) UpdatePromotionByID(ctx context.Context, promotion domain.Promotion) domain.MessageErr {
+	if msg := promotion.Validate(); len(msg) > 0 {
+		return domain.NewBadRequestError(msg)
+	}
+
+	affRow, err := ps.promotionRepository.UpdatePromotionByID(ctx, ps.db, promotion)
+	if err != nil {
+		return promotionWriteError(err)
+	}
+	if affRow == 0 {
+		return domain.NewNotFoundError("promotion is not found")
+	}
+
+	return nil
+}
+
+func (ps *promotionService) DeletePromotionByID(ctx context.Context, id string) domain.MessageErr {
+	affRow, err := ps.promotionRepository.DeletePromotionByID(ctx, ps.db, id)
+	if err != nil {
+		return domain.NewInternalServerError(err.Error())
+	}
+	if affRow == 0 {
+		return domain.NewNotFoundError("promotion is not found")
+	}
+
+	return nil
+}
+
+func (ps *promotionService) GetPromotionReport(ctx context.Context, queryParams domain.PromotionReportQueryParams) ([]domain.PromotionReport, domain.MessageErr) {
+	reports, err := ps.promotionRepository.GetPromotionReport(ctx, ps.db, queryParams, ps.storeLocation)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	return reports, nil
+}
+
+func promotionWriteError(err error) domain.MessageErr {
+	if err, ok := err.(*pgconn.PgError); ok {
+		if err.Code == "23503" {
+			return domain.NewNotFoundError("product is not found")
+		}
+	}
+
+	return domain.NewInternalServerError(err.Error())
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"eniqilo-store/internal/domain"
+	"eniqilo-store/internal/repository"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type ReportService interface {
+	GetZReport(ctx context.Context, queryParams domain.ZReportQueryParams) (*domain.ZReport, domain.MessageErr)
+	CloseZReport(ctx context.Context, body domain.ZReportCloseRequest) (*domain.ZReport, domain.MessageErr)
+}
+
+type reportService struct {
+	db               *sql.DB
+	reportRepository repository.ReportRepository
+	storeLocation    *time.Location
+}
+
+func NewReportService(db *sql.DB, reportRepository repository.ReportRepository, storeLocation *time.Location) ReportService {
+	return &reportService{
+		db:               db,
+		reportRepository: reportRepository,
+		storeLocation:    storeLocation,
+	}
+}
+
+func (rs *reportService) GetZReport(ctx context.Context, queryParams domain.ZReportQueryParams) (*domain.ZReport, domain.MessageErr) {
+	date := queryParams.Date
+	if len(date) == 0 {
+		date = time.Now().In(rs.storeLocation).Format(time.DateOnly)
+	}
+	from, to, ok := domain.ParseBusinessDay(date, rs.storeLocation)
+	if !ok {
+		return nil, domain.NewBadRequestError("date should be YYYY-MM-DD")
+	}
+
+	closed, err := rs.reportRepository.GetClosedZReport(ctx, rs.db, date)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if closed != nil {
+		return closed, nil
+	}
+
+	// one snapshot for all of the day's totals
+	tx, err := rs.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback()
+
+	totals, err := rs.reportRepository.GetZReportTotals(ctx, tx, from, to)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	report := domain.NewZReport(date, from, to, totals)
+
+	return &report, nil
+}
+
+func (rs *reportService) CloseZReport(ctx context.Context, body domain.ZReportCloseRequest) (*domain.ZReport, domain.MessageErr) {
+	from, to, ok := domain.ParseBusinessDay(body.Date, rs.storeLocation)
+	if !ok {
+		return nil, domain.NewBadRequestError("date should be YYYY-MM-DD")
+	}
+	if from.After(time.Now()) {
+		return nil, domain.NewBadRequestError("a day can't be closed before it starts")
+	}
+
+	tx, err := rs.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback()
+
+	totals, err := rs.reportRepository.GetZReportTotals(ctx, tx, from, to)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	report := domain.NewZReport(body.Date, from, to, totals)
+	report.Close(body.UserAdminID)
+
+	err = rs.reportRepository.CreateClosedZReport(ctx, tx, report)
+	if err != nil {
+		if err, ok := err.(*pgconn.PgError); ok {
+			if err.Code == "23505" {
+				return nil, domain.NewConflictError("day is already closed")
+			}
+		}
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	return &report, nil
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"eniqilo-store/internal/domain"
+	"eniqilo-store/internal/repository"
+	"fmt"
+	"math/rand/v2"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// newTestDB migrates a schema of its own on TEST_DATABASE_URL, tests that
+// need a database are skipped without it.
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	rawURL := os.Getenv("TEST_DATABASE_URL")
+	if len(rawURL) == 0 {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	admin, err := sql.Open("pgx", rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := "test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	_, err = admin.Exec("CREATE SCHEMA " + schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dbURL, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := dbURL.Query()
+	query.Set("search_path", schema)
+	dbURL.RawQuery = query.Encode()
+	db, err := sql.Open("pgx", dbURL.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		admin.Close()
+	})
+
+	migrations, err := filepath.Glob("../../migrations/*.up.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range migrations {
+		raw, err := os.ReadFile(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = db.Exec(string(raw))
+		if err != nil {
+			t.Fatalf("%s: %s", filepath.Base(m), err)
+		}
+	}
+
+	return db
+}
+
+func newTestCheckoutService(db *sql.DB, shiftsEnabled bool) CheckoutService {
+	return NewCheckoutService(db,
+		repository.NewCheckoutRepository(), repository.NewUserCustomerRepository(), repository.NewProductRepository(),
+		repository.NewIdempotencyKeyRepository(), repository.NewPaymentRepository(), repository.NewTaxRateRepository(),
+		repository.NewPromotionRepository(), repository.NewCouponRepository(), repository.NewPointsRepository(),
+		repository.NewCreditRepository(), repository.NewGiftCardRepository(), repository.NewShiftRepository(),
+		24*time.Hour, time.Hour, time.UTC, 0, shiftsEnabled,
+	)
+}
+
+func newTestUserAdminService(db *sql.DB) UserAdminService {
+	return NewUserAdminService(db,
+		repository.NewUserAdminRepository(), repository.NewStaffInvitationRepository(), repository.NewStaffSessionRepository(),
+		repository.NewLoginAttemptRepository(), repository.NewTwoFactorRepository(),
+		"test-secret", 4, time.Hour, time.Hour, time.Hour, "Test", []string{domain.UserAdminRoleOwner},
+	)
+}
+
+func testPhoneNumber() string {
+	return fmt.Sprintf("+62%010d", rand.Int64N(1e10))
+}
+
+func createTestUserAdmin(t *testing.T, db *sql.DB, role string) domain.UserAdmin {
+	t.Helper()
+
+	request := domain.RegisterUserAdminRequest{Name: "Test Staff", PhoneNumber: testPhoneNumber(), Password: "password"}
+	userAdmin := request.NewUserAdminFromDTO()
+	userAdmin.Role = role
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	err = repository.NewUserAdminRepository().CreateUserAdminRepository(context.Background(), tx, userAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tx.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return userAdmin
+}
+
+func createTestStaffSession(t *testing.T, db *sql.DB, userAdminID string, ttl time.Duration) string {
+	t.Helper()
+
+	staffSessionRepository := repository.NewStaffSessionRepository()
+	session := domain.NewStaffSession(userAdminID)
+	refreshToken, token, err := session.NewRefreshToken(ttl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	withTestTx(t, db, func(tx *sql.Tx) error {
+		err := staffSessionRepository.CreateStaffSession(context.Background(), tx, session)
+		if err != nil {
+			return err
+		}
+
+		return staffSessionRepository.CreateStaffRefreshToken(context.Background(), tx, refreshToken)
+	})
+
+	return token
+}
+
+func createTestCustomer(t *testing.T, db *sql.DB) domain.UserCustomer {
+	t.Helper()
+
+	request := domain.RegisterUserCustomerRequest{Name: "Test Customer", PhoneNumber: testPhoneNumber()}
+	customer := request.NewUserCustomer()
+	err := repository.NewUserCustomerRepository().CreateUserCustomer(context.Background(), db, customer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return customer
+}
+
+func createTestProduct(t *testing.T, db *sql.DB, price int, stock int) domain.Product {
+	t.Helper()
+
+	isAvailable := true
+	request := domain.ProductRequest{
+		Name:        "Test Product",
+		Sku:         "SKU-" + uuid.NewString()[:8],
+		Category:    domain.ProductCategoryBeverages,
+		ImageUrl:    "https://example.com/product.png",
+		Notes:       "test",
+		Price:       price,
+		Stock:       &stock,
+		Location:    "A1",
+		IsAvailable: &isAvailable,
+	}
+	product := request.NewProduct()
+	err := repository.NewProductRepository().CreateProduct(context.Background(), db, product)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return product
+}
+
+func createTestCoupon(t *testing.T, db *sql.DB, value int, maxRedemptions *int, maxRedemptionsPerCustomer *int) domain.Coupon {
+	t.Helper()
+
+	isActive := true
+	request := domain.CouponRequest{
+		Code:                      "TEST" + strings.ToUpper(uuid.NewString()[:8]),
+		Type:                      domain.PromotionTypeFixed,
+		Value:                     value,
+		MaxRedemptions:            maxRedemptions,
+		MaxRedemptionsPerCustomer: maxRedemptionsPerCustomer,
+		IsActive:                  &isActive,
+	}
+	coupon := request.NewCoupon()
+	err := repository.NewCouponRepository().CreateCoupon(context.Background(), db, coupon)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return coupon
+}
+
+func createTestCheckout(t *testing.T, cs CheckoutService, userAdminID string, customerID string, productID string, quantity int, paid int) *domain.StoredResponse {
+	t.Helper()
+
+	change := 0
+	response, errMsg := cs.CreateCheckout(context.Background(), domain.CheckoutRequest{
+		UserAdminID:    userAdminID,
+		CustomerID:     customerID,
+		ProductDetails: []domain.ProductCheckoutRequest{{ProductID: productID, Quantity: quantity}},
+		Paid:           paid,
+		Change:         &change,
+	}, "")
+	if errMsg != nil {
+		t.Fatalf("CreateCheckout() error = %v", errMsg.Message())
+	}
+
+	return response
+}
+
+func getTestProductStock(t *testing.T, db *sql.DB, productID string) int {
+	t.Helper()
+
+	var stock int
+	err := db.QueryRow(`SELECT stock FROM products WHERE id = $1`, productID).Scan(&stock)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return stock
+}
+
+func withTestTx(t *testing.T, db *sql.DB, fn func(tx *sql.Tx) error) {
+	t.Helper()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	err = fn(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tx.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
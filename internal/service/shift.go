@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"eniqilo-store/internal/domain"
+	"eniqilo-store/internal/repository"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type ShiftService interface {
+	OpenShift(ctx context.Context, body domain.ShiftOpenRequest) (*domain.ShiftReport, domain.MessageErr)
+	GetCurrentShift(ctx context.Context, userAdminID string) (*domain.ShiftReport, domain.MessageErr)
+	GetShiftByID(ctx context.Context, id string, userAdmin domain.UserAdmin) (*domain.ShiftReport, domain.MessageErr)
+	CreateCashPayout(ctx context.Context, body domain.CashPayoutRequest) (*domain.ShiftReport, domain.MessageErr)
+	CloseShift(ctx context.Context, body domain.ShiftCloseRequest) (*domain.ShiftReport, domain.MessageErr)
+}
+
+type shiftService struct {
+	db              *sql.DB
+	shiftRepository repository.ShiftRepository
+}
+
+func NewShiftService(db *sql.DB, shiftRepository repository.ShiftRepository) ShiftService {
+	return &shiftService{
+		db:              db,
+		shiftRepository: shiftRepository,
+	}
+}
+
+func (ss *shiftService) OpenShift(ctx context.Context, body domain.ShiftOpenRequest) (*domain.ShiftReport, domain.MessageErr) {
+	shift := body.NewShift()
+	err := ss.shiftRepository.CreateShift(ctx, ss.db, shift)
+	if err != nil {
+		if err, ok := err.(*pgconn.PgError); ok {
+			if err.Code == "23505" {
+				return nil, domain.NewConflictError("staff already has an open shift")
+			}
+		}
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	report := shift.NewShiftReport(domain.ShiftTotals{})
+
+	return &report, nil
+}
+
+func (ss *shiftService) GetCurrentShift(ctx context.Context, userAdminID string) (*domain.ShiftReport, domain.MessageErr) {
+	shift, err := ss.shiftRepository.GetOpenShift(ctx, ss.db, userAdminID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if shift == nil {
+		return nil, domain.NewNotFoundError("staff has no open shift")
+	}
+
+	return ss.newShiftReport(ctx, *shift)
+}
+
+func (ss *shiftService) GetShiftByID(ctx context.Context, id string, userAdmin domain.UserAdmin) (*domain.ShiftReport, domain.MessageErr) {
+	shift, err := ss.shiftRepository.GetShiftByID(ctx, ss.db, id)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if shift == nil {
+		return nil, domain.NewNotFoundError("shift is not found")
+	}
+	if shift.UserAdminID != userAdmin.ID && !domain.HasPermission(userAdmin.Role, domain.PermissionShiftRead) {
+		return nil, domain.NewForbiddenError("only the cashier of the shift or a manager can see it")
+	}
+
+	return ss.newShiftReport(ctx, *shift)
+}
+
+func (ss *shiftService) CreateCashPayout(ctx context.Context, body domain.CashPayoutRequest) (*domain.ShiftReport, domain.MessageErr) {
+	tx, err := ss.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback()
+
+	shift, err := ss.shiftRepository.GetOpenShiftForShare(ctx, tx, body.UserAdminID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if shift == nil {
+		return nil, domain.NewNotFoundError("staff has no open shift")
+	}
+
+	err = ss.shiftRepository.CreateCashPayout(ctx, tx, body.NewCashPayout(shift.ID))
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	return ss.newShiftReport(ctx, *shift)
+}
+
+func (ss *shiftService) CloseShift(ctx context.Context, body domain.ShiftCloseRequest) (*domain.ShiftReport, domain.MessageErr) {
+	tx, err := ss.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback()
+
+	shift, err := ss.shiftRepository.GetOpenShiftForUpdate(ctx, tx, body.UserAdminID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if shift == nil {
+		return nil, domain.NewNotFoundError("staff has no open shift")
+	}
+
+	totals, err := ss.shiftRepository.GetShiftTotals(ctx, tx, shift.ID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	shift.Close(body, totals)
+
+	err = ss.shiftRepository.CloseShift(ctx, tx, *shift)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	report := shift.NewShiftReport(totals)
+
+	return &report, nil
+}
+
+func (ss *shiftService) newShiftReport(ctx context.Context, shift domain.Shift) (*domain.ShiftReport, domain.MessageErr) {
+	// read only, the transaction gives the totals one consistent snapshot
+	tx, err := ss.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback()
+
+	totals, err := ss.shiftRepository.GetShiftTotals(ctx, tx, shift.ID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	report := shift.NewShiftReport(totals)
+
+	return &report, nil
+}
+
+func openShiftID(ctx context.Context, tx *sql.Tx, shiftRepository repository.ShiftRepository, userAdminID string, required bool) (*string, domain.MessageErr) {
+	shift, err := shiftRepository.GetOpenShiftForShare(ctx, tx, userAdminID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if shift == nil {
+		if required {
+			return nil, domain.NewForbiddenError("open a shift first")
+		}
+		return nil, nil
+	}
+
+	return &shift.ID, nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"eniqilo-store/internal/domain"
+	"eniqilo-store/internal/repository"
+	"testing"
+)
+
+func TestCloseShiftExpectedCash(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	cs := newTestCheckoutService(db, true)
+	ss := NewShiftService(db, repository.NewShiftRepository())
+	cashier := createTestUserAdmin(t, db, domain.UserAdminRoleCashier)
+	customer := createTestCustomer(t, db)
+	product := createTestProduct(t, db, 1000, 100)
+
+	openingFloat := 1000
+	_, errMsg := ss.OpenShift(ctx, domain.ShiftOpenRequest{UserAdminID: cashier.ID, Till: "1", OpeningFloat: &openingFloat})
+	if errMsg != nil {
+		t.Fatalf("OpenShift() error = %v", errMsg.Message())
+	}
+
+	change := 500
+	_, errMsg = cs.CreateCheckout(ctx, domain.CheckoutRequest{
+		UserAdminID:    cashier.ID,
+		CustomerID:     customer.ID,
+		ProductDetails: []domain.ProductCheckoutRequest{{ProductID: product.ID, Quantity: 1}},
+		Paid:           1500,
+		Change:         &change,
+	}, "")
+	if errMsg != nil {
+		t.Fatalf("CreateCheckout() error = %v", errMsg.Message())
+	}
+	voided := createTestCheckout(t, cs, cashier.ID, customer.ID, product.ID, 2, 2000)
+	_, errMsg = cs.VoidCheckout(ctx, voided.CheckoutID, domain.VoidRequest{UserAdminID: cashier.ID, UserAdminRole: cashier.Role, Reason: "test"})
+	if errMsg != nil {
+		t.Fatalf("VoidCheckout() error = %v", errMsg.Message())
+	}
+	_, errMsg = ss.CreateCashPayout(ctx, domain.CashPayoutRequest{UserAdminID: cashier.ID, Amount: 200, Reason: "test"})
+	if errMsg != nil {
+		t.Fatalf("CreateCashPayout() error = %v", errMsg.Message())
+	}
+
+	report, errMsg := ss.CloseShift(ctx, domain.ShiftCloseRequest{
+		UserAdminID:   cashier.ID,
+		Denominations: []domain.Denomination{{Value: 1000, Count: 1}, {Value: 500, Count: 1}},
+	})
+	if errMsg != nil {
+		t.Fatalf("CloseShift() error = %v", errMsg.Message())
+	}
+	if report.ExpectedCash != 1800 || *report.CountedCash != 1500 || *report.OverShort != -300 {
+		t.Errorf("expected %d, counted %d, over/short %d, want 1800, 1500, -300", report.ExpectedCash, *report.CountedCash, *report.OverShort)
+	}
+
+	_, errMsg = ss.CloseShift(ctx, domain.ShiftCloseRequest{
+		UserAdminID:   cashier.ID,
+		Denominations: []domain.Denomination{{Value: 1000, Count: 1}},
+	})
+	if errMsg == nil {
+		t.Errorf("CloseShift() closed a shift twice")
+	}
+}
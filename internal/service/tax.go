@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"eniqilo-store/internal/domain"
+	"eniqilo-store/internal/repository"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type TaxRateService interface {
+	CreateTaxRate(ctx context.Context, taxRate domain.TaxRate) domain.MessageErr
+	GetTaxRates(ctx context.Context) ([]domain.TaxRateResponse, domain.MessageErr)
+	UpdateTaxRateByID(ctx context.Context, taxRate domain.TaxRate) domain.MessageErr
+	DeleteTaxRateByID(ctx context.Context, id string) domain.MessageErr
+}
+
+type taxRateService struct {
+	db                *sql.DB
+	taxRateRepository repository.TaxRateRepository
+}
+
+func NewTaxRateService(db *sql.DB, taxRateRepository repository.TaxRateRepository) TaxRateService {
+	return &taxRateService{
+		db:                db,
+		taxRateRepository: taxRateRepository,
+	}
+}
+
+func (ts *taxRateService) CreateTaxRate(ctx context.Context, taxRate domain.TaxRate) domain.MessageErr {
+	if err := validateTaxRateScope(taxRate); err != nil {
+		return err
+	}
+
+	err := ts.taxRateRepository.CreateTaxRate(ctx, ts.db, taxRate)
+	if err != nil {
+		return taxRateWriteError(err)
+	}
+
+	return nil
+}
+
+func (ts *taxRateService) GetTaxRates(ctx context.Context) ([]domain.TaxRateResponse, domain.MessageErr) {
+	taxRates, err := ts.taxRateRepository.GetTaxRates(ctx, ts.db)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	taxRateResponses := []domain.TaxRateResponse{}
+	for _, tr := range taxRates {
+		taxRateResponses = append(taxRateResponses, tr.NewTaxRateResponse())
+	}
+
+	return taxRateResponses, nil
+}
+
+func (ts *taxRateService) UpdateTaxRateByID(ctx context.Context, taxRate domain.TaxRate) domain.MessageErr {
+	if err := validateTaxRateScope(taxRate); err != nil {
+		return err
+	}
+
+	affRow, err := ts.taxRateRepository.UpdateTaxRateByID(ctx, ts.db, taxRate)
+	if err != nil {
+		return taxRateWriteError(err)
+	}
+	if affRow == 0 {
+		return domain.NewNotFoundError("tax rate is not found")
+	}
+
+	return nil
+}
+
+func (ts *taxRateService) DeleteTaxRateByID(ctx context.Context, id string) domain.MessageErr {
+	affRow, err := ts.taxRateRepository.DeleteTaxRateByID(ctx, ts.db, id)
+	if err != nil {
+		return domain.NewInternalServerError(err.Error())
+	}
+	if affRow == 0 {
+		return domain.NewNotFoundError("tax rate is not found")
+	}
+
+	return nil
+}
+
+func validateTaxRateScope(taxRate domain.TaxRate) domain.MessageErr {
+	if (taxRate.Category == nil) == (taxRate.ProductID == nil) {
+		return domain.NewBadRequestError("either category or productId is required")
+	}
+
+	return nil
+}
+
+func taxRateWriteError(err error) domain.MessageErr {
+	if err, ok := err.(*pgconn.PgError); ok {
+		if err.Code == "23505" {
+			return domain.NewConflictError("tax rate for this category or product already exists")
+		}
+		if err.Code == "23503" {
+			return domain.NewNotFoundError("product is not found")
+		}
+	}
+
+	return domain.NewInternalServerError(err.Error())
+}
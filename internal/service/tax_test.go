@@ -0,0 +1,35 @@
+package service
+
+import (
+	"eniqilo-store/internal/domain"
+	"net/http"
+	"testing"
+)
+
+func TestValidateTaxRateScope(t *testing.T) {
+	category := domain.ProductCategoryBeverages
+	productID := "6f1d6c3e-8a57-4a4e-9f0b-3b2a9d1c7e21"
+
+	tests := []struct {
+		name       string
+		taxRate    domain.TaxRate
+		wantStatus int
+	}{
+		{name: "category", taxRate: domain.TaxRate{Category: &category}},
+		{name: "product", taxRate: domain.TaxRate{ProductID: &productID}},
+		{name: "neither", taxRate: domain.TaxRate{}, wantStatus: http.StatusBadRequest},
+		{name: "both", taxRate: domain.TaxRate{Category: &category, ProductID: &productID}, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := 0
+			if errMsg := validateTaxRateScope(tt.taxRate); errMsg != nil {
+				status = errMsg.Status()
+			}
+			if status != tt.wantStatus {
+				t.Errorf("validateTaxRateScope() status = %d, want %d", status, tt.wantStatus)
+			}
+		})
+	}
+}
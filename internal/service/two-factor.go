@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"eniqilo-store/internal/domain"
+	"eniqilo-store/internal/repository"
+	"time"
+)
+
+type TwoFactorService interface {
+	GetTwoFactor(ctx context.Context, userAdmin domain.UserAdmin) (*domain.TwoFactorStatusResponse, domain.MessageErr)
+	EnrolTwoFactor(ctx context.Context, userAdmin domain.UserAdmin) (*domain.TwoFactorEnrolmentResponse, domain.MessageErr)
+	ConfirmTwoFactor(ctx context.Context, body domain.TwoFactorCodeRequest) (*domain.RecoveryCodesResponse, domain.MessageErr)
+	RegenerateRecoveryCodes(ctx context.Context, body domain.TwoFactorCodeRequest) (*domain.RecoveryCodesResponse, domain.MessageErr)
+	DisableTwoFactor(ctx context.Context, body domain.TwoFactorCodeRequest) domain.MessageErr
+	DeleteExpiredLoginChallenges(ctx context.Context) domain.MessageErr
+	verifyCode(ctx context.Context, tx *sql.Tx, body domain.TwoFactorCodeRequest, secret string, lastStep int64) (int64, domain.MessageErr)
+}
+
+type twoFactorService struct {
+	db                     *sql.DB
+	twoFactorRepository    repository.TwoFactorRepository
+	loginAttemptRepository repository.LoginAttemptRepository
+	totpIssuer             string
+	twoFactorRoles         []string
+}
+
+func NewTwoFactorService(db *sql.DB, twoFactorRepository repository.TwoFactorRepository, loginAttemptRepository repository.LoginAttemptRepository, totpIssuer string, twoFactorRoles []string) TwoFactorService {
+	return &twoFactorService{
+		db:                     db,
+		twoFactorRepository:    twoFactorRepository,
+		loginAttemptRepository: loginAttemptRepository,
+		totpIssuer:             totpIssuer,
+		twoFactorRoles:         twoFactorRoles,
+	}
+}
+
+func (t *twoFactorService) GetTwoFactor(ctx context.Context, userAdmin domain.UserAdmin) (*domain.TwoFactorStatusResponse, domain.MessageErr) {
+	twoFactor, err := t.twoFactorRepository.GetStaffTwoFactor(ctx, t.db, userAdmin.ID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	recoveryCodesLeft, err := t.twoFactorRepository.CountStaffRecoveryCodes(ctx, t.db, userAdmin.ID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	response := domain.TwoFactorStatusResponse{
+		Enabled:           twoFactor.Enabled(),
+		Required:          domain.TwoFactorRequired(userAdmin.Role, t.twoFactorRoles),
+		RecoveryCodesLeft: recoveryCodesLeft,
+	}
+	if twoFactor.Enabled() {
+		response.EnabledAt = twoFactor.EnabledAt
+	}
+
+	return &response, nil
+}
+
+func (t *twoFactorService) EnrolTwoFactor(ctx context.Context, userAdmin domain.UserAdmin) (*domain.TwoFactorEnrolmentResponse, domain.MessageErr) {
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback()
+
+	response, errMsg := startTwoFactorEnrolment(ctx, tx, t.twoFactorRepository, userAdmin, t.totpIssuer)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	return response, nil
+}
+
+func (t *twoFactorService) ConfirmTwoFactor(ctx context.Context, body domain.TwoFactorCodeRequest) (*domain.RecoveryCodesResponse, domain.MessageErr) {
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback()
+
+	errMsg := checkLoginThrottle(ctx, tx, t.loginAttemptRepository, body.LoginUserAdmin())
+	if errMsg != nil {
+		return nil, errMsg
+	}
+
+	twoFactor, err := t.twoFactorRepository.GetStaffTwoFactorForUpdate(ctx, tx, body.UserAdmin.ID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if twoFactor == nil || twoFactor.PendingSecret == nil {
+		return nil, domain.NewBadRequestError("start the enrolment first")
+	}
+
+	step, errMsg := t.verifyCode(ctx, tx, body, *twoFactor.PendingSecret, 0)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+
+	now := time.Now()
+	err = t.twoFactorRepository.EnableStaffTwoFactor(ctx, tx, body.UserAdmin.ID, now, step)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	recoveryCodes, codeHashes, err := domain.NewRecoveryCodes()
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	err = t.twoFactorRepository.ReplaceStaffRecoveryCodes(ctx, tx, body.UserAdmin.ID, codeHashes, now)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	return &domain.RecoveryCodesResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+func (t *twoFactorService) RegenerateRecoveryCodes(ctx context.Context, body domain.TwoFactorCodeRequest) (*domain.RecoveryCodesResponse, domain.MessageErr) {
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback()
+
+	errMsg := checkLoginThrottle(ctx, tx, t.loginAttemptRepository, body.LoginUserAdmin())
+	if errMsg != nil {
+		return nil, errMsg
+	}
+
+	twoFactor, err := t.twoFactorRepository.GetStaffTwoFactorForUpdate(ctx, tx, body.UserAdmin.ID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if !twoFactor.Enabled() {
+		return nil, domain.NewBadRequestError("two-factor authentication is not enabled")
+	}
+
+	step, errMsg := t.verifyCode(ctx, tx, body, *twoFactor.Secret, twoFactor.LastStep)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	err = t.twoFactorRepository.UpdateStaffTwoFactorLastStep(ctx, tx, body.UserAdmin.ID, step)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	recoveryCodes, codeHashes, err := domain.NewRecoveryCodes()
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	err = t.twoFactorRepository.ReplaceStaffRecoveryCodes(ctx, tx, body.UserAdmin.ID, codeHashes, time.Now())
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	return &domain.RecoveryCodesResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+func (t *twoFactorService) DisableTwoFactor(ctx context.Context, body domain.TwoFactorCodeRequest) domain.MessageErr {
+	if domain.TwoFactorRequired(body.UserAdmin.Role, t.twoFactorRoles) {
+		return domain.NewForbiddenError("two-factor authentication is required for the role")
+	}
+
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback()
+
+	errMsg := checkLoginThrottle(ctx, tx, t.loginAttemptRepository, body.LoginUserAdmin())
+	if errMsg != nil {
+		return errMsg
+	}
+
+	twoFactor, err := t.twoFactorRepository.GetStaffTwoFactorForUpdate(ctx, tx, body.UserAdmin.ID)
+	if err != nil {
+		return domain.NewInternalServerError(err.Error())
+	}
+	if !twoFactor.Enabled() {
+		return domain.NewBadRequestError("two-factor authentication is not enabled")
+	}
+
+	_, errMsg = t.verifyCode(ctx, tx, body, *twoFactor.Secret, twoFactor.LastStep)
+	if errMsg != nil {
+		return errMsg
+	}
+
+	err = t.twoFactorRepository.DeleteStaffTwoFactor(ctx, tx, body.UserAdmin.ID)
+	if err != nil {
+		return domain.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return domain.NewInternalServerError(err.Error())
+	}
+
+	return nil
+}
+
+func (t *twoFactorService) DeleteExpiredLoginChallenges(ctx context.Context) domain.MessageErr {
+	err := t.twoFactorRepository.DeleteExpiredLoginChallenges(ctx, t.db, time.Now())
+	if err != nil {
+		return domain.NewInternalServerError(err.Error())
+	}
+
+	return nil
+}
+
+// a wrong code counts as a failed login, so codes can't be guessed here instead
+func (t *twoFactorService) verifyCode(ctx context.Context, tx *sql.Tx, body domain.TwoFactorCodeRequest, secret string, lastStep int64) (int64, domain.MessageErr) {
+	step, ok := domain.VerifyTOTP(secret, body.Code, time.Now(), lastStep)
+	if !ok {
+		errMsg := recordLoginAttempt(ctx, tx, t.loginAttemptRepository, domain.NewLoginAttempt(body.LoginUserAdmin(), &body.UserAdmin.ID, domain.LoginAttemptInvalidSecondFactor))
+		if errMsg != nil {
+			return 0, errMsg
+		}
+
+		return 0, domain.NewBadRequestError("code is invalid")
+	}
+
+	return step, nil
+}
+
+// the new secret is only used once one of its codes is verified
+func startTwoFactorEnrolment(ctx context.Context, tx *sql.Tx, twoFactorRepository repository.TwoFactorRepository, userAdmin domain.UserAdmin, issuer string) (*domain.TwoFactorEnrolmentResponse, domain.MessageErr) {
+	twoFactor, err := twoFactorRepository.GetStaffTwoFactorForUpdate(ctx, tx, userAdmin.ID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if twoFactor.Enabled() {
+		return nil, domain.NewConflictError("two-factor authentication is already enabled")
+	}
+
+	secret, err := domain.NewTOTPSecret()
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	err = twoFactorRepository.SetStaffTwoFactorPendingSecret(ctx, tx, userAdmin.ID, secret)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	return &domain.TwoFactorEnrolmentResponse{
+		Secret:          secret,
+		ProvisioningURI: domain.TOTPProvisioningURI(issuer, userAdmin.PhoneNumber, secret),
+	}, nil
+}
@@ -5,33 +5,71 @@ import (
 	"database/sql"
 	"eniqilo-store/internal/domain"
 	"eniqilo-store/internal/repository"
+	"fmt"
+	"math"
 	"time"
 
 	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgconn"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type UserAdminService interface {
 	RegisterUserAdminService(ctx context.Context, userAdmin domain.RegisterUserAdminRequest) (*domain.UserAdminResponseWithAccessToken, domain.MessageErr)
-	LoginUserAdminService(ctx context.Context, userAdmin domain.LoginUserAdmin) (*domain.UserAdminResponseWithAccessToken, domain.MessageErr)
-	generateToken(userAdmin domain.UserAdmin) (string, error)
-	mapUserAdminResponseWithAccessToken(userAdmin *domain.UserAdmin, token string) *domain.UserAdminResponseWithAccessToken
+	LoginUserAdminService(ctx context.Context, userAdmin domain.LoginUserAdmin) (*domain.UserAdminResponseWithAccessToken, *domain.LoginChallengeResponse, domain.MessageErr)
+	GetUserAdmins(ctx context.Context) ([]domain.UserAdminResponse, domain.MessageErr)
+	UpdateUserAdminRole(ctx context.Context, id string, userAdminID string, body domain.UserAdminRoleRequest) (*domain.UserAdminResponse, domain.MessageErr)
+	CreateStaffInvitation(ctx context.Context, body domain.StaffInvitationRequest) (*domain.StaffInvitationResponse, domain.MessageErr)
+	GetStaffInvitations(ctx context.Context) ([]domain.StaffInvitationResponse, domain.MessageErr)
+	RevokeStaffInvitation(ctx context.Context, id string) domain.MessageErr
+	RefreshTokenService(ctx context.Context, body domain.RefreshTokenRequest) (*domain.UserAdminResponseWithAccessToken, domain.MessageErr)
+	LogoutUserAdminService(ctx context.Context, body domain.LogoutRequest) domain.MessageErr
+	RevokeUserAdminSessions(ctx context.Context, id string) domain.MessageErr
+	DeleteExpiredStaffSessions(ctx context.Context) domain.MessageErr
+	GetLoginAttempts(ctx context.Context, queryParams domain.LoginAttemptQueryParams) ([]domain.LoginAttemptResponse, domain.MessageErr)
+	EnrolLoginService(ctx context.Context, body domain.LoginChallengeRequest) (*domain.TwoFactorEnrolmentResponse, domain.MessageErr)
+	VerifyLoginService(ctx context.Context, body domain.LoginVerifyRequest) (*domain.UserAdminResponseWithAccessToken, domain.MessageErr)
+	startSession(ctx context.Context, tx *sql.Tx, userAdmin domain.UserAdmin) (*domain.UserAdminResponseWithAccessToken, error)
+	generateToken(userAdmin domain.UserAdmin, sessionID string) (string, error)
+	mapUserAdminResponseWithAccessToken(userAdmin *domain.UserAdmin, token string, refreshToken string) *domain.UserAdminResponseWithAccessToken
 }
 
 type userAdminService struct {
-	db                  *sql.DB
-	userAdminRepository repository.UserAdminRepository
-	jwtSecret           string
-	bcryptSalt          int
+	db                        *sql.DB
+	userAdminRepository       repository.UserAdminRepository
+	staffInvitationRepository repository.StaffInvitationRepository
+	staffSessionRepository    repository.StaffSessionRepository
+	loginAttemptRepository    repository.LoginAttemptRepository
+	twoFactorRepository       repository.TwoFactorRepository
+	jwtSecret                 string
+	bcryptSalt                int
+	invitationTTL             time.Duration
+	accessTokenTTL            time.Duration
+	refreshTokenTTL           time.Duration
+	totpIssuer                string
+	twoFactorRoles            []string
+	dummyPasswordHash         string
 }
 
-func NewUserAdminService(db *sql.DB, userAdminRepository repository.UserAdminRepository, jwtSecret string, bcryptSalt int) UserAdminService {
+func NewUserAdminService(db *sql.DB, userAdminRepository repository.UserAdminRepository, staffInvitationRepository repository.StaffInvitationRepository, staffSessionRepository repository.StaffSessionRepository, loginAttemptRepository repository.LoginAttemptRepository, twoFactorRepository repository.TwoFactorRepository, jwtSecret string, bcryptSalt int, invitationTTL time.Duration, accessTokenTTL time.Duration, refreshTokenTTL time.Duration, totpIssuer string, twoFactorRoles []string) UserAdminService {
+	dummyPasswordHash, _ := bcrypt.GenerateFromPassword([]byte(uuid.NewString()), bcryptSalt)
+
 	return &userAdminService{
-		db:                  db,
-		userAdminRepository: userAdminRepository,
-		jwtSecret:           jwtSecret,
-		bcryptSalt:          bcryptSalt,
+		db:                        db,
+		userAdminRepository:       userAdminRepository,
+		staffInvitationRepository: staffInvitationRepository,
+		staffSessionRepository:    staffSessionRepository,
+		loginAttemptRepository:    loginAttemptRepository,
+		twoFactorRepository:       twoFactorRepository,
+		jwtSecret:                 jwtSecret,
+		bcryptSalt:                bcryptSalt,
+		invitationTTL:             invitationTTL,
+		accessTokenTTL:            accessTokenTTL,
+		refreshTokenTTL:           refreshTokenTTL,
+		totpIssuer:                totpIssuer,
+		twoFactorRoles:            twoFactorRoles,
+		dummyPasswordHash:         string(dummyPasswordHash),
 	}
 }
 
@@ -44,7 +82,39 @@ func (u *userAdminService) RegisterUserAdminService(ctx context.Context, userAdm
 	userAdmin := userAdminPayload.NewUserAdminFromDTO()
 	userAdmin.Password = string(hashedPassword)
 
-	err = u.userAdminRepository.CreateUserAdminRepository(ctx, u.db, userAdmin)
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback()
+
+	var invitation *domain.StaffInvitation
+	if len(userAdminPayload.InvitationToken) > 0 {
+		invitation, err = u.staffInvitationRepository.GetStaffInvitationByTokenHashForUpdate(ctx, tx, domain.HashToken(userAdminPayload.InvitationToken))
+		if err != nil {
+			return nil, domain.NewInternalServerError(err.Error())
+		}
+		if invitation == nil || !invitation.Usable(time.Now()) {
+			return nil, domain.NewForbiddenError("invitation is invalid or expired")
+		}
+		userAdmin.Role = invitation.Role
+	} else {
+		// only the first staff member, the owner, registers without an invitation
+		err = u.userAdminRepository.LockUserAdmins(ctx, tx)
+		if err != nil {
+			return nil, domain.NewInternalServerError(err.Error())
+		}
+		exists, err := u.userAdminRepository.CheckUserAdminExists(ctx, tx)
+		if err != nil {
+			return nil, domain.NewInternalServerError(err.Error())
+		}
+		if exists {
+			return nil, domain.NewForbiddenError("an invitation is required to register")
+		}
+		userAdmin.Role = domain.UserAdminRoleOwner
+	}
+
+	err = u.userAdminRepository.CreateUserAdminRepository(ctx, tx, userAdmin)
 	if err != nil {
 		if err, ok := err.(*pgconn.PgError); ok {
 			if err.Code == "23505" {
@@ -55,38 +125,582 @@ func (u *userAdminService) RegisterUserAdminService(ctx context.Context, userAdm
 		return nil, domain.NewInternalServerError(err.Error())
 	}
 
-	token, err := u.generateToken(userAdmin)
+	if invitation != nil {
+		err = u.staffInvitationRepository.UseStaffInvitation(ctx, tx, invitation.ID, userAdmin.ID, userAdmin.CreatedAt)
+		if err != nil {
+			return nil, domain.NewInternalServerError(err.Error())
+		}
+	}
+
+	response, err := u.startSession(ctx, tx, userAdmin)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit()
 	if err != nil {
 		return nil, domain.NewInternalServerError(err.Error())
 	}
 
-	return u.mapUserAdminResponseWithAccessToken(&userAdmin, token), nil
+	return response, nil
 }
 
-func (u *userAdminService) LoginUserAdminService(ctx context.Context, userAdminPayload domain.LoginUserAdmin) (*domain.UserAdminResponseWithAccessToken, domain.MessageErr) {
+func (u *userAdminService) LoginUserAdminService(ctx context.Context, userAdminPayload domain.LoginUserAdmin) (*domain.UserAdminResponseWithAccessToken, *domain.LoginChallengeResponse, domain.MessageErr) {
+	// the same answer whether the phone number exists or not
+	invalidLoginErr := domain.NewUnauthenticatedError("phone number or password is invalid")
+
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, domain.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback()
+
+	errMsg := checkLoginThrottle(ctx, tx, u.loginAttemptRepository, userAdminPayload)
+	if errMsg != nil {
+		return nil, nil, errMsg
+	}
+
 	userAdmin, err := u.userAdminRepository.GetUserByPhoneNumberRepository(ctx, u.db, userAdminPayload.PhoneNumber)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, nil, domain.NewInternalServerError(err.Error())
+	}
+
+	// an unknown phone number costs a bcrypt comparison too, so timing doesn't tell
+	passwordHash := u.dummyPasswordHash
+	if userAdmin != nil {
+		passwordHash = userAdmin.Password
+	}
+	err = bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(userAdminPayload.Password))
+	if userAdmin == nil || err != nil {
+		var userAdminID *string
+		if userAdmin != nil {
+			userAdminID = &userAdmin.ID
+		}
+		errMsg := recordLoginAttempt(ctx, tx, u.loginAttemptRepository, domain.NewLoginAttempt(userAdminPayload, userAdminID, domain.LoginAttemptInvalidCredentials))
+		if errMsg != nil {
+			return nil, nil, errMsg
+		}
+
+		return nil, nil, invalidLoginErr
+	}
+
+	// with two-factor authentication the password only gets a challenge
+	challengeType := ""
+	twoFactor, err := u.twoFactorRepository.GetStaffTwoFactor(ctx, u.db, userAdmin.ID)
+	if err != nil {
+		return nil, nil, domain.NewInternalServerError(err.Error())
+	}
+	if twoFactor.Enabled() {
+		challengeType = domain.LoginChallengeVerify
+	} else if domain.TwoFactorRequired(userAdmin.Role, u.twoFactorRoles) {
+		challengeType = domain.LoginChallengeEnrol
+	}
+	if len(challengeType) > 0 {
+		err = u.loginAttemptRepository.CreateLoginAttempt(ctx, tx, domain.NewLoginAttempt(userAdminPayload, &userAdmin.ID, domain.LoginAttemptPasswordVerified))
+		if err != nil {
+			return nil, nil, domain.NewInternalServerError(err.Error())
+		}
+
+		challenge, challengeToken, err := domain.NewLoginChallenge(userAdmin.ID, challengeType)
+		if err != nil {
+			return nil, nil, domain.NewInternalServerError(err.Error())
+		}
+		err = u.twoFactorRepository.CreateLoginChallenge(ctx, tx, challenge)
+		if err != nil {
+			return nil, nil, domain.NewInternalServerError(err.Error())
+		}
+
+		err = tx.Commit()
+		if err != nil {
+			return nil, nil, domain.NewInternalServerError(err.Error())
+		}
+
+		return nil, &domain.LoginChallengeResponse{TwoFactor: challengeType, ChallengeToken: challengeToken}, nil
+	}
+
+	err = u.loginAttemptRepository.CreateLoginAttempt(ctx, tx, domain.NewLoginAttempt(userAdminPayload, &userAdmin.ID, domain.LoginAttemptSuccess))
+	if err != nil {
+		return nil, nil, domain.NewInternalServerError(err.Error())
+	}
+
+	response, err := u.startSession(ctx, tx, *userAdmin)
+	if err != nil {
+		return nil, nil, domain.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, nil, domain.NewInternalServerError(err.Error())
+	}
+
+	return response, nil, nil
+}
+
+func (u *userAdminService) EnrolLoginService(ctx context.Context, body domain.LoginChallengeRequest) (*domain.TwoFactorEnrolmentResponse, domain.MessageErr) {
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback()
+
+	challenge, err := u.twoFactorRepository.GetLoginChallengeForUpdate(ctx, tx, domain.HashToken(body.ChallengeToken))
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if challenge == nil || !challenge.Usable(time.Now()) || challenge.Type != domain.LoginChallengeEnrol {
+		return nil, domain.NewUnauthenticatedError("login challenge is invalid or expired")
+	}
+
+	userAdmin, err := u.userAdminRepository.GetUserByIDAdminRepository(ctx, tx, challenge.UserAdminID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	response, errMsg := startTwoFactorEnrolment(ctx, tx, u.twoFactorRepository, *userAdmin, u.totpIssuer)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	return response, nil
+}
+
+// an enrolment is confirmed by its first code and returns the recovery codes
+func (u *userAdminService) VerifyLoginService(ctx context.Context, body domain.LoginVerifyRequest) (*domain.UserAdminResponseWithAccessToken, domain.MessageErr) {
+	if len(body.Code) == 0 && len(body.RecoveryCode) == 0 {
+		return nil, domain.NewBadRequestError("code or recoveryCode is required")
+	}
+	invalidChallengeErr := domain.NewUnauthenticatedError("login challenge is invalid or expired")
+
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback()
+
+	challenge, err := u.twoFactorRepository.GetLoginChallengeForUpdate(ctx, tx, domain.HashToken(body.ChallengeToken))
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	now := time.Now()
+	if challenge == nil || !challenge.Usable(now) {
+		return nil, invalidChallengeErr
+	}
+
+	userAdmin, err := u.userAdminRepository.GetUserByIDAdminRepository(ctx, tx, challenge.UserAdminID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	loginPayload := domain.LoginUserAdmin{PhoneNumber: userAdmin.PhoneNumber, IP: body.IP, UserAgent: body.UserAgent}
+
+	errMsg := checkLoginThrottle(ctx, tx, u.loginAttemptRepository, loginPayload)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+
+	twoFactor, err := u.twoFactorRepository.GetStaffTwoFactorForUpdate(ctx, tx, userAdmin.ID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	valid := false
+	var recoveryCodes []string
+	switch challenge.Type {
+	case domain.LoginChallengeEnrol:
+		if twoFactor == nil || twoFactor.PendingSecret == nil {
+			return nil, domain.NewBadRequestError("start the enrolment first")
+		}
+		if len(body.Code) == 0 {
+			return nil, domain.NewBadRequestError("code is required to confirm the enrolment")
+		}
+
+		step, ok := domain.VerifyTOTP(*twoFactor.PendingSecret, body.Code, now, 0)
+		if ok {
+			valid = true
+			err = u.twoFactorRepository.EnableStaffTwoFactor(ctx, tx, userAdmin.ID, now, step)
+			if err != nil {
+				return nil, domain.NewInternalServerError(err.Error())
+			}
+
+			var codeHashes []string
+			recoveryCodes, codeHashes, err = domain.NewRecoveryCodes()
+			if err != nil {
+				return nil, domain.NewInternalServerError(err.Error())
+			}
+			err = u.twoFactorRepository.ReplaceStaffRecoveryCodes(ctx, tx, userAdmin.ID, codeHashes, now)
+			if err != nil {
+				return nil, domain.NewInternalServerError(err.Error())
+			}
+		}
+	default:
+		// two-factor authentication was turned off since the challenge
+		if !twoFactor.Enabled() {
+			return nil, invalidChallengeErr
+		}
+
+		if len(body.RecoveryCode) > 0 {
+			affRow, err := u.twoFactorRepository.UseStaffRecoveryCode(ctx, tx, userAdmin.ID, domain.HashRecoveryCode(body.RecoveryCode), now)
+			if err != nil {
+				return nil, domain.NewInternalServerError(err.Error())
+			}
+			valid = affRow > 0
+		} else {
+			step, ok := domain.VerifyTOTP(*twoFactor.Secret, body.Code, now, twoFactor.LastStep)
+			if ok {
+				valid = true
+				err = u.twoFactorRepository.UpdateStaffTwoFactorLastStep(ctx, tx, userAdmin.ID, step)
+				if err != nil {
+					return nil, domain.NewInternalServerError(err.Error())
+				}
+			}
+		}
+	}
+
+	if !valid {
+		err = u.twoFactorRepository.FailLoginChallenge(ctx, tx, challenge.TokenHash)
+		if err != nil {
+			return nil, domain.NewInternalServerError(err.Error())
+		}
+		errMsg := recordLoginAttempt(ctx, tx, u.loginAttemptRepository, domain.NewLoginAttempt(loginPayload, &userAdmin.ID, domain.LoginAttemptInvalidSecondFactor))
+		if errMsg != nil {
+			return nil, errMsg
+		}
+
+		return nil, domain.NewUnauthenticatedError("code is invalid")
+	}
+
+	err = u.twoFactorRepository.UseLoginChallenge(ctx, tx, challenge.TokenHash, now)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	err = u.loginAttemptRepository.CreateLoginAttempt(ctx, tx, domain.NewLoginAttempt(loginPayload, &userAdmin.ID, domain.LoginAttemptSuccess))
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	response, err := u.startSession(ctx, tx, *userAdmin)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	response.RecoveryCodes = recoveryCodes
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	return response, nil
+}
+
+func (u *userAdminService) GetLoginAttempts(ctx context.Context, queryParams domain.LoginAttemptQueryParams) ([]domain.LoginAttemptResponse, domain.MessageErr) {
+	limit, offset := queryParams.LimitOffset()
+	attempts, err := u.loginAttemptRepository.GetLoginAttempts(ctx, u.db, queryParams, limit, offset)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	responses := []domain.LoginAttemptResponse{}
+	for _, la := range attempts {
+		responses = append(responses, la.NewLoginAttemptResponse())
+	}
+
+	return responses, nil
+}
+
+func (u *userAdminService) RefreshTokenService(ctx context.Context, body domain.RefreshTokenRequest) (*domain.UserAdminResponseWithAccessToken, domain.MessageErr) {
+	invalidTokenErr := domain.NewUnauthenticatedError("invalid refresh token")
+
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback()
+
+	refreshToken, err := u.staffSessionRepository.GetStaffRefreshTokenForUpdate(ctx, tx, domain.HashToken(body.RefreshToken))
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if refreshToken == nil {
+		return nil, invalidTokenErr
+	}
+
+	session, err := u.staffSessionRepository.GetStaffSessionForUpdate(ctx, tx, refreshToken.SessionID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if session == nil || session.RevokedAt != nil {
+		return nil, invalidTokenErr
+	}
+
+	now := time.Now()
+	if refreshToken.UsedAt != nil {
+		// someone else holds a copy of the token, end the whole session
+		err = u.staffSessionRepository.RevokeStaffSession(ctx, tx, session.ID, now)
+		if err != nil {
+			return nil, domain.NewInternalServerError(err.Error())
+		}
+		err = tx.Commit()
+		if err != nil {
+			return nil, domain.NewInternalServerError(err.Error())
+		}
+
+		return nil, domain.NewUnauthenticatedError("refresh token was already used, the session is revoked")
+	}
+	if !now.Before(refreshToken.ExpiresAt) {
+		return nil, invalidTokenErr
+	}
+
+	err = u.staffSessionRepository.UseStaffRefreshToken(ctx, tx, refreshToken.TokenHash, now)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	nextRefreshToken, nextToken, err := session.NewRefreshToken(u.refreshTokenTTL)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	err = u.staffSessionRepository.CreateStaffRefreshToken(ctx, tx, nextRefreshToken)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	userAdmin, err := u.userAdminRepository.GetUserByIDAdminRepository(ctx, tx, session.UserAdminID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	// staff whose role needs a second factor must set it up to keep a session
+	if domain.TwoFactorRequired(userAdmin.Role, u.twoFactorRoles) {
+		twoFactor, err := u.twoFactorRepository.GetStaffTwoFactorForUpdate(ctx, tx, userAdmin.ID)
+		if err != nil {
+			return nil, domain.NewInternalServerError(err.Error())
+		}
+		if !twoFactor.Enabled() {
+			return nil, domain.NewUnauthenticatedError("two-factor authentication is required, log in again")
+		}
+	}
+
+	token, err := u.generateToken(*userAdmin, session.ID)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	return u.mapUserAdminResponseWithAccessToken(userAdmin, token, nextToken), nil
+}
+
+func (u *userAdminService) LogoutUserAdminService(ctx context.Context, body domain.LogoutRequest) domain.MessageErr {
+	if body.AllSessions {
+		_, err := u.staffSessionRepository.RevokeStaffSessionsByUserAdminID(ctx, u.db, body.UserAdminID, time.Now())
+		if err != nil {
+			return domain.NewInternalServerError(err.Error())
+		}
+
+		return nil
+	}
+
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.NewInternalServerError(err.Error())
+	}
+	defer tx.Rollback()
+
+	err = u.staffSessionRepository.RevokeStaffSession(ctx, tx, body.SessionID, time.Now())
+	if err != nil {
+		return domain.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return domain.NewInternalServerError(err.Error())
+	}
+
+	return nil
+}
+
+func (u *userAdminService) RevokeUserAdminSessions(ctx context.Context, id string) domain.MessageErr {
+	_, err := u.staffSessionRepository.RevokeStaffSessionsByUserAdminID(ctx, u.db, id, time.Now())
+	if err != nil {
+		return domain.NewInternalServerError(err.Error())
+	}
+
+	return nil
+}
+
+func (u *userAdminService) DeleteExpiredStaffSessions(ctx context.Context) domain.MessageErr {
+	// access tokens outlive the last refresh token by their own ttl
+	err := u.staffSessionRepository.DeleteExpiredStaffSessions(ctx, u.db, time.Now().Add(-u.accessTokenTTL))
+	if err != nil {
+		return domain.NewInternalServerError(err.Error())
+	}
+
+	return nil
+}
+
+func (u *userAdminService) GetUserAdmins(ctx context.Context) ([]domain.UserAdminResponse, domain.MessageErr) {
+	userAdmins, err := u.userAdminRepository.GetUserAdmins(ctx, u.db)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	responses := []domain.UserAdminResponse{}
+	for _, ua := range userAdmins {
+		responses = append(responses, ua.NewUserAdminResponse())
+	}
+
+	return responses, nil
+}
+
+func (u *userAdminService) UpdateUserAdminRole(ctx context.Context, id string, userAdminID string, body domain.UserAdminRoleRequest) (*domain.UserAdminResponse, domain.MessageErr) {
+	// owners can't demote themselves, so the store always keeps one
+	if id == userAdminID {
+		return nil, domain.NewForbiddenError("staff can't change their own role")
+	}
+
+	userAdmin, err := u.userAdminRepository.UpdateUserAdminRole(ctx, u.db, id, body.Role)
 	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+	if userAdmin == nil {
 		return nil, domain.NewNotFoundError("staff is not found")
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(userAdmin.Password), []byte(userAdminPayload.Password))
+	// tokens of the old role must not outlive it, and a role that needs two-factor has to log in with it
+	errMsg := u.RevokeUserAdminSessions(ctx, userAdmin.ID)
+	if errMsg != nil {
+		return nil, errMsg
+	}
+	response := userAdmin.NewUserAdminResponse()
+
+	return &response, nil
+}
+
+func (u *userAdminService) CreateStaffInvitation(ctx context.Context, body domain.StaffInvitationRequest) (*domain.StaffInvitationResponse, domain.MessageErr) {
+	if body.Role == domain.UserAdminRoleOwner && !domain.HasPermission(body.UserAdminRole, domain.PermissionStaffManage) {
+		return nil, domain.NewForbiddenError("only an owner can invite an owner")
+	}
+
+	invitation, token, err := body.NewStaffInvitation(u.invitationTTL)
 	if err != nil {
-		return nil, domain.NewBadRequestError("invalid password")
+		return nil, domain.NewInternalServerError(err.Error())
 	}
+	err = u.staffInvitationRepository.CreateStaffInvitation(ctx, u.db, invitation)
+	if err != nil {
+		return nil, domain.NewInternalServerError(err.Error())
+	}
+
+	response := invitation.NewStaffInvitationResponse()
+	response.Token = token
 
-	token, err := u.generateToken(*userAdmin)
+	return &response, nil
+}
+
+func (u *userAdminService) GetStaffInvitations(ctx context.Context) ([]domain.StaffInvitationResponse, domain.MessageErr) {
+	invitations, err := u.staffInvitationRepository.GetPendingStaffInvitations(ctx, u.db, time.Now())
 	if err != nil {
 		return nil, domain.NewInternalServerError(err.Error())
 	}
 
-	return u.mapUserAdminResponseWithAccessToken(userAdmin, token), nil
+	responses := []domain.StaffInvitationResponse{}
+	for _, si := range invitations {
+		responses = append(responses, si.NewStaffInvitationResponse())
+	}
+
+	return responses, nil
+}
+
+func (u *userAdminService) RevokeStaffInvitation(ctx context.Context, id string) domain.MessageErr {
+	affRow, err := u.staffInvitationRepository.RevokeStaffInvitation(ctx, u.db, id, time.Now())
+	if err != nil {
+		return domain.NewInternalServerError(err.Error())
+	}
+	if affRow == 0 {
+		return domain.NewNotFoundError("invitation is not found")
+	}
+
+	return nil
+}
+
+func checkLoginThrottle(ctx context.Context, tx *sql.Tx, loginAttemptRepository repository.LoginAttemptRepository, body domain.LoginUserAdmin) domain.MessageErr {
+	err := loginAttemptRepository.LockLoginAttempts(ctx, tx, body.PhoneNumber)
+	if err != nil {
+		return domain.NewInternalServerError(err.Error())
+	}
+
+	now := time.Now()
+	accountFailures, err := loginAttemptRepository.GetAccountLoginFailures(ctx, tx, body.PhoneNumber, now.Add(-domain.LoginFailureWindow))
+	if err != nil {
+		return domain.NewInternalServerError(err.Error())
+	}
+	ipFailures, err := loginAttemptRepository.GetIPLoginFailures(ctx, tx, body.IP, now.Add(-domain.LoginFailureWindow))
+	if err != nil {
+		return domain.NewInternalServerError(err.Error())
+	}
+	retryAfter := domain.LoginRetryAfter(accountFailures, ipFailures, now)
+	if retryAfter > 0 {
+		errMsg := recordLoginAttempt(ctx, tx, loginAttemptRepository, domain.NewLoginAttempt(body, nil, domain.LoginAttemptThrottled))
+		if errMsg != nil {
+			return errMsg
+		}
+
+		return domain.NewTooManyRequestsError(fmt.Sprintf("too many failed logins, try again in %d seconds", int(math.Ceil(retryAfter.Seconds()))))
+	}
+
+	return nil
+}
+
+// committed so the refused login is kept
+func recordLoginAttempt(ctx context.Context, tx *sql.Tx, loginAttemptRepository repository.LoginAttemptRepository, attempt domain.LoginAttempt) domain.MessageErr {
+	err := loginAttemptRepository.CreateLoginAttempt(ctx, tx, attempt)
+	if err != nil {
+		return domain.NewInternalServerError(err.Error())
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return domain.NewInternalServerError(err.Error())
+	}
+
+	return nil
+}
+
+func (u *userAdminService) startSession(ctx context.Context, tx *sql.Tx, userAdmin domain.UserAdmin) (*domain.UserAdminResponseWithAccessToken, error) {
+	session := domain.NewStaffSession(userAdmin.ID)
+	err := u.staffSessionRepository.CreateStaffSession(ctx, tx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, plainRefreshToken, err := session.NewRefreshToken(u.refreshTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+	err = u.staffSessionRepository.CreateStaffRefreshToken(ctx, tx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := u.generateToken(userAdmin, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.mapUserAdminResponseWithAccessToken(&userAdmin, token, plainRefreshToken), nil
 }
 
-func (u *userAdminService) generateToken(userAdmin domain.UserAdmin) (string, error) {
+func (u *userAdminService) generateToken(userAdmin domain.UserAdmin, sessionID string) (string, error) {
 	claims := jwt.MapClaims{
 		"id":          userAdmin.ID,
 		"phoneNumber": userAdmin.PhoneNumber,
-		"exp":         time.Now().Add(time.Hour * 8).Unix(),
+		"sessionId":   sessionID,
+		"exp":         time.Now().Add(u.accessTokenTTL).Unix(),
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -95,11 +709,13 @@ func (u *userAdminService) generateToken(userAdmin domain.UserAdmin) (string, er
 	return tokenString, err
 }
 
-func (u *userAdminService) mapUserAdminResponseWithAccessToken(userAdmin *domain.UserAdmin, token string) *domain.UserAdminResponseWithAccessToken {
+func (u *userAdminService) mapUserAdminResponseWithAccessToken(userAdmin *domain.UserAdmin, token string, refreshToken string) *domain.UserAdminResponseWithAccessToken {
 	return &domain.UserAdminResponseWithAccessToken{
-		ID:          userAdmin.ID,
-		Name:        userAdmin.Name,
-		PhoneNumber: userAdmin.PhoneNumber,
-		AccessToken: token,
+		ID:           userAdmin.ID,
+		Name:         userAdmin.Name,
+		PhoneNumber:  userAdmin.PhoneNumber,
+		Role:         userAdmin.Role,
+		AccessToken:  token,
+		RefreshToken: refreshToken,
 	}
 }
@@ -0,0 +1,397 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"eniqilo-store/internal/domain"
+	"eniqilo-store/internal/repository"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestVerifyLoginSecondFactorSingleUse(t *testing.T) {
+	db := newTestDB(t)
+	twoFactorRepository := repository.NewTwoFactorRepository()
+	us := newTestUserAdminService(db)
+
+	// codes are picked by the index of the recovery code, -1 is the current
+	// authenticator code
+	tests := []struct {
+		name         string
+		codes        []int
+		wantStatuses []int
+	}{
+		{name: "recovery code used twice", codes: []int{0, 0}, wantStatuses: []int{http.StatusOK, http.StatusUnauthorized}},
+		{name: "two recovery codes", codes: []int{0, 1}, wantStatuses: []int{http.StatusOK, http.StatusOK}},
+		{name: "authenticator code used twice", codes: []int{-1, -1}, wantStatuses: []int{http.StatusOK, http.StatusUnauthorized}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			owner := createTestUserAdmin(t, db, domain.UserAdminRoleOwner)
+			secret, recoveryCodes := enableTestTwoFactor(t, db, twoFactorRepository, owner.ID)
+
+			for i, code := range tt.codes {
+				challenge, token, err := domain.NewLoginChallenge(owner.ID, domain.LoginChallengeVerify)
+				if err != nil {
+					t.Fatal(err)
+				}
+				withTestTx(t, db, func(tx *sql.Tx) error {
+					return twoFactorRepository.CreateLoginChallenge(ctx, tx, challenge)
+				})
+
+				body := domain.LoginVerifyRequest{ChallengeToken: token}
+				if code < 0 {
+					totp, err := domain.TOTPCode(secret, domain.TOTPStep(time.Now()))
+					if err != nil {
+						t.Fatal(err)
+					}
+					body.Code = totp
+				} else {
+					body.RecoveryCode = recoveryCodes[code]
+				}
+
+				_, errMsg := us.VerifyLoginService(ctx, body)
+				status := http.StatusOK
+				if errMsg != nil {
+					status = errMsg.Status()
+				}
+				if status != tt.wantStatuses[i] {
+					t.Errorf("attempt %d: VerifyLoginService() status = %d, want %d", i, status, tt.wantStatuses[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRefreshTokenTwoFactorRequired(t *testing.T) {
+	db := newTestDB(t)
+	us := newTestUserAdminService(db)
+
+	tests := []struct {
+		name       string
+		role       string
+		twoFactor  bool
+		wantStatus int
+	}{
+		{name: "role without two-factor", role: domain.UserAdminRoleCashier, wantStatus: http.StatusOK},
+		{name: "role with two-factor set up", role: domain.UserAdminRoleOwner, twoFactor: true, wantStatus: http.StatusOK},
+		{name: "role with two-factor not set up", role: domain.UserAdminRoleOwner, wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			userAdmin := createTestUserAdmin(t, db, tt.role)
+			if tt.twoFactor {
+				enableTestTwoFactor(t, db, repository.NewTwoFactorRepository(), userAdmin.ID)
+			}
+
+			token := createTestStaffSession(t, db, userAdmin.ID, time.Hour)
+
+			_, errMsg := us.RefreshTokenService(ctx, domain.RefreshTokenRequest{RefreshToken: token})
+			status := http.StatusOK
+			if errMsg != nil {
+				status = errMsg.Status()
+			}
+			if status != tt.wantStatus {
+				t.Errorf("RefreshTokenService() status = %d, want %d", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	us := newTestUserAdminService(db)
+	cashier := createTestUserAdmin(t, db, domain.UserAdminRoleCashier)
+	tokens := map[string]string{"first": createTestStaffSession(t, db, cashier.ID, time.Hour)}
+
+	tests := []struct {
+		name       string
+		token      string
+		next       string
+		wantStatus int
+	}{
+		{name: "refresh", token: "first", next: "second", wantStatus: http.StatusOK},
+		{name: "refresh with the rotated token", token: "second", next: "third", wantStatus: http.StatusOK},
+		{name: "used token comes back", token: "first", wantStatus: http.StatusUnauthorized},
+		{name: "latest token of the revoked session", token: "third", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, errMsg := us.RefreshTokenService(ctx, domain.RefreshTokenRequest{RefreshToken: tokens[tt.token]})
+			status := http.StatusOK
+			if errMsg != nil {
+				status = errMsg.Status()
+			}
+			if status != tt.wantStatus {
+				t.Fatalf("RefreshTokenService() status = %d, want %d", status, tt.wantStatus)
+			}
+			if errMsg == nil {
+				if response.RefreshToken == tokens[tt.token] {
+					t.Errorf("refresh token wasn't rotated")
+				}
+				tokens[tt.next] = response.RefreshToken
+			}
+		})
+	}
+
+	expired := createTestStaffSession(t, db, cashier.ID, -time.Hour)
+	_, errMsg := us.RefreshTokenService(ctx, domain.RefreshTokenRequest{RefreshToken: expired})
+	if errMsg == nil || errMsg.Status() != http.StatusUnauthorized {
+		t.Errorf("RefreshTokenService() refreshed an expired token")
+	}
+}
+
+func enableTestTwoFactor(t *testing.T, db *sql.DB, twoFactorRepository repository.TwoFactorRepository, userAdminID string) (string, []string) {
+	t.Helper()
+
+	secret, err := domain.NewTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recoveryCodes, codeHashes, err := domain.NewRecoveryCodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	now := time.Now()
+	withTestTx(t, db, func(tx *sql.Tx) error {
+		err := twoFactorRepository.SetStaffTwoFactorPendingSecret(ctx, tx, userAdminID, secret)
+		if err != nil {
+			return err
+		}
+		err = twoFactorRepository.EnableStaffTwoFactor(ctx, tx, userAdminID, now, 0)
+		if err != nil {
+			return err
+		}
+
+		return twoFactorRepository.ReplaceStaffRecoveryCodes(ctx, tx, userAdminID, codeHashes, now)
+	})
+
+	return secret, recoveryCodes
+}
+
+func TestUpdateUserAdminRole(t *testing.T) {
+	db := newTestDB(t)
+	us := newTestUserAdminService(db)
+
+	tests := []struct {
+		name        string
+		self        bool
+		unknown     bool
+		role        string
+		wantStatus  int
+		wantRevoked bool
+	}{
+		{name: "promote a cashier", role: domain.UserAdminRoleManager, wantStatus: http.StatusOK, wantRevoked: true},
+		{name: "promote to a role with two-factor", role: domain.UserAdminRoleOwner, wantStatus: http.StatusOK, wantRevoked: true},
+		{name: "demote themselves", self: true, role: domain.UserAdminRoleCashier, wantStatus: http.StatusForbidden},
+		{name: "unknown staff", unknown: true, role: domain.UserAdminRoleManager, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			owner := createTestUserAdmin(t, db, domain.UserAdminRoleOwner)
+			target := createTestUserAdmin(t, db, domain.UserAdminRoleCashier)
+			id := target.ID
+			if tt.self {
+				target = owner
+				id = owner.ID
+			}
+			if tt.unknown {
+				id = uuid.NewString()
+			}
+			createTestStaffSession(t, db, target.ID, time.Hour)
+
+			response, errMsg := us.UpdateUserAdminRole(ctx, id, owner.ID, domain.UserAdminRoleRequest{Role: tt.role})
+			status := http.StatusOK
+			if errMsg != nil {
+				status = errMsg.Status()
+			}
+			if status != tt.wantStatus {
+				t.Fatalf("UpdateUserAdminRole() status = %d, want %d", status, tt.wantStatus)
+			}
+			if errMsg == nil && response.Role != tt.role {
+				t.Errorf("role = %s, want %s", response.Role, tt.role)
+			}
+
+			var open int
+			err := db.QueryRow(`SELECT count(*) FROM staff_sessions WHERE user_admin_id = $1 AND revoked_at IS NULL`, target.ID).Scan(&open)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if revoked := open == 0; revoked != tt.wantRevoked {
+				t.Errorf("sessions revoked = %v, want %v", revoked, tt.wantRevoked)
+			}
+		})
+	}
+}
+
+func TestLoginUserAdminThrottle(t *testing.T) {
+	db := newTestDB(t)
+	loginAttemptRepository := repository.NewLoginAttemptRepository()
+	us := newTestUserAdminService(db)
+
+	tests := []struct {
+		name            string
+		accountFailures int
+		ipFailures      int
+		wantStatus      int
+	}{
+		{name: "no failures", wantStatus: http.StatusOK},
+		{name: "a few failures", accountFailures: domain.LoginDelayAfterFailures - 1, wantStatus: http.StatusOK},
+		{name: "account locked out", accountFailures: domain.AccountLockoutFailures, wantStatus: http.StatusTooManyRequests},
+		{name: "ip locked out", ipFailures: domain.IPLockoutFailures, wantStatus: http.StatusTooManyRequests},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			cashier := createTestUserAdmin(t, db, domain.UserAdminRoleCashier)
+			body := domain.LoginUserAdmin{PhoneNumber: cashier.PhoneNumber, Password: "password", IP: uuid.NewString(), UserAgent: "test"}
+
+			withTestTx(t, db, func(tx *sql.Tx) error {
+				for i := 0; i < tt.accountFailures; i++ {
+					err := loginAttemptRepository.CreateLoginAttempt(ctx, tx, domain.NewLoginAttempt(body, &cashier.ID, domain.LoginAttemptInvalidCredentials))
+					if err != nil {
+						return err
+					}
+				}
+				for i := 0; i < tt.ipFailures; i++ {
+					other := body
+					other.PhoneNumber = testPhoneNumber()
+					err := loginAttemptRepository.CreateLoginAttempt(ctx, tx, domain.NewLoginAttempt(other, nil, domain.LoginAttemptInvalidCredentials))
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+
+			_, _, errMsg := us.LoginUserAdminService(ctx, body)
+			status := http.StatusOK
+			if errMsg != nil {
+				status = errMsg.Status()
+			}
+			if status != tt.wantStatus {
+				t.Errorf("LoginUserAdminService() status = %d, want %d", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestLoginUserAdminTwoFactorChallenge(t *testing.T) {
+	db := newTestDB(t)
+	us := newTestUserAdminService(db)
+
+	tests := []struct {
+		name          string
+		role          string
+		twoFactor     bool
+		wantChallenge string
+	}{
+		{name: "role without two-factor", role: domain.UserAdminRoleCashier},
+		{name: "two-factor set up", role: domain.UserAdminRoleCashier, twoFactor: true, wantChallenge: domain.LoginChallengeVerify},
+		{name: "role with two-factor not set up", role: domain.UserAdminRoleOwner, wantChallenge: domain.LoginChallengeEnrol},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			userAdmin := createTestUserAdmin(t, db, tt.role)
+			if tt.twoFactor {
+				enableTestTwoFactor(t, db, repository.NewTwoFactorRepository(), userAdmin.ID)
+			}
+
+			response, challenge, errMsg := us.LoginUserAdminService(ctx, domain.LoginUserAdmin{PhoneNumber: userAdmin.PhoneNumber, Password: "password", IP: uuid.NewString(), UserAgent: "test"})
+			if errMsg != nil {
+				t.Fatalf("LoginUserAdminService() error = %v", errMsg.Message())
+			}
+			if len(tt.wantChallenge) == 0 {
+				if challenge != nil || response == nil || len(response.AccessToken) == 0 {
+					t.Fatalf("LoginUserAdminService() = %+v, %+v, want a session", response, challenge)
+				}
+				return
+			}
+
+			if response != nil {
+				t.Errorf("LoginUserAdminService() returned %+v before the second factor", response)
+			}
+			if challenge == nil || challenge.TwoFactor != tt.wantChallenge || len(challenge.ChallengeToken) == 0 {
+				t.Errorf("challenge = %+v, want %s", challenge, tt.wantChallenge)
+			}
+		})
+	}
+}
+
+func TestRegisterUserAdminInvitation(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+	us := newTestUserAdminService(db)
+	owner := createTestUserAdmin(t, db, domain.UserAdminRoleOwner)
+	manager := createTestUserAdmin(t, db, domain.UserAdminRoleManager)
+
+	invite := func(role string) (string, string) {
+		invitation, errMsg := us.CreateStaffInvitation(ctx, domain.StaffInvitationRequest{UserAdminID: owner.ID, UserAdminRole: owner.Role, Role: role})
+		if errMsg != nil {
+			t.Fatalf("CreateStaffInvitation() error = %v", errMsg.Message())
+		}
+		return invitation.ID, invitation.Token
+	}
+	_, usedToken := invite(domain.UserAdminRoleCashier)
+	revokedID, revokedToken := invite(domain.UserAdminRoleCashier)
+	if errMsg := us.RevokeStaffInvitation(ctx, revokedID); errMsg != nil {
+		t.Fatalf("RevokeStaffInvitation() error = %v", errMsg.Message())
+	}
+	_, managerToken := invite(domain.UserAdminRoleManager)
+
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+		wantRole   string
+	}{
+		{name: "no invitation", wantStatus: http.StatusForbidden},
+		{name: "unknown invitation", token: "abcdef", wantStatus: http.StatusForbidden},
+		{name: "cashier invitation", token: usedToken, wantStatus: http.StatusOK, wantRole: domain.UserAdminRoleCashier},
+		{name: "invitation used twice", token: usedToken, wantStatus: http.StatusForbidden},
+		{name: "revoked invitation", token: revokedToken, wantStatus: http.StatusForbidden},
+		{name: "manager invitation", token: managerToken, wantStatus: http.StatusOK, wantRole: domain.UserAdminRoleManager},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, errMsg := us.RegisterUserAdminService(ctx, domain.RegisterUserAdminRequest{
+				Name:            "Test Staff",
+				PhoneNumber:     testPhoneNumber(),
+				Password:        "password",
+				InvitationToken: tt.token,
+			})
+			status := http.StatusOK
+			if errMsg != nil {
+				status = errMsg.Status()
+			}
+			if status != tt.wantStatus {
+				t.Fatalf("RegisterUserAdminService() status = %d, want %d", status, tt.wantStatus)
+			}
+			if errMsg == nil && response.Role != tt.wantRole {
+				t.Errorf("role = %s, want %s", response.Role, tt.wantRole)
+			}
+		})
+	}
+
+	_, errMsg := us.CreateStaffInvitation(ctx, domain.StaffInvitationRequest{UserAdminID: manager.ID, UserAdminRole: manager.Role, Role: domain.UserAdminRoleOwner})
+	if errMsg == nil || errMsg.Status() != http.StatusForbidden {
+		t.Errorf("a manager invited an owner")
+	}
+}
@@ -0,0 +1,19 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+  id uuid PRIMARY KEY,
+  sid serial,
+  key varchar NOT NULL UNIQUE,
+  request_hash varchar NOT NULL,
+  response_status int NOT NULL,
+  response_body jsonb NOT NULL,
+  checkout_id uuid,
+  created_at timestamptz NOT NULL,
+  expires_at timestamptz NOT NULL
+);
+
+ALTER TABLE idempotency_keys ADD CONSTRAINT fk_checkout_id_idempotency_keys FOREIGN KEY (checkout_id) REFERENCES checkouts (id);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE checkouts DROP COLUMN IF EXISTS total;
+
+ALTER TABLE product_checkouts DROP COLUMN IF EXISTS price;
+ALTER TABLE product_checkouts DROP COLUMN IF EXISTS product_sku;
+ALTER TABLE product_checkouts DROP COLUMN IF EXISTS product_name;
+
+COMMIT;
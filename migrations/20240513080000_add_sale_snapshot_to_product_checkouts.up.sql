@@ -0,0 +1,29 @@
+BEGIN;
+
+ALTER TABLE product_checkouts ADD COLUMN IF NOT EXISTS product_name varchar;
+ALTER TABLE product_checkouts ADD COLUMN IF NOT EXISTS product_sku varchar;
+ALTER TABLE product_checkouts ADD COLUMN IF NOT EXISTS price int;
+
+UPDATE product_checkouts pc
+SET product_name = p.name,
+  product_sku = p.sku,
+  price = p.price
+FROM products p
+WHERE p.id = pc.product_id;
+
+ALTER TABLE product_checkouts ALTER COLUMN product_name SET NOT NULL;
+ALTER TABLE product_checkouts ALTER COLUMN product_sku SET NOT NULL;
+ALTER TABLE product_checkouts ALTER COLUMN price SET NOT NULL;
+
+ALTER TABLE checkouts ADD COLUMN IF NOT EXISTS total int;
+
+UPDATE checkouts c
+SET total = COALESCE((
+  SELECT SUM(pc.price * pc.quantity)
+  FROM product_checkouts pc
+  WHERE pc.checkout_id = c.id
+), 0);
+
+ALTER TABLE checkouts ALTER COLUMN total SET NOT NULL;
+
+COMMIT;
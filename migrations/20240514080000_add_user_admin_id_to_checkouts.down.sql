@@ -0,0 +1 @@
+ALTER TABLE checkouts DROP COLUMN IF EXISTS user_admin_id;
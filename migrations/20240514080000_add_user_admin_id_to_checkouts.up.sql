@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE checkouts ADD COLUMN IF NOT EXISTS user_admin_id uuid;
+
+ALTER TABLE checkouts ADD CONSTRAINT fk_user_admin_id_checkouts FOREIGN KEY (user_admin_id) REFERENCES user_admins (id);
+
+CREATE INDEX IF NOT EXISTS idx_checkouts_user_admin_id ON checkouts (user_admin_id);
+
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+
+ALTER TABLE products DROP COLUMN IF EXISTS damaged_stock;
+
+ALTER TABLE product_checkouts DROP COLUMN IF EXISTS restock_state;
+
+DELETE FROM product_checkouts pc
+USING checkouts c
+WHERE c.id = pc.checkout_id
+  AND c.type = 'return';
+DELETE FROM checkouts WHERE type = 'return';
+
+ALTER TABLE checkouts DROP COLUMN IF EXISTS return_reason;
+ALTER TABLE checkouts DROP COLUMN IF EXISTS return_of;
+ALTER TABLE checkouts DROP COLUMN IF EXISTS type;
+
+COMMIT;
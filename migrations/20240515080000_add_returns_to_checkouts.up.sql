@@ -0,0 +1,15 @@
+BEGIN;
+
+ALTER TABLE checkouts ADD COLUMN IF NOT EXISTS type varchar NOT NULL DEFAULT 'sale';
+ALTER TABLE checkouts ADD COLUMN IF NOT EXISTS return_of uuid;
+ALTER TABLE checkouts ADD COLUMN IF NOT EXISTS return_reason varchar;
+
+ALTER TABLE checkouts ADD CONSTRAINT fk_return_of_checkouts FOREIGN KEY (return_of) REFERENCES checkouts (id);
+
+CREATE INDEX IF NOT EXISTS idx_checkouts_return_of ON checkouts (return_of);
+
+ALTER TABLE product_checkouts ADD COLUMN IF NOT EXISTS restock_state varchar;
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS damaged_stock int NOT NULL DEFAULT 0;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE checkouts DROP COLUMN IF EXISTS void_reason;
+ALTER TABLE checkouts DROP COLUMN IF EXISTS voided_by;
+ALTER TABLE checkouts DROP COLUMN IF EXISTS voided_at;
+
+COMMIT;
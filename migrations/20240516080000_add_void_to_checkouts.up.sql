@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE checkouts ADD COLUMN IF NOT EXISTS voided_at timestamptz;
+ALTER TABLE checkouts ADD COLUMN IF NOT EXISTS voided_by uuid;
+ALTER TABLE checkouts ADD COLUMN IF NOT EXISTS void_reason varchar;
+
+ALTER TABLE checkouts ADD CONSTRAINT fk_voided_by_checkouts FOREIGN KEY (voided_by) REFERENCES user_admins (id);
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS payments (
+  id uuid PRIMARY KEY,
+  sid serial,
+  checkout_id uuid NOT NULL,
+  method varchar NOT NULL,
+  amount int NOT NULL,
+  created_at timestamptz NOT NULL
+);
+
+ALTER TABLE payments ADD CONSTRAINT fk_checkout_id_payments FOREIGN KEY (checkout_id) REFERENCES checkouts (id);
+
+CREATE INDEX IF NOT EXISTS idx_payments_checkout_id ON payments (checkout_id);
+
+INSERT INTO payments (id, checkout_id, method, amount, created_at)
+SELECT gen_random_uuid(), c.id, 'cash', c.paid, COALESCE(c.created_at, now())
+FROM checkouts c;
+
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+
+ALTER TABLE checkouts DROP COLUMN IF EXISTS tax;
+ALTER TABLE checkouts DROP COLUMN IF EXISTS subtotal;
+
+ALTER TABLE product_checkouts DROP COLUMN IF EXISTS total;
+ALTER TABLE product_checkouts DROP COLUMN IF EXISTS tax_inclusive;
+ALTER TABLE product_checkouts DROP COLUMN IF EXISTS tax_rate;
+ALTER TABLE product_checkouts DROP COLUMN IF EXISTS tax;
+
+DROP TABLE IF EXISTS tax_rates;
+
+COMMIT;
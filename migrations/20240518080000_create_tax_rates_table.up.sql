@@ -0,0 +1,36 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS tax_rates (
+  id uuid PRIMARY KEY,
+  sid serial,
+  name varchar NOT NULL,
+  category varchar,
+  product_id uuid,
+  rate numeric NOT NULL,
+  inclusive bool NOT NULL,
+  created_at timestamptz NOT NULL,
+  CHECK ((category IS NULL) <> (product_id IS NULL))
+);
+
+ALTER TABLE tax_rates ADD CONSTRAINT fk_product_id_tax_rates FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_tax_rates_category ON tax_rates (category) WHERE category IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_tax_rates_product_id ON tax_rates (product_id) WHERE product_id IS NOT NULL;
+
+ALTER TABLE product_checkouts ADD COLUMN IF NOT EXISTS tax int NOT NULL DEFAULT 0;
+ALTER TABLE product_checkouts ADD COLUMN IF NOT EXISTS tax_rate numeric NOT NULL DEFAULT 0;
+ALTER TABLE product_checkouts ADD COLUMN IF NOT EXISTS tax_inclusive bool NOT NULL DEFAULT false;
+ALTER TABLE product_checkouts ADD COLUMN IF NOT EXISTS total int;
+
+UPDATE product_checkouts SET total = price * quantity;
+
+ALTER TABLE product_checkouts ALTER COLUMN total SET NOT NULL;
+
+ALTER TABLE checkouts ADD COLUMN IF NOT EXISTS subtotal int;
+ALTER TABLE checkouts ADD COLUMN IF NOT EXISTS tax int NOT NULL DEFAULT 0;
+
+UPDATE checkouts SET subtotal = total;
+
+ALTER TABLE checkouts ALTER COLUMN subtotal SET NOT NULL;
+
+COMMIT;
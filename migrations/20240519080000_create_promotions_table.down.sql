@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP TABLE IF EXISTS product_checkout_promotions;
+
+ALTER TABLE checkouts DROP COLUMN IF EXISTS discount;
+ALTER TABLE product_checkouts DROP COLUMN IF EXISTS discount;
+
+DROP TABLE IF EXISTS promotions;
+
+COMMIT;
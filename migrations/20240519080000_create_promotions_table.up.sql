@@ -0,0 +1,46 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS promotions (
+  id uuid PRIMARY KEY,
+  sid serial,
+  name varchar NOT NULL,
+  scope varchar NOT NULL,
+  product_id uuid,
+  category varchar,
+  type varchar NOT NULL,
+  value int NOT NULL,
+  buy_quantity int NOT NULL DEFAULT 0,
+  get_quantity int NOT NULL DEFAULT 0,
+  starts_at timestamptz,
+  ends_at timestamptz,
+  start_hour int,
+  end_hour int,
+  priority int NOT NULL DEFAULT 0,
+  stackable bool NOT NULL,
+  is_active bool NOT NULL,
+  created_at timestamptz NOT NULL
+);
+
+ALTER TABLE promotions ADD CONSTRAINT fk_product_id_promotions FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_promotions_is_active ON promotions (is_active);
+
+ALTER TABLE product_checkouts ADD COLUMN IF NOT EXISTS discount int NOT NULL DEFAULT 0;
+ALTER TABLE checkouts ADD COLUMN IF NOT EXISTS discount int NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS product_checkout_promotions (
+  id uuid PRIMARY KEY,
+  sid serial,
+  product_checkout_id uuid NOT NULL,
+  promotion_id uuid,
+  promotion_name varchar NOT NULL,
+  discount int NOT NULL
+);
+
+ALTER TABLE product_checkout_promotions ADD CONSTRAINT fk_product_checkout_id_product_checkout_promotions FOREIGN KEY (product_checkout_id) REFERENCES product_checkouts (id) ON DELETE CASCADE;
+ALTER TABLE product_checkout_promotions ADD CONSTRAINT fk_promotion_id_product_checkout_promotions FOREIGN KEY (promotion_id) REFERENCES promotions (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_product_checkout_promotions_product_checkout_id ON product_checkout_promotions (product_checkout_id);
+CREATE INDEX IF NOT EXISTS idx_product_checkout_promotions_promotion_id ON product_checkout_promotions (promotion_id);
+
+COMMIT;
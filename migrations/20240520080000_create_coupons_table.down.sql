@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE product_checkout_promotions DROP COLUMN IF EXISTS coupon_id;
+
+DROP TABLE IF EXISTS coupon_redemptions;
+DROP TABLE IF EXISTS coupons;
+
+COMMIT;
@@ -0,0 +1,38 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS coupons (
+  id uuid PRIMARY KEY,
+  sid serial,
+  code varchar NOT NULL UNIQUE,
+  type varchar NOT NULL,
+  value int NOT NULL,
+  starts_at timestamptz,
+  ends_at timestamptz,
+  max_redemptions int,
+  max_redemptions_per_customer int,
+  redemption_count int NOT NULL DEFAULT 0,
+  is_active bool NOT NULL,
+  created_at timestamptz NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS coupon_redemptions (
+  id uuid PRIMARY KEY,
+  sid serial,
+  coupon_id uuid NOT NULL,
+  checkout_id uuid NOT NULL UNIQUE,
+  user_customer_id uuid NOT NULL,
+  discount int NOT NULL,
+  created_at timestamptz NOT NULL
+);
+
+ALTER TABLE coupon_redemptions ADD CONSTRAINT fk_coupon_id_coupon_redemptions FOREIGN KEY (coupon_id) REFERENCES coupons (id);
+ALTER TABLE coupon_redemptions ADD CONSTRAINT fk_checkout_id_coupon_redemptions FOREIGN KEY (checkout_id) REFERENCES checkouts (id);
+ALTER TABLE coupon_redemptions ADD CONSTRAINT fk_user_customer_id_coupon_redemptions FOREIGN KEY (user_customer_id) REFERENCES user_customers (id);
+
+CREATE INDEX IF NOT EXISTS idx_coupon_redemptions_coupon_id_user_customer_id ON coupon_redemptions (coupon_id, user_customer_id);
+
+ALTER TABLE product_checkout_promotions ADD COLUMN IF NOT EXISTS coupon_id uuid;
+
+ALTER TABLE product_checkout_promotions ADD CONSTRAINT fk_coupon_id_product_checkout_promotions FOREIGN KEY (coupon_id) REFERENCES coupons (id);
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS points_entries (
+  id uuid PRIMARY KEY,
+  sid serial,
+  user_customer_id uuid NOT NULL,
+  checkout_id uuid,
+  type varchar NOT NULL,
+  points int NOT NULL,
+  created_at timestamptz NOT NULL
+);
+
+ALTER TABLE points_entries ADD CONSTRAINT fk_user_customer_id_points_entries FOREIGN KEY (user_customer_id) REFERENCES user_customers (id);
+ALTER TABLE points_entries ADD CONSTRAINT fk_checkout_id_points_entries FOREIGN KEY (checkout_id) REFERENCES checkouts (id);
+
+CREATE INDEX IF NOT EXISTS idx_points_entries_user_customer_id ON points_entries (user_customer_id);
+CREATE INDEX IF NOT EXISTS idx_points_entries_checkout_id ON points_entries (checkout_id);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS credit_entries;
+
+ALTER TABLE user_customers DROP COLUMN IF EXISTS credit_limit;
+
+COMMIT;
@@ -0,0 +1,24 @@
+BEGIN;
+
+ALTER TABLE user_customers ADD COLUMN IF NOT EXISTS credit_limit int NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS credit_entries (
+  id uuid PRIMARY KEY,
+  sid serial,
+  user_customer_id uuid NOT NULL,
+  checkout_id uuid,
+  user_admin_id uuid,
+  type varchar NOT NULL,
+  method varchar,
+  amount int NOT NULL,
+  created_at timestamptz NOT NULL
+);
+
+ALTER TABLE credit_entries ADD CONSTRAINT fk_user_customer_id_credit_entries FOREIGN KEY (user_customer_id) REFERENCES user_customers (id);
+ALTER TABLE credit_entries ADD CONSTRAINT fk_checkout_id_credit_entries FOREIGN KEY (checkout_id) REFERENCES checkouts (id);
+ALTER TABLE credit_entries ADD CONSTRAINT fk_user_admin_id_credit_entries FOREIGN KEY (user_admin_id) REFERENCES user_admins (id);
+
+CREATE INDEX IF NOT EXISTS idx_credit_entries_user_customer_id ON credit_entries (user_customer_id);
+CREATE INDEX IF NOT EXISTS idx_credit_entries_checkout_id ON credit_entries (checkout_id);
+
+COMMIT;
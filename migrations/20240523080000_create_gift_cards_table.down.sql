@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE payments DROP COLUMN IF EXISTS gift_card_id;
+
+DROP TABLE IF EXISTS gift_card_entries;
+DROP TABLE IF EXISTS gift_cards;
+
+COMMIT;
@@ -0,0 +1,35 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS gift_cards (
+  id uuid PRIMARY KEY,
+  sid serial,
+  code varchar(30) NOT NULL UNIQUE,
+  checkout_id uuid NOT NULL,
+  initial_balance int NOT NULL,
+  status varchar NOT NULL DEFAULT 'active',
+  expires_at timestamptz,
+  created_at timestamptz NOT NULL
+);
+
+ALTER TABLE gift_cards ADD CONSTRAINT fk_checkout_id_gift_cards FOREIGN KEY (checkout_id) REFERENCES checkouts (id);
+
+CREATE TABLE IF NOT EXISTS gift_card_entries (
+  id uuid PRIMARY KEY,
+  sid serial,
+  gift_card_id uuid NOT NULL,
+  checkout_id uuid,
+  type varchar NOT NULL,
+  amount int NOT NULL,
+  created_at timestamptz NOT NULL
+);
+
+ALTER TABLE gift_card_entries ADD CONSTRAINT fk_gift_card_id_gift_card_entries FOREIGN KEY (gift_card_id) REFERENCES gift_cards (id);
+ALTER TABLE gift_card_entries ADD CONSTRAINT fk_checkout_id_gift_card_entries FOREIGN KEY (checkout_id) REFERENCES checkouts (id);
+
+CREATE INDEX IF NOT EXISTS idx_gift_card_entries_gift_card_id ON gift_card_entries (gift_card_id);
+CREATE INDEX IF NOT EXISTS idx_gift_card_entries_checkout_id ON gift_card_entries (checkout_id);
+
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS gift_card_id uuid;
+ALTER TABLE payments ADD CONSTRAINT fk_gift_card_id_payments FOREIGN KEY (gift_card_id) REFERENCES gift_cards (id);
+
+COMMIT;
@@ -0,0 +1,36 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS carts (
+  id uuid PRIMARY KEY,
+  sid serial,
+  till varchar(50) NOT NULL,
+  label varchar(100),
+  user_customer_id uuid,
+  user_admin_id uuid NOT NULL,
+  status varchar NOT NULL DEFAULT 'open',
+  checkout_id uuid,
+  created_at timestamptz NOT NULL,
+  updated_at timestamptz NOT NULL
+);
+
+ALTER TABLE carts ADD CONSTRAINT fk_user_customer_id_carts FOREIGN KEY (user_customer_id) REFERENCES user_customers (id);
+ALTER TABLE carts ADD CONSTRAINT fk_user_admin_id_carts FOREIGN KEY (user_admin_id) REFERENCES user_admins (id);
+ALTER TABLE carts ADD CONSTRAINT fk_checkout_id_carts FOREIGN KEY (checkout_id) REFERENCES checkouts (id);
+
+CREATE INDEX IF NOT EXISTS idx_carts_till_status ON carts (till, status);
+CREATE INDEX IF NOT EXISTS idx_carts_updated_at ON carts (updated_at);
+
+CREATE TABLE IF NOT EXISTS cart_items (
+  id uuid PRIMARY KEY,
+  sid serial,
+  cart_id uuid NOT NULL,
+  product_id uuid NOT NULL,
+  quantity int NOT NULL,
+  created_at timestamptz NOT NULL,
+  UNIQUE (cart_id, product_id)
+);
+
+ALTER TABLE cart_items ADD CONSTRAINT fk_cart_id_cart_items FOREIGN KEY (cart_id) REFERENCES carts (id) ON DELETE CASCADE;
+ALTER TABLE cart_items ADD CONSTRAINT fk_product_id_cart_items FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE CASCADE;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE credit_entries DROP COLUMN IF EXISTS shift_id;
+ALTER TABLE checkouts DROP COLUMN IF EXISTS shift_id;
+
+DROP TABLE IF EXISTS cash_payouts;
+DROP TABLE IF EXISTS shifts;
+
+COMMIT;
@@ -0,0 +1,45 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS shifts (
+  id uuid PRIMARY KEY,
+  sid serial,
+  user_admin_id uuid NOT NULL,
+  till varchar(50) NOT NULL,
+  opening_float int NOT NULL,
+  opened_at timestamptz NOT NULL,
+  closed_at timestamptz,
+  expected_cash int,
+  counted_cash int,
+  denominations jsonb,
+  tenders jsonb,
+  notes varchar(255)
+);
+
+ALTER TABLE shifts ADD CONSTRAINT fk_user_admin_id_shifts FOREIGN KEY (user_admin_id) REFERENCES user_admins (id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_shifts_user_admin_id_open ON shifts (user_admin_id) WHERE closed_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS cash_payouts (
+  id uuid PRIMARY KEY,
+  sid serial,
+  shift_id uuid NOT NULL,
+  user_admin_id uuid NOT NULL,
+  amount int NOT NULL,
+  reason varchar(255) NOT NULL,
+  created_at timestamptz NOT NULL
+);
+
+ALTER TABLE cash_payouts ADD CONSTRAINT fk_shift_id_cash_payouts FOREIGN KEY (shift_id) REFERENCES shifts (id);
+ALTER TABLE cash_payouts ADD CONSTRAINT fk_user_admin_id_cash_payouts FOREIGN KEY (user_admin_id) REFERENCES user_admins (id);
+
+CREATE INDEX IF NOT EXISTS idx_cash_payouts_shift_id ON cash_payouts (shift_id);
+
+ALTER TABLE checkouts ADD COLUMN IF NOT EXISTS shift_id uuid;
+ALTER TABLE checkouts ADD CONSTRAINT fk_shift_id_checkouts FOREIGN KEY (shift_id) REFERENCES shifts (id);
+CREATE INDEX IF NOT EXISTS idx_checkouts_shift_id ON checkouts (shift_id);
+
+ALTER TABLE credit_entries ADD COLUMN IF NOT EXISTS shift_id uuid;
+ALTER TABLE credit_entries ADD CONSTRAINT fk_shift_id_credit_entries FOREIGN KEY (shift_id) REFERENCES shifts (id);
+CREATE INDEX IF NOT EXISTS idx_credit_entries_shift_id ON credit_entries (shift_id);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_checkouts_created_at;
+
+DROP TABLE IF EXISTS z_reports;
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS z_reports (
+  date date PRIMARY KEY,
+  closed_at timestamptz NOT NULL,
+  user_admin_id uuid NOT NULL,
+  report jsonb NOT NULL
+);
+
+ALTER TABLE z_reports ADD CONSTRAINT fk_user_admin_id_z_reports FOREIGN KEY (user_admin_id) REFERENCES user_admins (id);
+
+CREATE INDEX IF NOT EXISTS idx_checkouts_created_at ON checkouts (created_at);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE user_admins DROP CONSTRAINT IF EXISTS chk_role_user_admins;
+
+UPDATE user_admins SET role = 'staff';
+
+COMMIT;
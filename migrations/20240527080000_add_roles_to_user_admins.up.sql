@@ -0,0 +1,11 @@
+BEGIN;
+
+UPDATE user_admins SET role = 'cashier' WHERE role NOT IN ('owner', 'manager', 'cashier', 'stock_clerk');
+
+UPDATE user_admins SET role = 'owner'
+WHERE id = (SELECT id FROM user_admins ORDER BY created_at, sid LIMIT 1)
+  AND NOT EXISTS (SELECT 1 FROM user_admins WHERE role = 'owner');
+
+ALTER TABLE user_admins ADD CONSTRAINT chk_role_user_admins CHECK (role IN ('owner', 'manager', 'cashier', 'stock_clerk'));
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS staff_invitations (
+  id uuid PRIMARY KEY,
+  sid serial,
+  created_at timestamptz NOT NULL,
+  created_by uuid NOT NULL,
+  token_hash varchar NOT NULL UNIQUE,
+  role varchar NOT NULL,
+  expires_at timestamptz NOT NULL,
+  revoked_at timestamptz,
+  used_at timestamptz,
+  used_by uuid
+);
+
+ALTER TABLE staff_invitations ADD CONSTRAINT fk_created_by_staff_invitations FOREIGN KEY (created_by) REFERENCES user_admins (id);
+ALTER TABLE staff_invitations ADD CONSTRAINT fk_used_by_staff_invitations FOREIGN KEY (used_by) REFERENCES user_admins (id);
+
+COMMIT;
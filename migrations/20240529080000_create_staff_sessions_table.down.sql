@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS staff_refresh_tokens;
+DROP TABLE IF EXISTS staff_sessions;
+
+COMMIT;
@@ -0,0 +1,28 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS staff_sessions (
+  id uuid PRIMARY KEY,
+  sid serial,
+  created_at timestamptz NOT NULL,
+  user_admin_id uuid NOT NULL,
+  revoked_at timestamptz
+);
+
+ALTER TABLE staff_sessions ADD CONSTRAINT fk_user_admin_id_staff_sessions FOREIGN KEY (user_admin_id) REFERENCES user_admins (id);
+
+CREATE INDEX IF NOT EXISTS idx_staff_sessions_user_admin_id ON staff_sessions (user_admin_id);
+
+CREATE TABLE IF NOT EXISTS staff_refresh_tokens (
+  token_hash varchar PRIMARY KEY,
+  session_id uuid NOT NULL,
+  created_at timestamptz NOT NULL,
+  expires_at timestamptz NOT NULL,
+  used_at timestamptz
+);
+
+ALTER TABLE staff_refresh_tokens ADD CONSTRAINT fk_session_id_staff_refresh_tokens FOREIGN KEY (session_id) REFERENCES staff_sessions (id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_staff_refresh_tokens_session_id ON staff_refresh_tokens (session_id);
+CREATE INDEX IF NOT EXISTS idx_staff_refresh_tokens_expires_at ON staff_refresh_tokens (expires_at);
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS login_attempts (
+  id uuid PRIMARY KEY,
+  sid serial,
+  created_at timestamptz NOT NULL,
+  phone_number varchar NOT NULL,
+  ip varchar NOT NULL,
+  user_agent varchar NOT NULL,
+  user_admin_id uuid,
+  result varchar NOT NULL
+);
+
+ALTER TABLE login_attempts ADD CONSTRAINT fk_user_admin_id_login_attempts FOREIGN KEY (user_admin_id) REFERENCES user_admins (id);
+
+CREATE INDEX IF NOT EXISTS idx_login_attempts_phone_number_created_at ON login_attempts (phone_number, created_at);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_ip_created_at ON login_attempts (ip, created_at);
+
+COMMIT;
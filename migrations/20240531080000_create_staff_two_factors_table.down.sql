@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS login_challenges;
+DROP TABLE IF EXISTS staff_recovery_codes;
+DROP TABLE IF EXISTS staff_two_factors;
+
+COMMIT;
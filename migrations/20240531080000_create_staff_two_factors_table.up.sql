@@ -0,0 +1,37 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS staff_two_factors (
+  user_admin_id uuid PRIMARY KEY,
+  secret varchar,
+  pending_secret varchar,
+  enabled_at timestamptz,
+  last_step bigint NOT NULL DEFAULT 0
+);
+
+ALTER TABLE staff_two_factors ADD CONSTRAINT fk_user_admin_id_staff_two_factors FOREIGN KEY (user_admin_id) REFERENCES user_admins (id);
+
+CREATE TABLE IF NOT EXISTS staff_recovery_codes (
+  user_admin_id uuid NOT NULL,
+  code_hash varchar NOT NULL,
+  created_at timestamptz NOT NULL,
+  used_at timestamptz,
+  PRIMARY KEY (user_admin_id, code_hash)
+);
+
+ALTER TABLE staff_recovery_codes ADD CONSTRAINT fk_user_admin_id_staff_recovery_codes FOREIGN KEY (user_admin_id) REFERENCES user_admins (id);
+
+CREATE TABLE IF NOT EXISTS login_challenges (
+  token_hash varchar PRIMARY KEY,
+  user_admin_id uuid NOT NULL,
+  type varchar NOT NULL,
+  created_at timestamptz NOT NULL,
+  expires_at timestamptz NOT NULL,
+  failed_attempts int NOT NULL DEFAULT 0,
+  used_at timestamptz
+);
+
+ALTER TABLE login_challenges ADD CONSTRAINT fk_user_admin_id_login_challenges FOREIGN KEY (user_admin_id) REFERENCES user_admins (id);
+
+CREATE INDEX IF NOT EXISTS idx_login_challenges_expires_at ON login_challenges (expires_at);
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+ALTER TABLE idempotency_keys DROP CONSTRAINT IF EXISTS fk_user_admin_id_idempotency_keys;
+ALTER TABLE idempotency_keys DROP CONSTRAINT IF EXISTS idempotency_keys_user_admin_id_key_key;
+
+UPDATE idempotency_keys SET key = substr(key, length('client:') + 1) WHERE key LIKE 'client:%';
+
+DELETE FROM idempotency_keys ik
+USING idempotency_keys newer
+WHERE newer.key = ik.key AND newer.sid > ik.sid;
+
+ALTER TABLE idempotency_keys ADD CONSTRAINT idempotency_keys_key_key UNIQUE (key);
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS user_admin_id;
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS user_admin_id uuid;
+
+UPDATE idempotency_keys ik
+SET user_admin_id = c.user_admin_id
+FROM checkouts c
+WHERE c.id = ik.checkout_id;
+
+DELETE FROM idempotency_keys WHERE user_admin_id IS NULL;
+
+UPDATE idempotency_keys SET key = 'client:' || key WHERE key NOT LIKE 'cart:%';
+
+ALTER TABLE idempotency_keys ALTER COLUMN user_admin_id SET NOT NULL;
+ALTER TABLE idempotency_keys DROP CONSTRAINT IF EXISTS idempotency_keys_key_key;
+ALTER TABLE idempotency_keys ADD CONSTRAINT idempotency_keys_user_admin_id_key_key UNIQUE (user_admin_id, key);
+ALTER TABLE idempotency_keys ADD CONSTRAINT fk_user_admin_id_idempotency_keys FOREIGN KEY (user_admin_id) REFERENCES user_admins (id);
+
+COMMIT;